@@ -0,0 +1,88 @@
+package contextMigrations
+
+import (
+	"github.com/Layr-Labs/devkit-cli/pkg/migration"
+
+	"gopkg.in/yaml.v3"
+)
+
+func Migration_0_0_7_to_0_0_8(user, old, new *yaml.Node) (*yaml.Node, error) {
+	contextNode := migration.ResolveNode(user, []string{"context"})
+
+	// Replace the single component artifact with a list of components
+	if contextNode != nil && contextNode.Kind == yaml.MappingNode {
+		scalar := func(value string) *yaml.Node {
+			return &yaml.Node{Kind: yaml.ScalarNode, Value: value, Tag: "!!str"}
+		}
+
+		// Carry over the fields of the existing artifact section
+		values := map[string]string{}
+		artifactIndex := -1
+		var artifactNode *yaml.Node
+		for i := 0; i < len(contextNode.Content)-1; i += 2 {
+			if contextNode.Content[i].Value != "artifact" {
+				continue
+			}
+			artifactIndex = i + 1
+			artifactNode = contextNode.Content[artifactIndex]
+			for j := 0; artifactNode.Kind == yaml.MappingNode && j < len(artifactNode.Content)-1; j += 2 {
+				values[artifactNode.Content[j].Value] = artifactNode.Content[j+1].Value
+			}
+			break
+		}
+
+		// A built component becomes the first entry of the list
+		components := &yaml.Node{Kind: yaml.SequenceNode, Style: yaml.FlowStyle}
+		if values["component"] != "" {
+			components.Style = 0
+			components.Content = append(components.Content, &yaml.Node{
+				Kind: yaml.MappingNode,
+				Content: []*yaml.Node{
+					scalar("name"), scalar(values["component"]),
+					scalar("image"), scalar(values["component"]),
+					scalar("artifact_id"), scalar(values["artifactId"]),
+					scalar("digest"), scalar(values["digest"]),
+				},
+			})
+		}
+
+		newArtifactValue := &yaml.Node{
+			Kind: yaml.MappingNode,
+			Content: []*yaml.Node{
+				scalar("registry"), scalar(values["registry"]),
+				scalar("version"), scalar(values["version"]),
+			},
+		}
+		// Keys the single component layout did not know are kept as they are, e.g. ones added by hand or by a layer
+		for j := 0; artifactNode != nil && artifactNode.Kind == yaml.MappingNode && j < len(artifactNode.Content)-1; j += 2 {
+			switch artifactNode.Content[j].Value {
+			case "registry":
+				newArtifactValue.Content[1] = artifactNode.Content[j+1]
+			case "version":
+				newArtifactValue.Content[3] = artifactNode.Content[j+1]
+			case "artifactId", "component", "digest", "components":
+			default:
+				newArtifactValue.Content = append(newArtifactValue.Content, artifactNode.Content[j], artifactNode.Content[j+1])
+			}
+		}
+		newArtifactValue.Content = append(newArtifactValue.Content, scalar("components"), components)
+
+		if artifactIndex != -1 {
+			contextNode.Content[artifactIndex-1].HeadComment = "# Release artifacts, one component per image produced by `devkit avs build`"
+			contextNode.Content[artifactIndex] = newArtifactValue
+		} else {
+			artifactKey := &yaml.Node{
+				Kind:        yaml.ScalarNode,
+				Value:       "artifact",
+				HeadComment: "# Release artifacts, one component per image produced by `devkit avs build`",
+			}
+			contextNode.Content = append(contextNode.Content, artifactKey, newArtifactValue)
+		}
+	}
+
+	// Upgrade the version
+	if v := migration.ResolveNode(user, []string{"version"}); v != nil {
+		v.Value = "0.0.8"
+	}
+	return user, nil
+}
@@ -0,0 +1,38 @@
+package networks
+
+import _ "embed"
+
+// Set the latest address book version
+const LatestVersion = "0.0.1"
+
+// Networks with an embedded address book
+var Names = [...]string{
+	"holesky",
+	"sepolia",
+	"hoodi",
+	"mainnet",
+}
+
+// --
+// Address books
+// --
+
+//go:embed holesky.yaml
+var holesky []byte
+
+//go:embed sepolia.yaml
+var sepolia []byte
+
+//go:embed hoodi.yaml
+var hoodi []byte
+
+//go:embed mainnet.yaml
+var mainnet []byte
+
+// Map of network names to their EigenLayer address books
+var AddressBooks = map[string][]byte{
+	"holesky": holesky,
+	"sepolia": sepolia,
+	"hoodi":   hoodi,
+	"mainnet": mainnet,
+}
@@ -21,5 +21,6 @@ var AVSCommand = &cli.Command{
 		CallCommand,
 		ReleaseCommand,
 		template.Command,
+		MigrateCommand,
 	},
 }
@@ -1,10 +1,16 @@
 package commands
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
 	"path/filepath"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/Layr-Labs/devkit-cli/pkg/common"
+	"github.com/Layr-Labs/devkit-cli/pkg/common/iface"
 	"github.com/Layr-Labs/devkit-cli/pkg/testutils"
 	"gopkg.in/yaml.v3"
 
@@ -21,7 +27,20 @@ var BuildCommand = &cli.Command{
 			Usage: "devnet ,testnet or mainnet",
 			Value: "devnet",
 		},
+		&cli.StringFlag{
+			Name:  "version",
+			Usage: "Semver tag for the images (defaults to the next patch version after the last published release)",
+		},
+		&cli.StringFlag{
+			Name:  "bump",
+			Usage: "Derive the tag by bumping the last published version: major, minor or patch (default: patch). Use the same bump for release publish",
+		},
+		&cli.BoolFlag{
+			Name:  "force",
+			Usage: "Rebuild every component, ignoring the build cache",
+		},
 	}, common.GlobalFlags...),
+	Subcommands: []*cli.Command{buildVerifyCommand},
 	Action: func(cCtx *cli.Context) error {
 		logger := common.LoggerFromContext(cCtx.Context)
 
@@ -36,44 +55,49 @@ var BuildCommand = &cli.Command{
 			// Use test config from context
 			cfg = cfgValue.(*common.ConfigWithContextConfig)
 		} else {
-			// Load selected context
-			context := cCtx.String("context")
-
 			// Load from file if not in context
 			var err error
-			cfg, err = common.LoadConfigWithContextConfig(context)
+			cfg, err = common.LoadConfigWithContextConfig(cCtx.String("context"))
 			if err != nil {
 				return err
 			}
 		}
 
-		// Handle version increment
-		version := cfg.Context["devnet"].Artifact.Version
-		if version == "" {
-			version = "0"
+		// Tag images with the version the next release will publish
+		contextName := cCtx.String("context")
+		envCtx, ok := cfg.Context[contextName]
+		if !ok {
+			return fmt.Errorf("context '%s' not found in configuration", contextName)
+		}
+		var lastVersion string
+		if envCtx.Artifact != nil {
+			lastVersion = envCtx.Artifact.Version
+		}
+		version, err := buildImageVersion(lastVersion, cCtx.String("version"), cCtx.String("bump"))
+		if err != nil {
+			return err
 		}
 
 		logger.Debug("Project Name: %s", cfg.Config.Project.Name)
 		logger.Debug("Building AVS components...")
 
-		// All scripts contained here
-		scriptsDir := filepath.Join(".devkit", "scripts")
-
-		// Execute build via .devkit scripts with project name
-		output, err := common.CallTemplateScript(cCtx.Context, logger, dir, filepath.Join(scriptsDir, "build"), common.ExpectJSONResponse,
-			[]byte("--image"),
-			[]byte(cfg.Config.Project.Name),
-			[]byte("--tag"),
-			[]byte(version),
-		)
-		if err != nil {
-			logger.Error("Build script failed with error: %v", err)
-			return fmt.Errorf("build failed: %w", err)
+		var artifacts []buildOutputArtifact
+		if len(cfg.Config.Build.Components) == 0 {
+			// Execute build via .devkit scripts with project name
+			artifacts, err = runBuildScript(cCtx.Context, logger, dir, cfg.Config.Project.Name, version, "")
+			if err != nil {
+				return err
+			}
+		} else {
+			// Components declared in config.yaml are built one at a time, skipping those whose inputs are unchanged
+			artifacts, err = buildComponents(cCtx, cfg, contextName, version)
+			if err != nil {
+				return err
+			}
 		}
 
 		// Load the context yaml file
-		contextPath := filepath.Join("config", "contexts", fmt.Sprintf("%s.yaml", cCtx.String("context")))
-		contextNode, err := common.LoadYAML(contextPath)
+		contextPath, contextNode, _, err := common.LoadContext(contextName)
 		if err != nil {
 			return fmt.Errorf("failed to load context yaml: %w", err)
 		}
@@ -92,12 +116,12 @@ var BuildCommand = &cli.Command{
 		}
 
 		// Update artifact in context
-		if err := updateArtifactFromBuild(contextSection, output); err != nil {
+		if err := recordBuildArtifacts(contextSection, artifacts, version); err != nil {
 			return fmt.Errorf("failed to update artifact: %w", err)
 		}
 
 		// Write the merged yaml back to file
-		if err := common.WriteYAML(contextPath, contextNode); err != nil {
+		if err := common.WriteContext(contextPath, contextNode); err != nil {
 			return fmt.Errorf("failed to write merged yaml: %w", err)
 		}
 
@@ -106,12 +130,109 @@ var BuildCommand = &cli.Command{
 	},
 }
 
-// updateArtifactFromBuild updates the artifactId and component fields in the context yaml file
+// buildOutput is what the build script prints. Scripts list every image they built under artifacts,
+// older scripts report a single image under artifact.
+type buildOutput struct {
+	Artifacts []buildOutputArtifact `json:"artifacts"`
+	Artifact  *struct {
+		ArtifactId string `json:"artifactId"`
+		Component  string `json:"component"`
+	} `json:"artifact"`
+}
+
+// buildOutputArtifact is one image reported by the build script
+type buildOutputArtifact struct {
+	Name         string            `json:"name"`
+	Image        string            `json:"image"`
+	ArtifactId   string            `json:"artifactId"`
+	OperatorSets []uint32          `json:"operatorSets"`
+	Platforms    []string          `json:"platforms"`
+	Hashes       map[string]string `json:"hashes"`
+}
+
+// buildComponents runs the build script for each component declared in config.yaml whose inputs changed since its
+// last successful build, and reuses the cached output of the others unless --force is set
+func buildComponents(cCtx *cli.Context, cfg *common.ConfigWithContextConfig, contextName, version string) ([]buildOutputArtifact, error) {
+	logger := common.LoggerFromContext(cCtx.Context)
+	cachePath := buildCachePath(contextName)
+	cache := readBuildCache(cachePath)
+
+	var artifacts []buildOutputArtifact
+	var built, cached []string
+	for _, component := range cfg.Config.Build.Components {
+		hash, err := hashBuildInputs(".", component, version)
+		if err != nil {
+			return nil, err
+		}
+		if previous, ok := cache.lookup(component.Name, hash); ok && !cCtx.Bool("force") {
+			logger.Info("Component %s: cached, inputs unchanged since %s", component.Name, cache.Components[component.Name].Time.Format(time.RFC3339))
+			artifacts = append(artifacts, previous...)
+			cached = append(cached, component.Name)
+			continue
+		}
+
+		logger.Info("Component %s: building...", component.Name)
+		componentArtifacts, err := runBuildScript(cCtx.Context, logger, "", cfg.Config.Project.Name, version, component.Name)
+		if err != nil {
+			return nil, err
+		}
+		artifacts = append(artifacts, componentArtifacts...)
+		built = append(built, component.Name)
+
+		// Record each success right away so an interrupted build keeps what it finished
+		cache.Components[component.Name] = buildCacheEntry{Hash: hash, Time: time.Now().UTC(), Artifacts: componentArtifacts}
+		if err := cache.write(cachePath); err != nil {
+			return nil, err
+		}
+	}
+
+	logger.Info("Built %d components, %d cached", len(built), len(cached))
+	return artifacts, nil
+}
+
+// runBuildScript runs the project's build script in dir, for a single component when one is named, and returns
+// the artifacts it reports
+func runBuildScript(ctx context.Context, logger iface.Logger, dir, image, tag, component string) ([]buildOutputArtifact, error) {
+	params := [][]byte{[]byte("--image"), []byte(image), []byte("--tag"), []byte(tag)}
+	if component != "" {
+		params = append(params, []byte("--component"), []byte(component))
+	}
+
+	// All scripts contained here
+	scriptPath := filepath.Join(dir, ".devkit", "scripts", "build")
+	output, err := common.CallTemplateScript(ctx, logger, dir, scriptPath, common.ExpectJSONResponse, params...)
+	if err != nil {
+		logger.Error("Build script failed with error: %v", err)
+		if component != "" {
+			return nil, fmt.Errorf("build of component %s failed: %w", component, err)
+		}
+		return nil, fmt.Errorf("build failed: %w", err)
+	}
+	artifacts, err := decodeBuildOutput(output)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read build output: %w", err)
+	}
+	return artifacts, nil
+}
+
+// updateArtifactFromBuild records the components reported by the build script in the context's artifact section
 func updateArtifactFromBuild(contextSection *yaml.Node, buildOutput interface{}) error {
-	// Convert build output to map for easier access
-	outputMap, ok := buildOutput.(map[string]interface{})
-	if !ok {
-		return fmt.Errorf("build output is not a map")
+	artifacts, err := decodeBuildOutput(buildOutput)
+	if err != nil {
+		return err
+	}
+	return recordBuildArtifacts(contextSection, artifacts, "")
+}
+
+// recordBuildArtifacts writes the built artifacts as the components of the context's artifact section, along with
+// the version they were built for when known. Digests of earlier releases are kept for components that are built again.
+func recordBuildArtifacts(contextSection *yaml.Node, artifacts []buildOutputArtifact, version string) error {
+	components, err := buildComponentsFromArtifacts(artifacts)
+	if err != nil {
+		return err
+	}
+	if len(components) == 0 {
+		return nil
 	}
 
 	// Get or create artifact section
@@ -123,22 +244,121 @@ func updateArtifactFromBuild(contextSection *yaml.Node, buildOutput interface{})
 			artifactSection)
 	}
 
-	// Update artifact fields from build output
-	if artifact, ok := outputMap["artifact"].(map[string]interface{}); ok {
-		// Update artifactId if present
-		if artifactId, exists := artifact["artifactId"]; exists {
-			common.SetMappingValue(artifactSection,
-				&yaml.Node{Kind: yaml.ScalarNode, Value: "artifactId"},
-				&yaml.Node{Kind: yaml.ScalarNode, Value: artifactId.(string), Tag: "!!str"})
+	// Carry over the digests of components released before
+	var existing common.ArtifactConfig
+	if err := artifactSection.Decode(&existing); err != nil {
+		return fmt.Errorf("invalid artifact section: %w", err)
+	}
+	for i := range components {
+		for _, previous := range existing.ComponentList() {
+			if previous.Name == components[i].Name {
+				components[i].Digest = previous.Digest
+				components[i].Registry = previous.Registry
+			}
 		}
+	}
 
-		// Update component if present
-		if component, exists := artifact["component"]; exists {
-			common.SetMappingValue(artifactSection,
-				&yaml.Node{Kind: yaml.ScalarNode, Value: "component"},
-				&yaml.Node{Kind: yaml.ScalarNode, Value: component.(string), Tag: "!!str"})
-		}
+	componentsNode := &yaml.Node{}
+	if err := componentsNode.Encode(components); err != nil {
+		return fmt.Errorf("failed to encode artifact components: %w", err)
+	}
+	common.SetMappingValue(artifactSection,
+		&yaml.Node{Kind: yaml.ScalarNode, Value: "components"},
+		componentsNode)
+	if version != "" {
+		common.SetMappingValue(artifactSection,
+			&yaml.Node{Kind: yaml.ScalarNode, Value: "build_version"},
+			&yaml.Node{Kind: yaml.ScalarNode, Value: version})
 	}
 
+	// The single component fields of older contexts are replaced by the list
+	removeMappingKeys(artifactSection, "artifactId", "component", "digest")
 	return nil
 }
+
+// decodeBuildOutput reads the artifacts of a build script's output
+func decodeBuildOutput(scriptOutput interface{}) ([]buildOutputArtifact, error) {
+	// Convert build output to map for easier access
+	outputMap, ok := scriptOutput.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("build output is not a map")
+	}
+	raw, err := json.Marshal(outputMap)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read build output: %w", err)
+	}
+	var output buildOutput
+	if err := json.Unmarshal(raw, &output); err != nil {
+		return nil, fmt.Errorf("invalid build output: %w", err)
+	}
+
+	if len(output.Artifacts) == 0 && output.Artifact != nil && output.Artifact.Component != "" {
+		output.Artifacts = []buildOutputArtifact{{
+			Name:       output.Artifact.Component,
+			Image:      output.Artifact.Component,
+			ArtifactId: output.Artifact.ArtifactId,
+		}}
+	}
+	return output.Artifacts, nil
+}
+
+// buildComponentsFromArtifacts turns built artifacts into context components, one per name
+func buildComponentsFromArtifacts(artifacts []buildOutputArtifact) ([]common.ArtifactComponent, error) {
+	components := make([]common.ArtifactComponent, 0, len(artifacts))
+	seen := make(map[string]bool)
+	for i, artifact := range artifacts {
+		if artifact.Name == "" {
+			return nil, fmt.Errorf("build output artifact %d has no name", i+1)
+		}
+		if seen[artifact.Name] {
+			return nil, fmt.Errorf("build output lists component %q more than once", artifact.Name)
+		}
+		seen[artifact.Name] = true
+		if artifact.Image == "" {
+			artifact.Image = artifact.Name
+		}
+		components = append(components, common.ArtifactComponent{
+			Name:         artifact.Name,
+			Image:        artifact.Image,
+			ArtifactId:   artifact.ArtifactId,
+			OperatorSets: artifact.OperatorSets,
+			Platforms:    artifact.Platforms,
+			Hashes:       artifact.Hashes,
+		})
+	}
+	return components, nil
+}
+
+// removeMappingKeys drops keys and their values from a mapping node
+func removeMappingKeys(mapNode *yaml.Node, keys ...string) {
+	content := mapNode.Content[:0]
+	for i := 0; i+1 < len(mapNode.Content); i += 2 {
+		if slices.Contains(keys, mapNode.Content[i].Value) {
+			continue
+		}
+		content = append(content, mapNode.Content[i], mapNode.Content[i+1])
+	}
+	mapNode.Content = content
+}
+
+// buildImageVersion returns the semver tag for built images, requested when given and otherwise lastVersion bumped
+// by bump, the next patch version by default, which is what release publish picks for the same flags
+func buildImageVersion(lastVersion, requested, bump string) (string, error) {
+	if requested != "" && bump != "" {
+		return "", fmt.Errorf("--version and --bump cannot be used together")
+	}
+	if requested != "" {
+		if !common.IsSemver(requested) {
+			return "", fmt.Errorf("invalid --version %q, expected semver such as 1.4.0", requested)
+		}
+		return strings.TrimPrefix(requested, "v"), nil
+	}
+	last, err := normalizeReleaseVersion(lastVersion)
+	if err != nil {
+		return "", err
+	}
+	if bump == "" {
+		bump = "patch"
+	}
+	return common.BumpVersion(last, bump)
+}
@@ -0,0 +1,158 @@
+package commands
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Layr-Labs/devkit-cli/pkg/common"
+)
+
+// buildCachePath is where the outputs of the last successful build of each component are kept, relative to the project root
+func buildCachePath(contextName string) string {
+	return filepath.Join(".devkit", "cache", "build-"+contextName+".json")
+}
+
+// buildCache holds the last successful build of each component
+type buildCache struct {
+	Components map[string]buildCacheEntry `json:"components"`
+}
+
+// buildCacheEntry is a component's build output and the hash of the inputs and tag it was built from
+type buildCacheEntry struct {
+	Hash      string                `json:"hash"`
+	Time      time.Time             `json:"time"`
+	Artifacts []buildOutputArtifact `json:"artifacts"`
+}
+
+// readBuildCache loads the cache at path. A missing or unreadable cache is empty, it only means everything is rebuilt.
+func readBuildCache(path string) *buildCache {
+	cache := &buildCache{Components: make(map[string]buildCacheEntry)}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return cache
+	}
+	if err := json.Unmarshal(data, cache); err != nil || cache.Components == nil {
+		return &buildCache{Components: make(map[string]buildCacheEntry)}
+	}
+	return cache
+}
+
+// write stores the cache at path, creating its directory if needed
+func (c *buildCache) write(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create build cache directory: %w", err)
+	}
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode build cache: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write build cache: %w", err)
+	}
+	return nil
+}
+
+// hashBuildInputs hashes the path and content of every file below root matching the component's input globs,
+// along with the image tag, so a new version rebuilds the component
+func hashBuildInputs(root string, component common.BuildComponentConfig, tag string) (string, error) {
+	if len(component.Inputs) == 0 {
+		return "", fmt.Errorf("build component %q declares no inputs", component.Name)
+	}
+
+	var files []string
+	err := filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(root, p)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if d.IsDir() {
+			// Build outputs and VCS metadata never count as inputs
+			if rel == ".git" || rel == ".devkit" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		for _, pattern := range component.Inputs {
+			if matchBuildInput(pattern, rel) {
+				files = append(files, rel)
+				break
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to collect inputs of %s: %w", component.Name, err)
+	}
+	sort.Strings(files)
+
+	h := sha256.New()
+	fmt.Fprintf(h, "tag %s\n", tag)
+	for _, pattern := range component.Inputs {
+		fmt.Fprintf(h, "input %s\n", pattern)
+	}
+	for _, file := range files {
+		f, err := os.Open(filepath.Join(root, file))
+		if err != nil {
+			return "", fmt.Errorf("failed to read input %s: %w", file, err)
+		}
+		fmt.Fprintf(h, "file %s\n", file)
+		_, err = io.Copy(h, f)
+		f.Close()
+		if err != nil {
+			return "", fmt.Errorf("failed to read input %s: %w", file, err)
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// matchBuildInput reports whether the slash separated path matches pattern. A ** segment matches
+// any number of directories, a pattern naming a directory matches everything below it.
+func matchBuildInput(pattern, name string) bool {
+	pattern = strings.TrimSuffix(strings.TrimPrefix(filepath.ToSlash(pattern), "./"), "/")
+	return matchSegments(strings.Split(pattern, "/"), strings.Split(name, "/"))
+}
+
+func matchSegments(pattern, name []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			for i := 0; i <= len(name); i++ {
+				if matchSegments(pattern[1:], name[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(name) == 0 {
+			return false
+		}
+		if ok, err := path.Match(pattern[0], name[0]); err != nil || !ok {
+			return false
+		}
+		pattern, name = pattern[1:], name[1:]
+	}
+	// Everything below a matched directory is an input
+	return true
+}
+
+// lookup returns the cached artifacts of a component when it was last built from inputs hashing to hash
+func (c *buildCache) lookup(name, hash string) ([]buildOutputArtifact, bool) {
+	entry, ok := c.Components[name]
+	if !ok || entry.Hash != hash {
+		return nil, false
+	}
+	return entry.Artifacts, true
+}
@@ -0,0 +1,138 @@
+package commands
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Layr-Labs/devkit-cli/config/contexts"
+	"github.com/Layr-Labs/devkit-cli/pkg/common"
+	"github.com/Layr-Labs/devkit-cli/pkg/common/logger"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli/v2"
+)
+
+func TestMatchBuildInput(t *testing.T) {
+	tests := []struct {
+		pattern, name string
+		match         bool
+	}{
+		{"go.mod", "go.mod", true},
+		{"go.mod", "cmd/go.mod", false},
+		{"cmd/aggregator", "cmd/aggregator/main.go", true},
+		{"cmd/aggregator/", "cmd/aggregator/internal/task.go", true},
+		{"cmd/*/main.go", "cmd/executor/main.go", true},
+		{"cmd/*/main.go", "cmd/executor/internal/main.go", false},
+		{"**/*.go", "main.go", true},
+		{"**/*.go", "pkg/a/b/c.go", true},
+		{"**/*.go", "pkg/a/b/c.sol", false},
+		{"./contracts/src/**", "contracts/src/Task.sol", true},
+		{"contracts/src/**/*.sol", "contracts/test/Task.sol", false},
+	}
+	for _, tt := range tests {
+		require.Equal(t, tt.match, matchBuildInput(tt.pattern, tt.name), "%s %s", tt.pattern, tt.name)
+	}
+}
+
+func TestHashBuildInputs(t *testing.T) {
+	root := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "cmd", "aggregator"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "cmd", "aggregator", "main.go"), []byte("package main"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "README.md"), []byte("docs"), 0644))
+	component := common.BuildComponentConfig{Name: "aggregator", Inputs: []string{"cmd/aggregator/**"}}
+
+	hash, err := hashBuildInputs(root, component, "0.0.1")
+	require.NoError(t, err)
+
+	// Files outside the inputs don't count
+	require.NoError(t, os.WriteFile(filepath.Join(root, "README.md"), []byte("more docs"), 0644))
+	unchanged, err := hashBuildInputs(root, component, "0.0.1")
+	require.NoError(t, err)
+	require.Equal(t, hash, unchanged)
+
+	// Editing an input or tagging a new version does
+	retagged, err := hashBuildInputs(root, component, "0.0.2")
+	require.NoError(t, err)
+	require.NotEqual(t, hash, retagged)
+	require.NoError(t, os.WriteFile(filepath.Join(root, "cmd", "aggregator", "main.go"), []byte("package main\n"), 0644))
+	edited, err := hashBuildInputs(root, component, "0.0.1")
+	require.NoError(t, err)
+	require.NotEqual(t, hash, edited)
+
+	_, err = hashBuildInputs(root, common.BuildComponentConfig{Name: "executor"}, "0.0.1")
+	require.ErrorContains(t, err, "declares no inputs")
+}
+
+func TestBuildCommandCachesComponents(t *testing.T) {
+	tmpDir := t.TempDir()
+	originalCwd, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(tmpDir))
+	t.Cleanup(func() { _ = os.Chdir(originalCwd) })
+
+	config := `version: 0.0.2
+config:
+  project:
+    name: "acme"
+  build:
+    components:
+      - name: aggregator
+        inputs: ["cmd/aggregator/**", "go.mod"]
+      - name: executor
+        inputs: ["cmd/executor/**", "go.mod"]
+`
+	require.NoError(t, os.MkdirAll(filepath.Join("config", "contexts"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join("config", "config.yaml"), []byte(config), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join("config", "contexts", "devnet.yaml"), contexts.ContextYamls[contexts.LatestVersion], 0644))
+	for _, dir := range []string{"aggregator", "executor"} {
+		require.NoError(t, os.MkdirAll(filepath.Join("cmd", dir), 0755))
+		require.NoError(t, os.WriteFile(filepath.Join("cmd", dir, "main.go"), []byte("package main"), 0644))
+	}
+	require.NoError(t, os.WriteFile("go.mod", []byte("module acme"), 0644))
+
+	// The build script logs each component it builds and reports its image
+	require.NoError(t, os.MkdirAll(filepath.Join(".devkit", "scripts"), 0755))
+	script := `#!/bin/bash
+while [ $# -gt 0 ]; do [ "$1" = "--component" ] && component=$2; shift; done
+echo "$component" >> build.log
+echo "{\"artifacts\": [{\"name\": \"$component\", \"image\": \"acme-$component\", \"artifactId\": \"sha256:$component\"}]}"
+`
+	require.NoError(t, os.WriteFile(filepath.Join(".devkit", "scripts", "build"), []byte(script), 0755))
+
+	build := func(args ...string) []string {
+		_ = os.Remove("build.log")
+		cmd := *BuildCommand
+		cmd.Before = func(cCtx *cli.Context) error {
+			cCtx.Context = common.WithLogger(cCtx.Context, logger.NewNoopLogger())
+			return nil
+		}
+		app := &cli.App{Name: "test", Commands: []*cli.Command{&cmd}}
+		require.NoError(t, app.RunContext(context.Background(), append([]string{"app", "build"}, args...)))
+		data, err := os.ReadFile("build.log")
+		if os.IsNotExist(err) {
+			return nil
+		}
+		require.NoError(t, err)
+		return strings.Fields(string(data))
+	}
+
+	require.Equal(t, []string{"aggregator", "executor"}, build())
+	require.Empty(t, build())
+
+	// Only the component whose inputs changed is rebuilt, the context still lists both
+	require.NoError(t, os.WriteFile(filepath.Join("cmd", "executor", "main.go"), []byte("package main\n"), 0644))
+	require.Equal(t, []string{"executor"}, build())
+	cfg, err := common.LoadConfigWithContextConfig("devnet")
+	require.NoError(t, err)
+	components := cfg.Context["devnet"].Artifact.Components
+	require.Len(t, components, 2)
+	require.Equal(t, "acme-aggregator", components[0].Image)
+	require.Equal(t, "acme-executor", components[1].Image)
+
+	// A shared input rebuilds both, --force rebuilds regardless
+	require.NoError(t, os.WriteFile("go.mod", []byte("module acme\n"), 0644))
+	require.Equal(t, []string{"aggregator", "executor"}, build())
+	require.Equal(t, []string{"aggregator", "executor"}, build("--force"))
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/Layr-Labs/devkit-cli/pkg/testutils"
 
 	"github.com/urfave/cli/v2"
+	"gopkg.in/yaml.v3"
 )
 
 func TestBuildCommand(t *testing.T) {
@@ -193,3 +194,109 @@ echo "Mock build executed"`
 		t.Error("Build command did not exit after context cancellation")
 	}
 }
+
+func TestBuildImageVersion(t *testing.T) {
+	version, err := buildImageVersion("", "", "")
+	if err != nil || version != "0.0.1" {
+		t.Errorf("expected 0.0.1, got %q (%v)", version, err)
+	}
+	version, err = buildImageVersion("1.4.0", "", "")
+	if err != nil || version != "1.4.1" {
+		t.Errorf("expected 1.4.1, got %q (%v)", version, err)
+	}
+	version, err = buildImageVersion("1.4.0", "v2.0.0", "")
+	if err != nil || version != "2.0.0" {
+		t.Errorf("expected 2.0.0, got %q (%v)", version, err)
+	}
+	if _, err := buildImageVersion("1.4.0", "next", ""); err == nil {
+		t.Error("expected an error for a non-semver version")
+	}
+	version, err = buildImageVersion("1.4.0", "", "minor")
+	if err != nil || version != "1.5.0" {
+		t.Errorf("expected 1.5.0, got %q (%v)", version, err)
+	}
+	version, err = buildImageVersion("1.4.0", "2.0.0-rc.1", "")
+	if err != nil || version != "2.0.0-rc.1" {
+		t.Errorf("expected 2.0.0-rc.1, got %q (%v)", version, err)
+	}
+	if _, err := buildImageVersion("1.4.0", "2.0.0", "major"); err == nil {
+		t.Error("expected an error for --version with --bump")
+	}
+}
+
+func TestUpdateArtifactFromBuild(t *testing.T) {
+	var contextSection yaml.Node
+	if err := yaml.Unmarshal([]byte(`
+artifact:
+  registry: ghcr.io/acme/avs
+  version: 0.0.3
+  artifactId: sha256:old
+  component: aggregator
+  digest: sha256:released
+`), &contextSection); err != nil {
+		t.Fatal(err)
+	}
+	section := contextSection.Content[0]
+
+	output := map[string]interface{}{
+		"artifacts": []interface{}{
+			map[string]interface{}{"name": "aggregator", "image": "acme-aggregator", "artifactId": "sha256:aaaa", "platforms": []interface{}{"linux/amd64"}},
+			map[string]interface{}{"name": "executor", "artifactId": "sha256:bbbb", "operatorSets": []interface{}{0, 1}},
+		},
+	}
+	if err := updateArtifactFromBuild(section, output); err != nil {
+		t.Fatalf("updateArtifactFromBuild failed: %v", err)
+	}
+
+	var artifact common.ArtifactConfig
+	if err := common.GetChildByKey(section, "artifact").Decode(&artifact); err != nil {
+		t.Fatal(err)
+	}
+	if artifact.Component != "" || artifact.ArtifactId != "" || artifact.Digest != "" {
+		t.Errorf("expected the single component fields to be removed, got %+v", artifact)
+	}
+	if artifact.Registry != "ghcr.io/acme/avs" || artifact.Version != "0.0.3" {
+		t.Errorf("expected registry and version to be kept, got %+v", artifact)
+	}
+	if len(artifact.Components) != 2 {
+		t.Fatalf("expected 2 components, got %+v", artifact.Components)
+	}
+	aggregator, executor := artifact.Components[0], artifact.Components[1]
+	if aggregator.Image != "acme-aggregator" || aggregator.ArtifactId != "sha256:aaaa" || aggregator.Digest != "sha256:released" {
+		t.Errorf("unexpected aggregator component %+v", aggregator)
+	}
+	if executor.Image != "executor" || len(executor.OperatorSets) != 2 || executor.Digest != "" {
+		t.Errorf("unexpected executor component %+v", executor)
+	}
+
+	// Older build scripts report a single component
+	if err := updateArtifactFromBuild(section, map[string]interface{}{
+		"artifact": map[string]interface{}{"artifactId": "sha256:cccc", "component": "aggregator"},
+	}); err != nil {
+		t.Fatalf("updateArtifactFromBuild failed: %v", err)
+	}
+	artifact = common.ArtifactConfig{}
+	if err := common.GetChildByKey(section, "artifact").Decode(&artifact); err != nil {
+		t.Fatal(err)
+	}
+	if len(artifact.Components) != 1 || artifact.Components[0].ArtifactId != "sha256:cccc" || artifact.Components[0].Digest != "sha256:released" {
+		t.Errorf("unexpected components %+v", artifact.Components)
+	}
+
+	if err := updateArtifactFromBuild(section, map[string]interface{}{
+		"artifacts": []interface{}{map[string]interface{}{"image": "nameless"}},
+	}); err == nil {
+		t.Error("expected an error for an artifact without a name")
+	}
+	// The version the images were built for is recorded for release publish
+	if err := recordBuildArtifacts(section, []buildOutputArtifact{{Name: "aggregator", ArtifactId: "sha256:dddd"}}, "0.1.0"); err != nil {
+		t.Fatalf("recordBuildArtifacts failed: %v", err)
+	}
+	artifact = common.ArtifactConfig{}
+	if err := common.GetChildByKey(section, "artifact").Decode(&artifact); err != nil {
+		t.Fatal(err)
+	}
+	if artifact.BuildVersion != "0.1.0" || artifact.Version != "0.0.3" {
+		t.Errorf("expected build version 0.1.0 next to version 0.0.3, got %+v", artifact)
+	}
+}
@@ -0,0 +1,285 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/Layr-Labs/devkit-cli/pkg/common"
+	"github.com/Layr-Labs/devkit-cli/pkg/common/iface"
+	progresslogger "github.com/Layr-Labs/devkit-cli/pkg/common/logger"
+	"github.com/Layr-Labs/devkit-cli/pkg/template"
+	"github.com/urfave/cli/v2"
+)
+
+// Outcomes of verifying a component
+const (
+	buildVerifyMatch      = "match"
+	buildVerifyMismatch   = "mismatch"
+	buildVerifyMissing    = "missing"
+	buildVerifyUnrecorded = "unrecorded"
+)
+
+// BuildVerifyResult compares a component recorded in the context with its rebuild from source
+type BuildVerifyResult struct {
+	Component string `json:"component"`
+	Result    string `json:"result"`
+	Recorded  string `json:"recorded"`
+	Rebuilt   string `json:"rebuilt"`
+	// Binaries whose hashes differ between the recorded build and the rebuild
+	MismatchedBinaries []string `json:"mismatchedBinaries,omitempty"`
+}
+
+// buildVerifyCommand rebuilds every component from a clean checkout and compares it with the context
+var buildVerifyCommand = &cli.Command{
+	Name:  "verify",
+	Usage: "Rebuild each component from a clean checkout of the committed revision and compare it with the context's artifacts",
+	Flags: append([]cli.Flag{
+		&cli.StringFlag{
+			Name:  "context",
+			Usage: "devnet ,testnet or mainnet",
+			Value: "devnet",
+		},
+		&cli.StringFlag{
+			Name:  "revision",
+			Usage: "Git revision to rebuild",
+			Value: "HEAD",
+		},
+		&cli.BoolFlag{
+			Name:  "keep",
+			Usage: "Keep the temporary checkout and the rebuilt images for inspection",
+		},
+		&cli.BoolFlag{
+			Name:  "json",
+			Usage: "Print the results as JSON",
+		},
+	}, common.GlobalFlags...),
+	Action: buildVerifyAction,
+}
+
+func buildVerifyAction(cCtx *cli.Context) error {
+	logger := common.LoggerFromContext(cCtx.Context)
+	contextName := cCtx.String("context")
+
+	cfg, err := common.LoadConfigWithContextConfig(contextName)
+	if err != nil {
+		return err
+	}
+	artifact := cfg.Context[contextName].Artifact
+	if artifact == nil || len(artifact.ComponentList()) == 0 {
+		return fmt.Errorf("no components found in context. Please run 'devkit avs build' first")
+	}
+
+	// Resolve the revision and where the project sits in its repository
+	revision, err := gitOutput(cCtx.Context, "", "rev-parse", "--verify", cCtx.String("revision")+"^{commit}")
+	if err != nil {
+		return fmt.Errorf("failed to resolve revision %q: %w", cCtx.String("revision"), err)
+	}
+	repoRoot, err := gitOutput(cCtx.Context, "", "rev-parse", "--show-toplevel")
+	if err != nil {
+		return err
+	}
+	prefix, err := gitOutput(cCtx.Context, "", "rev-parse", "--show-prefix")
+	if err != nil {
+		return err
+	}
+	if status, err := gitOutput(cCtx.Context, "", "status", "--porcelain"); err == nil && status != "" {
+		logger.Warn("The working tree has uncommitted changes, verifying the committed revision %s", revision)
+	}
+
+	checkout, err := os.MkdirTemp("", "devkit-build-verify-")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary checkout: %w", err)
+	}
+	if cCtx.Bool("keep") {
+		logger.Info("Keeping the checkout in %s", checkout)
+	} else {
+		defer os.RemoveAll(checkout)
+	}
+
+	fetcher := &template.GitFetcher{
+		Client: template.NewGitClient(),
+		Logger: *progresslogger.NewProgressLogger(
+			logger,
+			common.ProgressTrackerFromContext(cCtx.Context),
+		),
+		Config: template.GitFetcherConfig{
+			Verbose: cCtx.Bool("verbose"),
+		},
+	}
+	if err := fetcher.Fetch(cCtx.Context, repoRoot, revision, checkout); err != nil {
+		return fmt.Errorf("failed to check out %s: %w", revision, err)
+	}
+	projectDir := filepath.Join(checkout, filepath.FromSlash(prefix))
+
+	// Rebuild with the same components and tag as the recorded build, build scripts may bake the tag into the image
+	tag := strings.TrimPrefix(artifact.BuildVersion, "v")
+	if tag == "" {
+		// Builds from before the tag was recorded used the next patch version, as a build without flags does
+		if tag, err = buildImageVersion(artifact.Version, "", ""); err != nil {
+			return err
+		}
+	}
+	logger.Info("Rebuilding %s in %s with tag %s...", revision, projectDir, tag)
+	var rebuilt []buildOutputArtifact
+	if len(cfg.Config.Build.Components) == 0 {
+		if rebuilt, err = runBuildScript(cCtx.Context, logger, projectDir, cfg.Config.Project.Name, tag, ""); err != nil {
+			return err
+		}
+	} else {
+		for _, component := range cfg.Config.Build.Components {
+			artifacts, err := runBuildScript(cCtx.Context, logger, projectDir, cfg.Config.Project.Name, tag, component.Name)
+			if err != nil {
+				return err
+			}
+			rebuilt = append(rebuilt, artifacts...)
+		}
+	}
+	if cCtx.Bool("keep") {
+		logger.Info("Keeping the rebuilt images tagged %s", tag)
+	} else {
+		defer removeRebuiltImages(cCtx.Context, logger, artifact.ComponentList(), rebuilt, tag)
+	}
+
+	results := compareBuildArtifacts(artifact.ComponentList(), rebuilt)
+	if err := writeBuildVerifyResults(cCtx, results); err != nil {
+		return err
+	}
+
+	failed := 0
+	for _, result := range results {
+		if result.Result != buildVerifyMatch {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d components did not reproduce from %s", failed, len(results), revision)
+	}
+	logger.Info("All %d components reproduce from %s", len(results), revision)
+	return nil
+}
+
+// compareBuildArtifacts matches each recorded component with the rebuilt artifact of the same name
+func compareBuildArtifacts(recorded []common.ArtifactComponent, rebuilt []buildOutputArtifact) []BuildVerifyResult {
+	byName := make(map[string]buildOutputArtifact, len(rebuilt))
+	for _, artifact := range rebuilt {
+		byName[artifact.Name] = artifact
+	}
+
+	results := make([]BuildVerifyResult, 0, len(recorded))
+	for _, component := range recorded {
+		result := BuildVerifyResult{Component: component.Name, Recorded: component.ArtifactId}
+		artifact, ok := byName[component.Name]
+		switch {
+		case !ok:
+			result.Result = buildVerifyMissing
+		case component.ArtifactId == "" && len(component.Hashes) == 0:
+			result.Result = buildVerifyUnrecorded
+			result.Rebuilt = artifact.ArtifactId
+		default:
+			result.Rebuilt = artifact.ArtifactId
+			result.MismatchedBinaries = mismatchedHashes(component.Hashes, artifact.Hashes)
+			result.Result = buildVerifyMatch
+			if component.ArtifactId != artifact.ArtifactId || len(result.MismatchedBinaries) > 0 {
+				result.Result = buildVerifyMismatch
+			}
+		}
+		results = append(results, result)
+	}
+	return results
+}
+
+// mismatchedHashes lists the recorded binaries the rebuild is missing or hashes differently
+func mismatchedHashes(recorded, rebuilt map[string]string) []string {
+	var mismatched []string
+	for path, hash := range recorded {
+		if rebuilt[path] != hash {
+			mismatched = append(mismatched, path)
+		}
+	}
+	sort.Strings(mismatched)
+	return mismatched
+}
+
+// writeBuildVerifyResults prints the results as a table, or as JSON with --json
+func writeBuildVerifyResults(cCtx *cli.Context, results []BuildVerifyResult) error {
+	if cCtx.Bool("json") {
+		enc := json.NewEncoder(cCtx.App.Writer)
+		enc.SetIndent("", "  ")
+		return enc.Encode(results)
+	}
+
+	w := tabwriter.NewWriter(cCtx.App.Writer, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "COMPONENT\tRESULT\tRECORDED\tREBUILT")
+	for _, result := range results {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", result.Component, result.Result, orDash(result.Recorded), orDash(result.Rebuilt))
+		for _, binary := range result.MismatchedBinaries {
+			fmt.Fprintf(w, "\t  differs: %s\t\t\n", binary)
+		}
+	}
+	return w.Flush()
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+// dockerImage runs a docker image command, replaced in tests
+var dockerImage = func(ctx context.Context, args ...string) error {
+	out, err := exec.CommandContext(ctx, "docker", append([]string{"image"}, args...)...).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("docker image %s: %s", args[0], strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// removeRebuiltImages removes the images of the rebuild that differ from the recorded ones. The rebuild took over
+// the tag of the recorded image, so the tag is moved back to it first.
+func removeRebuiltImages(ctx context.Context, logger iface.Logger, recorded []common.ArtifactComponent, rebuilt []buildOutputArtifact, tag string) {
+	recordedIDs := make(map[string]string, len(recorded))
+	for _, component := range recorded {
+		recordedIDs[component.Name] = component.ArtifactId
+	}
+	for _, artifact := range rebuilt {
+		recordedID := recordedIDs[artifact.Name]
+		// A reproduced image is the recorded one, there is nothing to remove
+		if artifact.ArtifactId == "" || artifact.ArtifactId == recordedID {
+			continue
+		}
+		image := artifact.Image
+		if image == "" {
+			image = artifact.Name
+		}
+		if recordedID != "" {
+			if err := dockerImage(ctx, "tag", recordedID, image+":"+tag); err != nil {
+				logger.Warn("Could not restore %s:%s to the recorded image: %v", image, tag, err)
+			}
+		}
+		if err := dockerImage(ctx, "rm", artifact.ArtifactId); err != nil {
+			logger.Warn("Could not remove the rebuilt image %s: %v", artifact.ArtifactId, err)
+		}
+	}
+}
+
+// gitOutput runs git in dir and returns its trimmed output
+func gitOutput(ctx context.Context, dir string, args ...string) (string, error) {
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = dir
+	out, err := cmd.Output()
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok && len(exitErr.Stderr) > 0 {
+			return "", fmt.Errorf("git %s: %s", args[0], strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return "", fmt.Errorf("git %s: %w", args[0], err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
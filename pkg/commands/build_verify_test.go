@@ -0,0 +1,158 @@
+package commands
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/Layr-Labs/devkit-cli/config/contexts"
+	"github.com/Layr-Labs/devkit-cli/pkg/common"
+	"github.com/Layr-Labs/devkit-cli/pkg/common/logger"
+	"github.com/Layr-Labs/devkit-cli/pkg/testutils"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli/v2"
+	"gopkg.in/yaml.v3"
+)
+
+func TestCompareBuildArtifacts(t *testing.T) {
+	recorded := []common.ArtifactComponent{
+		{Name: "aggregator", ArtifactId: "sha256:aaaa", Hashes: map[string]string{"bin/aggregator": "11"}},
+		{Name: "executor", ArtifactId: "sha256:bbbb"},
+		{Name: "performer", ArtifactId: "sha256:cccc", Hashes: map[string]string{"bin/performer": "33"}},
+		{Name: "gateway", ArtifactId: "sha256:dddd"},
+		{Name: "indexer"},
+	}
+	rebuilt := []buildOutputArtifact{
+		{Name: "aggregator", ArtifactId: "sha256:aaaa", Hashes: map[string]string{"bin/aggregator": "11"}},
+		{Name: "executor", ArtifactId: "sha256:ffff"},
+		{Name: "performer", ArtifactId: "sha256:cccc", Hashes: map[string]string{"bin/performer": "34"}},
+		{Name: "indexer", ArtifactId: "sha256:eeee"},
+	}
+
+	results := compareBuildArtifacts(recorded, rebuilt)
+	require.Equal(t, []BuildVerifyResult{
+		{Component: "aggregator", Result: buildVerifyMatch, Recorded: "sha256:aaaa", Rebuilt: "sha256:aaaa"},
+		{Component: "executor", Result: buildVerifyMismatch, Recorded: "sha256:bbbb", Rebuilt: "sha256:ffff"},
+		{Component: "performer", Result: buildVerifyMismatch, Recorded: "sha256:cccc", Rebuilt: "sha256:cccc", MismatchedBinaries: []string{"bin/performer"}},
+		{Component: "gateway", Result: buildVerifyMissing, Recorded: "sha256:dddd"},
+		{Component: "indexer", Result: buildVerifyUnrecorded, Rebuilt: "sha256:eeee"},
+	}, results)
+}
+
+func TestBuildVerify(t *testing.T) {
+	projectDir := t.TempDir()
+	originalCwd, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(projectDir))
+	t.Cleanup(func() { _ = os.Chdir(originalCwd) })
+
+	// The build script derives the image ID from the source, so rebuilding the commit reproduces it
+	require.NoError(t, os.MkdirAll(filepath.Join(".devkit", "scripts"), 0755))
+	script := `#!/bin/bash
+id=$(sha256sum main.go | cut -d' ' -f1)
+echo "{\"artifacts\": [{\"name\": \"avs\", \"image\": \"acme-avs\", \"artifactId\": \"sha256:$id\"}]}"
+`
+	require.NoError(t, os.WriteFile(filepath.Join(".devkit", "scripts", "build"), []byte(script), 0755))
+	require.NoError(t, os.WriteFile("main.go", []byte("package main"), 0644))
+	require.NoError(t, os.MkdirAll(filepath.Join("config", "contexts"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join("config", "config.yaml"), []byte("version: 0.0.2\nconfig:\n  project:\n    name: acme\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join("config", "contexts", "devnet.yaml"), contexts.ContextYamls[contexts.LatestVersion], 0644))
+	for _, args := range [][]string{
+		{"init", "--quiet"},
+		{"add", "."},
+		{"-c", "user.name=devkit", "-c", "user.email=devkit@example.com", "commit", "--quiet", "-m", "initial"},
+	} {
+		out, err := exec.Command("git", args...).CombinedOutput()
+		require.NoError(t, err, string(out))
+	}
+
+	// Record the artifact a build of the commit produces
+	artifacts, err := runBuildScript(context.Background(), logger.NewNoopLogger(), "", "acme", "0.0.1", "")
+	require.NoError(t, err)
+	setArtifactID := func(artifactId string) {
+		yamlPath, rootNode, contextNode, err := common.LoadContext("devnet")
+		require.NoError(t, err)
+		components := &yaml.Node{}
+		require.NoError(t, components.Encode([]common.ArtifactComponent{{Name: "avs", Image: "acme-avs", ArtifactId: artifactId}}))
+		common.SetMappingValue(common.GetChildByKey(contextNode, "artifact"), &yaml.Node{Kind: yaml.ScalarNode, Value: "components"}, components)
+		require.NoError(t, common.WriteYAML(yamlPath, rootNode))
+	}
+	setArtifactID(artifacts[0].ArtifactId)
+
+	// Uncommitted edits are not part of the rebuild
+	require.NoError(t, os.WriteFile("main.go", []byte("package main\n\nfunc main() {}"), 0644))
+
+	// Rebuilt images that differ from the recorded ones are removed
+	var imageCommands [][]string
+	origDockerImage := dockerImage
+	dockerImage = func(_ context.Context, args ...string) error {
+		imageCommands = append(imageCommands, args)
+		return nil
+	}
+	t.Cleanup(func() { dockerImage = origDockerImage })
+
+	var out bytes.Buffer
+	app := &cli.App{
+		Name:     "test",
+		Writer:   &out,
+		Commands: []*cli.Command{testutils.WithTestConfigAndNoopLogger(BuildCommand)},
+	}
+	require.NoError(t, app.Run([]string{"app", "build", "verify"}))
+	require.Regexp(t, `avs\s+match`, out.String())
+	require.Empty(t, imageCommands)
+
+	setArtifactID("sha256:1234")
+	out.Reset()
+	err = app.Run([]string{"app", "build", "verify", "--json"})
+	require.ErrorContains(t, err, "1 of 1 components did not reproduce")
+	require.Contains(t, out.String(), `"result": "mismatch"`)
+	require.Equal(t, [][]string{
+		{"tag", "sha256:1234", "acme-avs:0.0.1"},
+		{"rm", artifacts[0].ArtifactId},
+	}, imageCommands)
+}
+
+func TestBuildVerifyUsesRecordedTag(t *testing.T) {
+	projectDir := t.TempDir()
+	originalCwd, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(projectDir))
+	t.Cleanup(func() { _ = os.Chdir(originalCwd) })
+
+	// The image ID depends on the tag, as it does for scripts that bake the version into the image
+	require.NoError(t, os.MkdirAll(filepath.Join(".devkit", "scripts"), 0755))
+	script := `#!/bin/bash
+while [ $# -gt 0 ]; do [ "$1" = "--tag" ] && tag=$2; shift; done
+echo "{\"artifacts\": [{\"name\": \"avs\", \"image\": \"acme-avs\", \"artifactId\": \"sha256:$tag\"}]}"
+`
+	require.NoError(t, os.WriteFile(filepath.Join(".devkit", "scripts", "build"), []byte(script), 0755))
+	require.NoError(t, os.MkdirAll(filepath.Join("config", "contexts"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join("config", "config.yaml"), []byte("version: 0.0.2\nconfig:\n  project:\n    name: acme\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join("config", "contexts", "devnet.yaml"), contexts.ContextYamls[contexts.LatestVersion], 0644))
+	yamlPath, rootNode, contextNode, err := common.LoadContext("devnet")
+	require.NoError(t, err)
+	components := &yaml.Node{}
+	require.NoError(t, components.Encode([]common.ArtifactComponent{{Name: "avs", Image: "acme-avs", ArtifactId: "sha256:1.2.0"}}))
+	artifactNode := common.GetChildByKey(contextNode, "artifact")
+	common.SetMappingValue(artifactNode, &yaml.Node{Kind: yaml.ScalarNode, Value: "components"}, components)
+	common.SetMappingValue(artifactNode, &yaml.Node{Kind: yaml.ScalarNode, Value: "build_version"}, &yaml.Node{Kind: yaml.ScalarNode, Value: "1.2.0"})
+	require.NoError(t, common.WriteYAML(yamlPath, rootNode))
+	for _, args := range [][]string{
+		{"init", "--quiet"},
+		{"add", "."},
+		{"-c", "user.name=devkit", "-c", "user.email=devkit@example.com", "commit", "--quiet", "-m", "initial"},
+	} {
+		out, err := exec.Command("git", args...).CombinedOutput()
+		require.NoError(t, err, string(out))
+	}
+
+	app := &cli.App{
+		Name:     "test",
+		Writer:   &bytes.Buffer{},
+		Commands: []*cli.Command{testutils.WithTestConfigAndNoopLogger(BuildCommand)},
+	}
+	require.NoError(t, app.Run([]string{"app", "build", "verify"}))
+}
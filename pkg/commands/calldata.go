@@ -0,0 +1,68 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/Layr-Labs/devkit-cli/pkg/common"
+	"github.com/Layr-Labs/devkit-cli/pkg/common/iface"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/urfave/cli/v2"
+)
+
+// CalldataFlags switch privileged operations from sending transactions to emitting calldata for a multisig
+var CalldataFlags = []cli.Flag{
+	&cli.BoolFlag{
+		Name:  "calldata-only",
+		Usage: "Print target, value and calldata of privileged operations as JSON instead of sending them",
+	},
+	&cli.StringFlag{
+		Name:  "safe-batch",
+		Usage: "Write privileged operations to a Safe Transaction Builder batch file instead of sending them",
+	},
+	&cli.StringFlag{
+		Name:  "safe-address",
+		Usage: "Address of the Safe that will execute the calls, recorded in the batch file",
+	},
+}
+
+// setupCallRecorder installs a call recorder on the command context when calldata output was requested
+func setupCallRecorder(cCtx *cli.Context) (*common.CallRecorder, error) {
+	if !cCtx.Bool("calldata-only") && cCtx.String("safe-batch") == "" {
+		return nil, nil
+	}
+
+	var from ethcommon.Address
+	if safeAddress := cCtx.String("safe-address"); safeAddress != "" {
+		if !ethcommon.IsHexAddress(safeAddress) {
+			return nil, fmt.Errorf("invalid --safe-address %q", safeAddress)
+		}
+		from = ethcommon.HexToAddress(safeAddress)
+	}
+
+	recorder := common.NewCallRecorder(from)
+	cCtx.Context = common.WithCallRecorder(cCtx.Context, recorder)
+	return recorder, nil
+}
+
+// writeRecordedCalls emits the recorded calls as JSON on the app writer and/or as a Safe batch file
+func writeRecordedCalls(cCtx *cli.Context, logger iface.Logger, recorder *common.CallRecorder, batchName string) error {
+	calls := recorder.Calls()
+	if len(calls) == 0 {
+		logger.Warn("No privileged operations were recorded")
+		return nil
+	}
+
+	if cCtx.Bool("calldata-only") {
+		if err := recorder.WriteCalldata(cCtx.App.Writer); err != nil {
+			return fmt.Errorf("failed to write calldata: %w", err)
+		}
+	}
+
+	if path := cCtx.String("safe-batch"); path != "" {
+		if err := recorder.WriteSafeBatch(path, batchName); err != nil {
+			return err
+		}
+		logger.Info("Wrote %d transactions to Safe batch %s", len(calls), path)
+	}
+	return nil
+}
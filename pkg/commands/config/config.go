@@ -14,6 +14,10 @@ import (
 var Command = &cli.Command{
 	Name:  "config",
 	Usage: "Views or manages project-specific configuration (stored in config directory)",
+	Subcommands: []*cli.Command{
+		ValidateCommand,
+		GetCommand,
+	},
 	Flags: append([]cli.Flag{
 		&cli.BoolFlag{
 			Name:  "list",
@@ -81,7 +85,7 @@ var Command = &cli.Command{
 				}
 				logger.Info("Set %s = %s", pathStr, val)
 			}
-			if err := common.WriteYAML(cfgPath, rootDoc); err != nil {
+			if err := WriteAndValidate(logger, cfgPath, rootDoc, Config); err != nil {
 				return fmt.Errorf("write config YAML: %w", err)
 			}
 			return nil
@@ -8,6 +8,7 @@ import (
 	"os/exec"
 	"path/filepath"
 	"reflect"
+	"sort"
 	"strings"
 
 	"github.com/Layr-Labs/devkit-cli/pkg/common"
@@ -165,14 +166,19 @@ func openEditor(editorPath, filePath string, logger iface.Logger) error {
 	return cmd.Run()
 }
 
-// ValidateConfig reads configPath into the appropriate struct based on editTarget,
-// then runs requireNonZero on it, returning the raw bytes or an error.
+// ValidateConfig checks configPath against the schema of its version, reads it into the appropriate struct
+// based on editTarget, then runs requireNonZero on it, returning the raw bytes or an error.
 func ValidateConfig(configPath string, editTarget EditTarget) ([]byte, error) {
 	data, err := os.ReadFile(configPath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read config file: %w", err)
 	}
 
+	// Report typos, wrong types and missing fields with their positions
+	if err := validateFileError(configPath, editTarget); err != nil {
+		return data, err
+	}
+
 	// Either Config or ContextConfig
 	var val interface{}
 
@@ -325,6 +331,13 @@ func diffValues(path string, oldV, newV interface{}) []ConfigChange {
 	return out
 }
 
+// DiffValues returns the changes between two decoded YAML documents, ordered by path
+func DiffValues(oldV, newV interface{}) []ConfigChange {
+	changes := diffValues("", oldV, newV)
+	sort.SliceStable(changes, func(i, j int) bool { return changes[i].Path < changes[j].Path })
+	return changes
+}
+
 // join concatenates path and field
 func join(base, field string) string {
 	if base == "" {
@@ -223,3 +223,42 @@ func TestEditorLaunching(t *testing.T) {
 		t.Errorf("Editor didn't modify file as expected. Got: %s", string(content))
 	}
 }
+
+func TestGetCommand(t *testing.T) {
+	tmpDir := t.TempDir()
+	configContent := `version: 0.0.2
+config:
+  project:
+    name: "my-avs"
+    version: "0.1.0"
+    context: "devnet"
+    telemetry_enabled: true`
+	require.NoError(t, os.MkdirAll(filepath.Join(tmpDir, "config"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "config", common.BaseConfig), []byte(configContent), 0644))
+
+	originalWD, _ := os.Getwd()
+	defer func() {
+		if err := os.Chdir(originalWD); err != nil {
+			t.Logf("Failed to return to original directory: %v", err)
+		}
+	}()
+	require.NoError(t, os.Chdir(tmpDir))
+
+	run := func(args ...string) (string, error) {
+		var out bytes.Buffer
+		app := &cli.App{Writer: &out, Commands: []*cli.Command{GetCommand}}
+		err := app.Run(append([]string{"devkit", "get"}, args...))
+		return out.String(), err
+	}
+
+	out, err := run("project.name")
+	require.NoError(t, err)
+	require.Equal(t, "my-avs\n", out)
+
+	out, err = run("project")
+	require.NoError(t, err)
+	require.JSONEq(t, `{"name": "my-avs", "version": "0.1.0", "context": "devnet", "telemetry_enabled": true}`, out)
+
+	_, err = run("project.missing")
+	require.ErrorContains(t, err, "path not found")
+}
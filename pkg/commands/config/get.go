@@ -0,0 +1,88 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+
+	"github.com/Layr-Labs/devkit-cli/pkg/common"
+	"github.com/urfave/cli/v2"
+	"gopkg.in/yaml.v3"
+)
+
+// NewGetCommand builds the get subcommand of config or context. load returns the section paths are relative to,
+// config for config.yaml and context for contexts.
+func NewGetCommand(usage string, flags []cli.Flag, load func(cCtx *cli.Context) (*yaml.Node, error)) *cli.Command {
+	return &cli.Command{
+		Name:      "get",
+		Usage:     usage,
+		ArgsUsage: "<path>",
+		Flags: append(append(flags,
+			&cli.StringFlag{
+				Name:  "output",
+				Usage: "Format of mappings and lists: json or yaml",
+				Value: "json",
+			},
+		), common.GlobalFlags...),
+		Action: func(cCtx *cli.Context) error {
+			if cCtx.Args().Len() != 1 {
+				return fmt.Errorf("get needs one path, e.g. `avs.address` or `deployed_contracts[name=TaskMailbox].address`")
+			}
+			format := cCtx.String("output")
+			if format != "json" && format != "yaml" {
+				return fmt.Errorf("unsupported output %q, use json or yaml", format)
+			}
+
+			section, err := load(cCtx)
+			if err != nil {
+				return err
+			}
+			node, err := common.ReadFromPath(section, common.SplitPath(cCtx.Args().First()))
+			if err != nil {
+				return fmt.Errorf("path not found: %w", err)
+			}
+			return writeNode(cCtx.App.Writer, node, format)
+		},
+	}
+}
+
+// writeNode prints scalars raw, and mappings and lists as JSON or YAML
+func writeNode(w io.Writer, node *yaml.Node, format string) error {
+	if node.Kind == yaml.ScalarNode {
+		_, err := fmt.Fprintln(w, node.Value)
+		return err
+	}
+
+	if format == "yaml" {
+		enc := yaml.NewEncoder(w)
+		enc.SetIndent(2)
+		if err := enc.Encode(node); err != nil {
+			return err
+		}
+		return enc.Close()
+	}
+
+	var value interface{}
+	if err := node.Decode(&value); err != nil {
+		return fmt.Errorf("failed to decode value: %w", err)
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(common.Normalize(value))
+}
+
+// GetCommand prints a value of config.yaml
+var GetCommand = NewGetCommand("Print a value of config.yaml, e.g. `project.name`", nil,
+	func(cCtx *cli.Context) (*yaml.Node, error) {
+		doc, err := common.LoadYAML(filepath.Join(DefaultConfigPath, common.BaseConfig))
+		if err != nil {
+			return nil, fmt.Errorf("read config YAML: %w", err)
+		}
+		configNode := common.GetChildByKey(doc.Content[0], "config")
+		if configNode == nil {
+			return nil, fmt.Errorf("missing 'config' key in config.yaml")
+		}
+		// Values are printed as commands see them
+		return common.ExpandEnvNode(configNode), nil
+	})
@@ -0,0 +1,365 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Layr-Labs/devkit-cli/config/configs"
+	"github.com/Layr-Labs/devkit-cli/config/contexts"
+	"github.com/Layr-Labs/devkit-cli/pkg/common"
+	"github.com/Layr-Labs/devkit-cli/pkg/common/iface"
+	"github.com/urfave/cli/v2"
+	"gopkg.in/yaml.v3"
+)
+
+// Schema returns the JSON Schema of config or context files at version. The latest version is generated from
+// the Go structs and fitted to its embedded template, older versions are inferred from their embedded templates.
+func Schema(editTarget EditTarget, version string) (*common.JSONSchema, error) {
+	var (
+		templates map[string][]byte
+		latest    string
+		typ       reflect.Type
+		title     string
+	)
+	switch editTarget {
+	case Config:
+		templates, latest, typ, title = configs.ConfigYamls, configs.LatestVersion, reflect.TypeOf(common.Config{}), "devkit config"
+	case Context:
+		templates, latest, typ, title = contexts.ContextYamls, contexts.LatestVersion, reflect.TypeOf(common.ContextConfig{}), "devkit context"
+	default:
+		return nil, fmt.Errorf("unsupported edit target: %v", editTarget)
+	}
+
+	template, ok := templates[version]
+	if !ok {
+		return nil, fmt.Errorf("unknown %s version %q", title, version)
+	}
+	var node yaml.Node
+	if err := yaml.Unmarshal(template, &node); err != nil {
+		return nil, fmt.Errorf("invalid embedded template %s: %w", version, err)
+	}
+
+	var schema *common.JSONSchema
+	if version == latest {
+		schema = common.SchemaFromType(typ)
+		common.ApplyTemplate(schema, &node)
+	} else {
+		schema = common.SchemaFromTemplate(&node)
+	}
+	schema.Schema = common.JSONSchemaDraft
+	schema.Title = fmt.Sprintf("%s %s", title, version)
+	return schema, nil
+}
+
+// ValidateFile checks a config or context file against the schema of the version it declares.
+// Errors are prefixed with file:line:column.
+func ValidateFile(path string, editTarget EditTarget) ([]string, error) {
+	errs, err := validateFile(path, editTarget)
+	if err != nil {
+		return nil, err
+	}
+	problems := make([]string, 0, len(errs))
+	for _, e := range errs {
+		problems = append(problems, fmt.Sprintf("%s:%s", errorFile(path, e), e.Error()))
+	}
+	return problems, nil
+}
+
+// errorFile is the file a validation error of path is in, another layer for contexts merged from several files
+func errorFile(path string, e common.SchemaError) string {
+	if e.File != "" {
+		return e.File
+	}
+	return path
+}
+
+func validateFile(path string, editTarget EditTarget) ([]common.SchemaError, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	var node yaml.Node
+	if err := yaml.Unmarshal(data, &node); err != nil {
+		return yamlSyntaxErrors(err), nil
+	}
+	if len(node.Content) == 0 {
+		return []common.SchemaError{{Line: 1, Column: 1, Message: "empty file"}}, nil
+	}
+
+	root := node.Content[0]
+	if editTarget == Context {
+		if errs, ok := validateContextView(path); ok {
+			return errs, nil
+		}
+	}
+	version := common.GetChildByKey(root, "version")
+	if version == nil {
+		return []common.SchemaError{{Line: root.Line, Column: root.Column, Message: `missing required field "version"`}}, nil
+	}
+	schema, err := Schema(editTarget, version.Value)
+	if err != nil {
+		return []common.SchemaError{{Line: version.Line, Column: version.Column, Message: err.Error()}}, nil
+	}
+
+	// A context extending another only needs the settings it overrides
+	var errs []common.SchemaError
+	if extends := common.GetChildByKey(root, "extends"); editTarget == Context && extends != nil {
+		common.PartialSchema(schema)
+		if _, err := os.Stat(filepath.Join(filepath.Dir(path), extends.Value+".yaml")); err != nil {
+			errs = append(errs, common.SchemaError{Path: "extends", Line: extends.Line, Column: extends.Column, Message: fmt.Sprintf("context %q does not exist", extends.Value)})
+		}
+	}
+	// Values are checked as they load, with environment references expanded
+	return append(errs, common.ValidateNode(schema, common.ExpandEnvNode(&node))...), nil
+}
+
+// validateContextView validates a context merged with the contexts it extends and its local overlay, as it loads.
+// Errors are located in the layer that sets the value. It reports false for contexts made of a single file and
+// for layers that can't be merged, which are validated on their own.
+func validateContextView(path string) ([]common.SchemaError, bool) {
+	name := strings.TrimSuffix(filepath.Base(path), common.LocalContextSuffix)
+	name = strings.TrimSuffix(name, ".yaml")
+	view, err := common.LoadContextView(filepath.Dir(path), name)
+	if err != nil {
+		// A local overlay has no version to be validated on its own
+		if common.IsLocalContextFile(path) {
+			return []common.SchemaError{{Line: 1, Column: 1, Message: err.Error()}}, true
+		}
+		return nil, false
+	}
+	if !view.Layered() {
+		return nil, false
+	}
+
+	root := view.Doc.Content[0]
+	version := common.GetChildByKey(root, "version")
+	if version == nil {
+		return []common.SchemaError{{Line: root.Line, Column: root.Column, Message: `missing required field "version"`, File: view.Path}}, true
+	}
+	schema, err := Schema(Context, version.Value)
+	if err != nil {
+		return []common.SchemaError{{Line: version.Line, Column: version.Column, Message: err.Error(), File: view.File(version)}}, true
+	}
+	expanded := view.ExpandEnv()
+	errs := common.ValidateNode(schema, expanded)
+	for i := range errs {
+		errs[i].File = view.File(errs[i].Node)
+	}
+	return errs, true
+}
+
+// yamlErrorLineRegex matches the line yaml puts in front of its messages, e.g. "yaml: line 4: did not find expected key"
+var yamlErrorLineRegex = regexp.MustCompile(`^(?:yaml: )?line (\d+): (.*)$`)
+
+// yamlSyntaxErrors reports a yaml parse error at the line it names. yaml only knows the line of syntax errors, so
+// they point at its first column, and errors without a line point at the start of the file.
+func yamlSyntaxErrors(err error) []common.SchemaError {
+	messages := []string{err.Error()}
+	var typeErr *yaml.TypeError
+	if errors.As(err, &typeErr) {
+		messages = typeErr.Errors
+	}
+	errs := make([]common.SchemaError, 0, len(messages))
+	for _, message := range messages {
+		e := common.SchemaError{Line: 1, Column: 1, Message: message}
+		if match := yamlErrorLineRegex.FindStringSubmatch(message); match != nil {
+			e.Line, _ = strconv.Atoi(match[1])
+			e.Message = match[2]
+		}
+		errs = append(errs, e)
+	}
+	return errs
+}
+
+// validateFileError runs ValidateFile and folds its problems into a single error
+func validateFileError(path string, editTarget EditTarget) error {
+	problems, err := ValidateFile(path, editTarget)
+	if err != nil {
+		return err
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("%s is invalid:\n  %s", path, strings.Join(problems, "\n  "))
+	}
+	return nil
+}
+
+// WriteAndValidate writes doc to path and validates the result. Problems the file already had are reported as
+// warnings, new problems restore the previous content so a --set can't make a file invalid. A context doc is the
+// merged document from common.LoadContext and is written back through its layers, which may touch its local overlay.
+func WriteAndValidate(logger iface.Logger, path string, doc *yaml.Node, editTarget EditTarget) error {
+	paths := []string{path}
+	write := func() error { return common.WriteYAML(path, doc) }
+	if editTarget == Context {
+		paths = append(paths, strings.TrimSuffix(path, ".yaml")+common.LocalContextSuffix)
+		write = func() error { return common.WriteContext(path, doc) }
+	}
+	backups := make(map[string][]byte, len(paths))
+	for _, p := range paths {
+		data, err := os.ReadFile(p)
+		if errors.Is(err, os.ErrNotExist) && p != path {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", p, err)
+		}
+		backups[p] = data
+	}
+	before, err := validateFile(path, editTarget)
+	if err != nil {
+		return err
+	}
+	existing := make(map[string]bool, len(before))
+	for _, e := range before {
+		existing[e.Path+": "+e.Message] = true
+	}
+
+	if err := write(); err != nil {
+		return err
+	}
+	after, err := validateFile(path, editTarget)
+	if err != nil {
+		return err
+	}
+
+	var introduced []string
+	for _, e := range after {
+		if existing[e.Path+": "+e.Message] {
+			logger.Warn("%s:%s", errorFile(path, e), e.Error())
+			continue
+		}
+		introduced = append(introduced, fmt.Sprintf("%s:%s", errorFile(path, e), e.Error()))
+	}
+	if len(introduced) > 0 {
+		for p, backup := range backups {
+			if restoreErr := restoreBackup(p, backup); restoreErr != nil {
+				return fmt.Errorf("%s is invalid: %s (restoring %s failed: %v)", path, strings.Join(introduced, "; "), p, restoreErr)
+			}
+		}
+		return fmt.Errorf("changes were not saved, they make %s invalid:\n  %s", path, strings.Join(introduced, "\n  "))
+	}
+	return nil
+}
+
+// NewValidateCommand builds the validate subcommand of config or context. files lists the files to check
+// from the command's arguments.
+func NewValidateCommand(editTarget EditTarget, usage string, files func(cCtx *cli.Context) ([]string, error)) *cli.Command {
+	return &cli.Command{
+		Name:  "validate",
+		Usage: usage,
+		Flags: append([]cli.Flag{
+			&cli.BoolFlag{
+				Name:  "print-schema",
+				Usage: "Print the JSON Schema each file is validated against",
+			},
+		}, common.GlobalFlags...),
+		Action: func(cCtx *cli.Context) error {
+			logger := common.LoggerFromContext(cCtx.Context)
+			paths, err := files(cCtx)
+			if err != nil {
+				return err
+			}
+
+			// Contexts merged from the same layers report the problems of those layers once
+			invalid, printed := 0, make(map[string]bool)
+			for _, path := range paths {
+				if cCtx.Bool("print-schema") {
+					if err := printSchema(cCtx, path, editTarget); err != nil {
+						return err
+					}
+					continue
+				}
+
+				problems, err := ValidateFile(path, editTarget)
+				if err != nil {
+					return err
+				}
+				if len(problems) == 0 {
+					logger.Info("%s is valid", path)
+					continue
+				}
+				invalid++
+				for _, problem := range problems {
+					if !printed[problem] {
+						printed[problem] = true
+						fmt.Fprintln(cCtx.App.Writer, problem)
+					}
+				}
+			}
+			if invalid > 0 {
+				return fmt.Errorf("%d of %d files are invalid", invalid, len(paths))
+			}
+			return nil
+		},
+	}
+}
+
+// printSchema writes the schema of the version path declares
+func printSchema(cCtx *cli.Context, path string, editTarget EditTarget) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	var file struct {
+		Version string `yaml:"version"`
+	}
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	schema, err := Schema(editTarget, file.Version)
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(cCtx.App.Writer)
+	enc.SetIndent("", "  ")
+	return enc.Encode(schema)
+}
+
+// ContextFiles lists the context files named by args, or every context when none is named. Local overlays are
+// listed as the context they belong to, they are validated and linted merged into it.
+func ContextFiles(args []string) ([]string, error) {
+	contextDir := filepath.Join(DefaultConfigPath, "contexts")
+	if len(args) > 0 {
+		paths := make([]string, 0, len(args))
+		for _, name := range args {
+			// A local overlay is checked as part of its context
+			name = strings.TrimSuffix(strings.TrimSuffix(name, ".yaml"), strings.TrimSuffix(common.LocalContextSuffix, ".yaml"))
+			path := filepath.Join(contextDir, name+".yaml")
+			if !slices.Contains(paths, path) {
+				paths = append(paths, path)
+			}
+		}
+		return paths, nil
+	}
+
+	matches, err := filepath.Glob(filepath.Join(contextDir, "*.yaml"))
+	if err != nil {
+		return nil, err
+	}
+	// Local overlays only hold the settings they override
+	var paths []string
+	for _, path := range matches {
+		if !common.IsLocalContextFile(path) {
+			paths = append(paths, path)
+		}
+	}
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("no contexts found in %s", contextDir)
+	}
+	sort.Strings(paths)
+	return paths, nil
+}
+
+// ValidateCommand checks config.yaml against the schema of its version
+var ValidateCommand = NewValidateCommand(Config, "Validate config.yaml against the schema of its version",
+	func(cCtx *cli.Context) ([]string, error) {
+		return []string{filepath.Join(DefaultConfigPath, common.BaseConfig)}, nil
+	})
@@ -0,0 +1,116 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Layr-Labs/devkit-cli/config/configs"
+	"github.com/Layr-Labs/devkit-cli/config/contexts"
+	"github.com/Layr-Labs/devkit-cli/pkg/common"
+	"github.com/Layr-Labs/devkit-cli/pkg/common/logger"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEmbeddedTemplatesMatchTheirSchema(t *testing.T) {
+	dir := t.TempDir()
+	for target, templates := range map[EditTarget]map[string][]byte{Config: configs.ConfigYamls, Context: contexts.ContextYamls} {
+		for version, template := range templates {
+			path := filepath.Join(dir, version+".yaml")
+			require.NoError(t, os.WriteFile(path, template, 0644))
+			problems, err := ValidateFile(path, target)
+			require.NoError(t, err)
+			require.Empty(t, problems, "template %s", version)
+		}
+	}
+}
+
+func TestValidateFileReportsPositions(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "devnet.yaml")
+	context := string(contexts.ContextYamls[contexts.LatestVersion])
+	context = strings.Replace(context, "  operator_sets: []", "  operater_sets: []", 1)
+	context = strings.Replace(context, "      chain_id: 31337", "      chain_id: mainnet", 1)
+	context = strings.Replace(context, "  name: \"devnet\"\n", "", 1)
+	require.NoError(t, os.WriteFile(path, []byte(context), 0644))
+
+	problems, err := ValidateFile(path, Context)
+	require.NoError(t, err)
+	require.Len(t, problems, 4)
+	require.Equal(t, path+`:6:3: context: missing required field "name"`, problems[0])
+	require.Equal(t, path+`:6:3: context: missing required field "operator_sets"`, problems[1])
+	require.Equal(t, path+`:8:17: context.chains.l1.chain_id: expected an integer, got string "mainnet"`, problems[2])
+	require.Regexp(t, `:\d+:3: context: unknown field "operater_sets", did you mean "operator_sets"\?$`, problems[3])
+
+	// Files declaring an unknown version are reported at the version
+	require.NoError(t, os.WriteFile(path, []byte("version: 9.9.9\ncontext: {}\n"), 0644))
+	problems, err = ValidateFile(path, Context)
+	require.NoError(t, err)
+	require.Equal(t, []string{path + `:1:10: unknown devkit context version "9.9.9"`}, problems)
+	// Syntax errors are reported at the line yaml found them on
+	require.NoError(t, os.WriteFile(path, []byte("version: 0.0.8\ncontext:\n  name: devnet\n  chains: l1: {}\n"), 0644))
+	problems, err = ValidateFile(path, Context)
+	require.NoError(t, err)
+	require.Equal(t, []string{path + `:4:1: mapping values are not allowed in this context`}, problems)
+}
+
+func TestWriteAndValidateRestoresInvalidChanges(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "devnet.yaml")
+	original := contexts.ContextYamls[contexts.LatestVersion]
+	require.NoError(t, os.WriteFile(path, original, 0644))
+
+	doc, err := common.LoadYAML(path)
+	require.NoError(t, err)
+	chainID := common.GetChildByKey(common.GetChildByKey(common.GetChildByKey(common.GetChildByKey(doc.Content[0], "context"), "chains"), "l1"), "chain_id")
+	chainID.Tag, chainID.Value = "!!str", "mainnet"
+
+	err = WriteAndValidate(logger.NewNoopLogger(), path, doc, Context)
+	require.ErrorContains(t, err, "changes were not saved")
+	require.ErrorContains(t, err, "context.chains.l1.chain_id: expected an integer")
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.Equal(t, string(original), string(data))
+}
+
+func TestValidateFileExtendingContext(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "testnet.yaml"), contexts.ContextYamls[contexts.LatestVersion], 0644))
+	path := filepath.Join(dir, "staging.yaml")
+
+	// Only the overridden settings are needed, the extended context must exist
+	require.NoError(t, os.WriteFile(path, []byte("version: "+contexts.LatestVersion+"\nextends: testnet\ncontext:\n  name: staging\n"), 0644))
+	problems, err := ValidateFile(path, Context)
+	require.NoError(t, err)
+	require.Empty(t, problems)
+
+	require.NoError(t, os.WriteFile(path, []byte("version: "+contexts.LatestVersion+"\nextends: mainnet\ncontext:\n  name: staging\n"), 0644))
+	problems, err = ValidateFile(path, Context)
+	require.NoError(t, err)
+	require.Equal(t, []string{path + `:2:10: extends: context "mainnet" does not exist`}, problems)
+}
+
+func TestValidateFileLayeredContext(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "devnet.yaml")
+	localPath := filepath.Join(dir, "devnet.local.yaml")
+	require.NoError(t, os.WriteFile(path, contexts.ContextYamls[contexts.LatestVersion], 0644))
+	require.NoError(t, os.WriteFile(localPath, []byte("context:\n  chains:\n    l1:\n      chain_id: mainnet\n"), 0644))
+
+	// The overlay is checked merged into its context, problems are reported in the file that sets the value
+	expected := []string{localPath + `:4:17: context.chains.l1.chain_id: expected an integer, got string "mainnet"`}
+	problems, err := ValidateFile(path, Context)
+	require.NoError(t, err)
+	require.Equal(t, expected, problems)
+	problems, err = ValidateFile(localPath, Context)
+	require.NoError(t, err)
+	require.Equal(t, expected, problems)
+
+	// A context extending another gets the required settings from it
+	stagingPath := filepath.Join(dir, "staging.yaml")
+	require.NoError(t, os.WriteFile(localPath, []byte("context:\n  name: devnet\n"), 0644))
+	require.NoError(t, os.WriteFile(stagingPath, []byte("version: "+contexts.LatestVersion+"\nextends: devnet\ncontext:\n  name: staging\n  operater_sets: []\n"), 0644))
+	problems, err = ValidateFile(stagingPath, Context)
+	require.NoError(t, err)
+	require.Equal(t, []string{stagingPath + `:5:3: context: unknown field "operater_sets", did you mean "operator_sets"?`}, problems)
+}
@@ -18,6 +18,14 @@ var Command = &cli.Command{
 	Usage: "Views or manages context-specific configuration (stored in config/contexts directory)",
 	Subcommands: []*cli.Command{
 		CreateContextCommand,
+		ValidateContextCommand,
+		LintContextCommand,
+		DiffContextCommand,
+		CloneContextCommand,
+		ExportContextCommand,
+		ImportContextCommand,
+		GetContextCommand,
+		DiscoverContextCommand,
 	},
 	Flags: append([]cli.Flag{
 		&cli.StringFlag{
@@ -89,20 +97,11 @@ var Command = &cli.Command{
 			// Slice any position args to the items list
 			items = append(items, args...)
 
-			// Load the context yaml
-			rootDoc, err := common.LoadYAML(contextPath)
+			// Load the context merged with the contexts it extends and its local overlay
+			yamlPath, rootDoc, configNode, err := common.LoadContext(context)
 			if err != nil {
 				return fmt.Errorf("read context YAML: %w", err)
 			}
-			root := rootDoc.Content[0]
-			configNode := common.GetChildByKey(root, "context")
-			if configNode == nil {
-				configNode = &yaml.Node{Kind: yaml.MappingNode}
-				root.Content = append(root.Content,
-					&yaml.Node{Kind: yaml.ScalarNode, Value: "context"},
-					configNode,
-				)
-			}
 			for _, item := range items {
 				// Split into "key.path.to.field" and "value"
 				idx := strings.LastIndex(item, "=")
@@ -113,7 +112,7 @@ var Command = &cli.Command{
 				val := item[idx+1:]
 
 				// Break the key path into segments
-				path := strings.Split(pathStr, ".")
+				path := common.SplitPath(pathStr)
 
 				// Set val at path
 				configNode, err = common.WriteToPath(configNode, path, val)
@@ -122,7 +121,7 @@ var Command = &cli.Command{
 				}
 				logger.Info("Set %s = %s", pathStr, val)
 			}
-			if err := common.WriteYAML(contextPath, rootDoc); err != nil {
+			if err := config.WriteAndValidate(logger, yamlPath, rootDoc, config.Context); err != nil {
 				return fmt.Errorf("write context YAML: %w", err)
 			}
 			return nil
@@ -178,3 +177,34 @@ var Command = &cli.Command{
 		return nil
 	},
 }
+
+// ValidateContextCommand checks contexts against the schema of their version
+var ValidateContextCommand = config.NewValidateCommand(config.Context,
+	"Validate contexts against the schema of their version, every context when none is named",
+	func(cCtx *cli.Context) ([]string, error) {
+		names := cCtx.Args().Slice()
+		if name := cCtx.String("context"); name != "" {
+			names = append([]string{name}, names...)
+		}
+		return config.ContextFiles(names)
+	})
+
+// GetContextCommand prints a value of a context, merged with its layers and with environment references expanded
+var GetContextCommand = config.NewGetCommand("Print a value of a context, e.g. `avs.address` or `deployed_contracts[name=TaskMailbox].address`",
+	[]cli.Flag{
+		&cli.StringFlag{
+			Name:  "context",
+			Usage: "Select the context to read, defaults to the project's context",
+		},
+	},
+	func(cCtx *cli.Context) (*yaml.Node, error) {
+		contextName, err := common.SelectedContext(cCtx)
+		if err != nil {
+			return nil, err
+		}
+		_, _, contextNode, err := common.LoadContext(contextName)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load context %s: %w", contextName, err)
+		}
+		return common.ExpandEnvNode(contextNode), nil
+	})
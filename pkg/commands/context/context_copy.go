@@ -0,0 +1,341 @@
+package context
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Layr-Labs/devkit-cli/config/contexts"
+	"github.com/Layr-Labs/devkit-cli/pkg/commands/config"
+	"github.com/Layr-Labs/devkit-cli/pkg/common"
+	"github.com/Layr-Labs/devkit-cli/pkg/migration"
+	"github.com/urfave/cli/v2"
+	"gopkg.in/yaml.v3"
+)
+
+// deployedStatePaths hold what devnet start and deploys record, which belongs to the chain a context was used on
+var deployedStatePaths = [][]string{
+	{"deployed_contracts"},
+	{"operator_sets"},
+	{"operator_registrations"},
+	{"transporter", "active_stake_roots"},
+}
+
+// redactedKeys hold secrets, their values are dropped on export with --redact
+var redactedKeys = []string{"private_key", "ecdsa_key", "password"}
+
+// redactedValue replaces secrets in exported contexts
+const redactedValue = "<redacted>"
+
+// CloneContextCommand copies a context under a new name
+var CloneContextCommand = &cli.Command{
+	Name:      "clone",
+	Usage:     "Copy a context under a new name",
+	ArgsUsage: "<src> <dst>",
+	Flags: append([]cli.Flag{
+		&cli.BoolFlag{
+			Name:  "strip-state",
+			Usage: "Drop deployed state: deployed_contracts, operator_sets, operator_registrations and active_stake_roots",
+		},
+		&cli.BoolFlag{
+			Name:  "force",
+			Usage: "Overwrite the destination context",
+		},
+	}, common.GlobalFlags...),
+	Action: func(cCtx *cli.Context) error {
+		logger := common.LoggerFromContext(cCtx.Context)
+
+		if cCtx.Args().Len() != 2 {
+			return fmt.Errorf("clone needs a source and a destination, e.g. `devkit avs context clone testnet staging`")
+		}
+		src, dst := cCtx.Args().Get(0), cCtx.Args().Get(1)
+		contextDir := filepath.Join(config.DefaultConfigPath, "contexts")
+		dstPath := filepath.Join(contextDir, dst+".yaml")
+		if err := checkDestination(dstPath, dst, cCtx.Bool("force")); err != nil {
+			return err
+		}
+
+		// Copy the context's own file, it keeps extending the same context
+		doc, err := common.LoadYAML(filepath.Join(contextDir, src+".yaml"))
+		if err != nil {
+			return fmt.Errorf("failed to read context %s: %w", src, err)
+		}
+		contextNode, err := contextSection(doc)
+		if err != nil {
+			return fmt.Errorf("context %s: %w", src, err)
+		}
+		setContextName(contextNode, dst)
+		if cCtx.Bool("strip-state") {
+			stripDeployedState(contextNode)
+		}
+
+		if err := common.WriteYAML(dstPath, doc); err != nil {
+			return fmt.Errorf("failed to write %s: %w", dstPath, err)
+		}
+		logger.Info("Cloned %s to %s", src, dstPath)
+		return nil
+	},
+}
+
+// ExportContextCommand writes a self-contained copy of a context to share
+var ExportContextCommand = &cli.Command{
+	Name:      "export",
+	Usage:     "Write a context, merged with the contexts it extends and its local overlay, to a single file",
+	ArgsUsage: "<name>",
+	Flags: append([]cli.Flag{
+		&cli.StringFlag{
+			Name:  "output",
+			Usage: "File to write, defaults to <name>.context.yaml",
+		},
+		&cli.BoolFlag{
+			Name:  "redact",
+			Usage: "Replace private keys, passwords and RPC credentials",
+		},
+		&cli.BoolFlag{
+			Name:  "strip-state",
+			Usage: "Drop deployed state: deployed_contracts, operator_sets, operator_registrations and active_stake_roots",
+		},
+	}, common.GlobalFlags...),
+	Action: func(cCtx *cli.Context) error {
+		logger := common.LoggerFromContext(cCtx.Context)
+
+		if cCtx.Args().Len() != 1 {
+			return fmt.Errorf("export needs a context, e.g. `devkit avs context export --redact testnet`")
+		}
+		name := cCtx.Args().First()
+		output := cCtx.String("output")
+		if output == "" {
+			output = name + ".context.yaml"
+		}
+
+		_, rootNode, _, err := common.LoadContext(name)
+		if err != nil {
+			return fmt.Errorf("failed to load context %s: %w", name, err)
+		}
+		doc := common.CloneNode(rootNode)
+		contextNode, err := contextSection(doc)
+		if err != nil {
+			return fmt.Errorf("context %s: %w", name, err)
+		}
+		if cCtx.Bool("strip-state") {
+			stripDeployedState(contextNode)
+		}
+		if cCtx.Bool("redact") {
+			logger.Info("Redacted %d values", redactSecrets(contextNode))
+		}
+
+		if err := common.WriteYAML(output, doc); err != nil {
+			return fmt.Errorf("failed to write %s: %w", output, err)
+		}
+		logger.Info("Exported %s to %s", name, output)
+		return nil
+	},
+}
+
+// ImportContextCommand adds a context from a file, migrating it to the latest version
+var ImportContextCommand = &cli.Command{
+	Name:      "import",
+	Usage:     "Add a context from a file, such as one written by export, migrating it to the latest version",
+	ArgsUsage: "<file>",
+	Flags: append([]cli.Flag{
+		&cli.StringFlag{
+			Name:  "name",
+			Usage: "Name of the imported context, defaults to the name in the file",
+		},
+		&cli.BoolFlag{
+			Name:  "force",
+			Usage: "Overwrite an existing context",
+		},
+	}, common.GlobalFlags...),
+	Action: func(cCtx *cli.Context) error {
+		logger := common.LoggerFromContext(cCtx.Context)
+
+		if cCtx.Args().Len() != 1 {
+			return fmt.Errorf("import needs a file, e.g. `devkit avs context import testnet.context.yaml`")
+		}
+		file := cCtx.Args().First()
+
+		doc, err := common.LoadYAML(file)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", file, err)
+		}
+		if len(doc.Content) == 0 {
+			return fmt.Errorf("%s is empty", file)
+		}
+		version := common.GetChildByKey(doc.Content[0], "version")
+		if version == nil {
+			return fmt.Errorf("%s has no version, it is not a context file", file)
+		}
+
+		// Bring the context up to the latest version before it lands next to the others
+		if from := version.Value; from != contexts.LatestVersion {
+			logger.Info("Migrating %s v%s -> v%s", file, from, contexts.LatestVersion)
+			if doc, err = migration.MigrateNode(doc, from, contexts.LatestVersion, contexts.MigrationChain); err != nil {
+				return fmt.Errorf("failed to migrate %s: %w", file, err)
+			}
+		}
+
+		contextNode, err := contextSection(doc)
+		if err != nil {
+			return fmt.Errorf("%s: %w", file, err)
+		}
+		name := cCtx.String("name")
+		if name == "" {
+			if nameNode := common.GetChildByKey(contextNode, "name"); nameNode != nil && nameNode.Value != "" {
+				name = nameNode.Value
+			} else {
+				name = strings.TrimSuffix(strings.TrimSuffix(filepath.Base(file), filepath.Ext(file)), ".context")
+			}
+		}
+		setContextName(contextNode, name)
+
+		dstPath := filepath.Join(config.DefaultConfigPath, "contexts", name+".yaml")
+		if err := checkDestination(dstPath, name, cCtx.Bool("force")); err != nil {
+			return err
+		}
+		if err := os.MkdirAll(filepath.Dir(dstPath), 0755); err != nil {
+			return fmt.Errorf("failed to make contexts dir: %w", err)
+		}
+		if err := common.WriteYAML(dstPath, doc); err != nil {
+			return fmt.Errorf("failed to write %s: %w", dstPath, err)
+		}
+
+		// Exports may be redacted, point at what still needs filling in
+		problems, err := config.ValidateFile(dstPath, config.Context)
+		if err != nil {
+			return err
+		}
+		for _, problem := range problems {
+			logger.Warn("%s", problem)
+		}
+		if redacted := countRedacted(contextNode); redacted > 0 {
+			logger.Warn("%d values are %s, set them with `devkit avs context --context %s --edit`", redacted, redactedValue, name)
+		}
+		logger.Info("Imported %s as %s", file, dstPath)
+		return nil
+	},
+}
+
+// checkDestination rejects names that are not a plain file name and refuses to overwrite an existing context unless forced
+func checkDestination(path, name string, force bool) error {
+	// The name becomes a file in config/contexts, anything else could write outside of it or over a local overlay
+	if !filepath.IsLocal(name) || filepath.Base(name) != name || common.IsLocalContextFile(name+".yaml") {
+		return fmt.Errorf("invalid context name %q, use a plain name such as staging", name)
+	}
+	if _, err := os.Stat(path); err == nil && !force {
+		return fmt.Errorf("context %s already exists, use --force to overwrite it", name)
+	} else if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to check %s: %w", path, err)
+	}
+	return nil
+}
+
+// contextSection returns the context mapping of a context document
+func contextSection(doc *yaml.Node) (*yaml.Node, error) {
+	if len(doc.Content) == 0 {
+		return nil, fmt.Errorf("empty YAML root node")
+	}
+	contextNode := common.GetChildByKey(doc.Content[0], "context")
+	if contextNode == nil || contextNode.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("missing 'context' key")
+	}
+	return contextNode, nil
+}
+
+func setContextName(contextNode *yaml.Node, name string) {
+	if nameNode := common.GetChildByKey(contextNode, "name"); nameNode != nil {
+		nameNode.Value = name
+		return
+	}
+	common.SetMappingValue(contextNode,
+		&yaml.Node{Kind: yaml.ScalarNode, Value: "name"},
+		&yaml.Node{Kind: yaml.ScalarNode, Value: name, Style: yaml.DoubleQuotedStyle},
+	)
+}
+
+// stripDeployedState empties the lists devnet start and deploys fill in
+func stripDeployedState(contextNode *yaml.Node) {
+	for _, path := range deployedStatePaths {
+		parent := contextNode
+		for _, key := range path[:len(path)-1] {
+			if parent = common.GetChildByKey(parent, key); parent == nil {
+				break
+			}
+		}
+		if parent == nil {
+			continue
+		}
+		if node := common.GetChildByKey(parent, path[len(path)-1]); node != nil {
+			*node = yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq", Style: yaml.FlowStyle, HeadComment: node.HeadComment, LineComment: node.LineComment}
+		}
+	}
+}
+
+// redactSecrets replaces private keys, passwords and credentials in RPC URLs, returning how many values changed
+func redactSecrets(node *yaml.Node) int {
+	redacted := 0
+	switch node.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i].Value, node.Content[i+1]
+			if value.Kind != yaml.ScalarNode {
+				redacted += redactSecrets(value)
+				continue
+			}
+			if value.Value == "" || strings.HasPrefix(value.Value, "${") {
+				continue
+			}
+			switch {
+			case isSecretKey(key):
+				value.Value, value.Style, value.LineComment = redactedValue, yaml.DoubleQuotedStyle, ""
+				redacted++
+			case key == "url" || key == "rpc_url":
+				if stripped, ok := redactURL(value.Value); ok {
+					value.Value = stripped
+					redacted++
+				}
+			}
+		}
+	case yaml.SequenceNode:
+		for _, item := range node.Content {
+			redacted += redactSecrets(item)
+		}
+	}
+	return redacted
+}
+
+func isSecretKey(key string) bool {
+	for _, secret := range redactedKeys {
+		if strings.Contains(key, secret) {
+			return true
+		}
+	}
+	return false
+}
+
+// redactURL drops the user info, path and query of remote URLs, where providers put API keys
+func redactURL(raw string) (string, bool) {
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" || u.Hostname() == "localhost" || u.Hostname() == "127.0.0.1" {
+		return raw, false
+	}
+	if u.User == nil && (u.Path == "" || u.Path == "/") && u.RawQuery == "" {
+		return raw, false
+	}
+	return u.Scheme + "://" + u.Host + "/" + redactedValue, true
+}
+
+// countRedacted counts the values an export replaced
+func countRedacted(node *yaml.Node) int {
+	count := 0
+	if node.Kind == yaml.ScalarNode && strings.Contains(node.Value, redactedValue) {
+		count++
+	}
+	for _, child := range node.Content {
+		count += countRedacted(child)
+	}
+	return count
+}
@@ -0,0 +1,174 @@
+package context
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Layr-Labs/devkit-cli/config/configs"
+	"github.com/Layr-Labs/devkit-cli/config/contexts"
+	"github.com/Layr-Labs/devkit-cli/pkg/common"
+	"github.com/Layr-Labs/devkit-cli/pkg/common/logger"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli/v2"
+)
+
+// runContextCommand runs `context <args...>` in the current directory and returns what it printed
+func runContextCommand(t *testing.T, args ...string) (string, error) {
+	var out bytes.Buffer
+	app := &cli.App{
+		Name:     "devkit",
+		Writer:   &out,
+		Commands: []*cli.Command{Command},
+		Before: func(cCtx *cli.Context) error {
+			cCtx.Context = common.WithLogger(cCtx.Context, logger.NewNoopLogger())
+			cCtx.Context = common.WithProgressTracker(cCtx.Context, logger.NewNoopProgressTracker())
+			return nil
+		},
+	}
+	err := app.Run(append([]string{"devkit", "context"}, args...))
+	return out.String(), err
+}
+
+// writeContexts writes the latest devnet context as each of names into a temp project and moves into it
+func writeContexts(t *testing.T, names ...string) string {
+	tmp := t.TempDir()
+	dir := filepath.Join(tmp, "config", "contexts")
+	require.NoError(t, os.MkdirAll(dir, 0755))
+	for _, name := range names {
+		content := strings.Replace(string(contexts.ContextYamls[contexts.LatestVersion]), `name: "devnet"`, `name: "`+name+`"`, 1)
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name+".yaml"), []byte(content), 0644))
+	}
+
+	orig, err := os.Getwd()
+	require.NoError(t, err)
+	t.Cleanup(func() { require.NoError(t, os.Chdir(orig)) })
+	require.NoError(t, os.Chdir(tmp))
+	return dir
+}
+
+func TestContextDiff(t *testing.T) {
+	writeContexts(t, "devnet", "testnet")
+	_, err := runContextCommand(t, "--context", "testnet", "--set", "chains.l1.chain_id=17000")
+	require.NoError(t, err)
+
+	out, err := runContextCommand(t, "diff", "devnet", "testnet")
+	require.NoError(t, err)
+	require.Equal(t, `--- devnet
++++ testnet
+~ context.chains.l1.chain_id: 31337 -> 17000
+~ context.name: "devnet" -> "testnet"
+`, out)
+}
+
+func TestContextCloneStripsState(t *testing.T) {
+	dir := writeContexts(t, "testnet")
+	path := filepath.Join(dir, "testnet.yaml")
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	deployed := strings.Replace(string(data), "deployed_contracts: []", "deployed_contracts:\n    - name: avs\n      address: \"0x1\"\n      abi: \"\"", 1)
+	require.NoError(t, os.WriteFile(path, []byte(deployed), 0644))
+
+	_, err = runContextCommand(t, "clone", "--strip-state", "testnet", "staging")
+	require.NoError(t, err)
+
+	_, _, contextNode, err := common.LoadContext("staging")
+	require.NoError(t, err)
+	var ctx common.ChainContextConfig
+	require.NoError(t, contextNode.Decode(&ctx))
+	require.Equal(t, "staging", ctx.Name)
+	require.Empty(t, ctx.DeployedContracts)
+	require.Equal(t, 5, len(ctx.Operators))
+
+	// The destination is not overwritten without --force
+	_, err = runContextCommand(t, "clone", "testnet", "staging")
+	require.ErrorContains(t, err, "already exists")
+	require.FileExists(t, filepath.Join(dir, "staging.yaml"))
+}
+
+func TestContextCopyRejectsPathNames(t *testing.T) {
+	dir := writeContexts(t, "testnet")
+	for _, name := range []string{"../escaped", "nested/staging", "staging.local"} {
+		_, err := runContextCommand(t, "clone", "testnet", name)
+		require.ErrorContains(t, err, "invalid context name")
+	}
+
+	// Imports take the name from the file when --name is not given
+	exported := filepath.Join(t.TempDir(), "shared.yaml")
+	content := strings.Replace(string(contexts.ContextYamls[contexts.LatestVersion]), `name: "devnet"`, `name: "../../escaped"`, 1)
+	require.NoError(t, os.WriteFile(exported, []byte(content), 0644))
+	_, err := runContextCommand(t, "import", exported)
+	require.ErrorContains(t, err, "invalid context name")
+
+	require.NoFileExists(t, filepath.Join(dir, "..", "escaped.yaml"))
+	require.NoFileExists(t, filepath.Join(dir, "..", "..", "..", "escaped.yaml"))
+}
+
+func TestContextExportImport(t *testing.T) {
+	dir := writeContexts(t, "testnet")
+	_, err := runContextCommand(t, "--context", "testnet", "--set", "chains.l1.rpc_url=https://eth-holesky.example.com/v2/secret-api-key")
+	require.NoError(t, err)
+
+	exported := filepath.Join(t.TempDir(), "shared.yaml")
+	_, err = runContextCommand(t, "export", "--redact", "--output", exported, "testnet")
+	require.NoError(t, err)
+
+	data, err := os.ReadFile(exported)
+	require.NoError(t, err)
+	require.NotContains(t, string(data), "secret-api-key")
+	require.NotContains(t, string(data), "0xac0974bec39a17e36ba4a6b4d238ff944bacb478cbed5efcae784d7bf4f2ff80")
+	require.Contains(t, string(data), `deployer_private_key: "<redacted>"`)
+	require.Contains(t, string(data), "https://eth-holesky.example.com/<redacted>")
+
+	_, err = runContextCommand(t, "import", "--name", "shared", exported)
+	require.NoError(t, err)
+	imported, err := os.ReadFile(filepath.Join(dir, "shared.yaml"))
+	require.NoError(t, err)
+	require.Contains(t, string(imported), `name: "shared"`)
+}
+
+func TestContextImportMigrates(t *testing.T) {
+	dir := writeContexts(t)
+	old := filepath.Join(t.TempDir(), "old.context.yaml")
+	require.NoError(t, os.WriteFile(old, contexts.ContextYamls["0.0.7"], 0644))
+
+	_, err := runContextCommand(t, "import", old)
+	require.NoError(t, err)
+
+	// The name comes from the file and the context is at the latest version
+	imported, err := common.LoadYAML(filepath.Join(dir, "devnet.yaml"))
+	require.NoError(t, err)
+	require.Equal(t, contexts.LatestVersion, common.GetChildByKey(imported.Content[0], "version").Value)
+	artifact := common.GetChildByKey(common.GetChildByKey(imported.Content[0], "context"), "artifact")
+	require.NotNil(t, common.GetChildByKey(artifact, "components"))
+}
+
+func TestContextGet(t *testing.T) {
+	dir := writeContexts(t, "devnet", "testnet")
+	require.NoError(t, os.WriteFile(filepath.Join(filepath.Dir(dir), "config.yaml"), configs.ConfigYamls[configs.LatestVersion], 0644))
+
+	// The project's context is read by default, or the one given to `context`
+	out, err := runContextCommand(t, "get", "avs.address")
+	require.NoError(t, err)
+	require.Equal(t, "0x70997970C51812dc3A010C7d01b50e0d17dc79C8\n", out)
+	out, err = runContextCommand(t, "--context", "testnet", "get", "name")
+	require.NoError(t, err)
+	require.Equal(t, "testnet\n", out)
+
+	out, err = runContextCommand(t, "get", "--context", "devnet", "operators[address=0x9965507D1a55bcC2695C58ba16FB37d819B0A4dc].bls_keystore_path")
+	require.NoError(t, err)
+	require.Equal(t, "keystores/operator3.keystore.json\n", out)
+
+	out, err = runContextCommand(t, "get", "chains.l1.fork")
+	require.NoError(t, err)
+	require.JSONEq(t, `{"block": 4056218, "url": "", "block_time": 3}`, out)
+
+	out, err = runContextCommand(t, "get", "--output", "yaml", "chains.l1.fork")
+	require.NoError(t, err)
+	require.Equal(t, "block: 4056218\nurl: \"\"\nblock_time: 3\n", out)
+
+	_, err = runContextCommand(t, "get", "avs.missing")
+	require.ErrorContains(t, err, "path not found")
+}
@@ -7,6 +7,7 @@ import (
 	"strings"
 
 	"github.com/Layr-Labs/devkit-cli/config/contexts"
+	"github.com/Layr-Labs/devkit-cli/config/networks"
 	"github.com/Layr-Labs/devkit-cli/pkg/common"
 	"github.com/urfave/cli/v2"
 )
@@ -24,6 +25,14 @@ var CreateContextCommand = &cli.Command{
 			Name:  "force",
 			Usage: "Force context to be overwritten",
 		},
+		&cli.StringFlag{
+			Name:  "extends",
+			Usage: "Inherit every setting from an existing context instead of copying the defaults",
+		},
+		&cli.StringFlag{
+			Name:  "network",
+			Usage: "Set the EigenLayer core addresses from the built-in address book: " + strings.Join(networks.Names[:], ", "),
+		},
 	},
 	Action: func(cCtx *cli.Context) error {
 		logger := common.LoggerFromContext(cCtx.Context)
@@ -42,13 +51,27 @@ var CreateContextCommand = &cli.Command{
 		// create if missing or forced
 		if _, err := os.Stat(ctxPath); err != nil || cCtx.Bool("force") {
 			logger.Info("Creating a new context for %s", ctxName)
-			if err := CreateContext(ctxPath, ctxName); err != nil {
+			if base := cCtx.String("extends"); base != "" {
+				if err := CreateExtendingContext(ctxPath, ctxName, base); err != nil {
+					return fmt.Errorf("failed to create new context: %w", err)
+				}
+			} else if err := CreateContext(ctxPath, ctxName); err != nil {
 				return fmt.Errorf("failed to create new context: %w", err)
 			}
 		} else {
 			return fmt.Errorf("context already exists, if you want to recreate try `devkit avs context create --force %s`", ctxName)
 		}
 
+		if network := cCtx.String("network"); network != "" {
+			missing, err := SetNetworkAddresses(ctxPath, network)
+			if err != nil {
+				return fmt.Errorf("failed to set %s addresses: %w", network, err)
+			}
+			for _, key := range missing {
+				logger.Warn("The %s address book has no address for %s, set it with `devkit avs context --context %s --set %s=<address>`", network, key, ctxName, key)
+			}
+		}
+
 		logger.Info("Context successfully created at %s", ctxPath)
 		logger.Info("")
 		logger.Info("  - To view your new context call: `devkit avs context --list %s`", ctxName)
@@ -74,3 +97,47 @@ func CreateContext(contextPath, context string) error {
 
 	return nil
 }
+
+// CreateExtendingContext writes a context that inherits everything from base apart from its name
+func CreateExtendingContext(contextPath, context, base string) error {
+	basePath := filepath.Join(filepath.Dir(contextPath), fmt.Sprintf("%s.yaml", base))
+	if _, err := os.Stat(basePath); err != nil {
+		return fmt.Errorf("context %s does not exist: %w", base, err)
+	}
+
+	content := fmt.Sprintf(`version: %s
+# Settings not set here are inherited from %s
+extends: %s
+context:
+  # Name of the context
+  name: %q
+`, contexts.LatestVersion, base, base, context)
+
+	if err := os.WriteFile(contextPath, []byte(content), 0644); err != nil {
+		return fmt.Errorf("failed to write %s.yaml: %w", context, err)
+	}
+	return nil
+}
+
+// SetNetworkAddresses writes the EigenLayer core addresses of network into the context at contextPath, returning the
+// entries the address book has no address for
+func SetNetworkAddresses(contextPath, network string) ([]string, error) {
+	book, err := common.LoadNetworkAddressBook(network)
+	if err != nil {
+		return nil, err
+	}
+	doc, err := common.LoadYAML(contextPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", contextPath, err)
+	}
+	contextNode, err := contextSection(doc)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", contextPath, err)
+	}
+
+	missing := common.UpdateContextWithEigenLayerAddresses(contextNode, &book.EigenLayer)
+	if err := common.WriteYAML(contextPath, doc); err != nil {
+		return nil, fmt.Errorf("failed to write %s: %w", contextPath, err)
+	}
+	return missing, nil
+}
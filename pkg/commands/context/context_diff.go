@@ -0,0 +1,94 @@
+package context
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/Layr-Labs/devkit-cli/pkg/commands/config"
+	"github.com/Layr-Labs/devkit-cli/pkg/common"
+	"github.com/urfave/cli/v2"
+	"gopkg.in/yaml.v3"
+)
+
+// DiffContextCommand compares the settings of two contexts
+var DiffContextCommand = &cli.Command{
+	Name:      "diff",
+	Usage:     "Show the settings that differ between two contexts or exported context files",
+	ArgsUsage: "<a> <b>",
+	Flags:     append([]cli.Flag{}, common.GlobalFlags...),
+	Action: func(cCtx *cli.Context) error {
+		logger := common.LoggerFromContext(cCtx.Context)
+
+		if cCtx.Args().Len() != 2 {
+			return fmt.Errorf("diff needs two contexts, e.g. `devkit avs context diff devnet testnet`")
+		}
+		a, b := cCtx.Args().Get(0), cCtx.Args().Get(1)
+
+		oldV, err := loadContextValues(a)
+		if err != nil {
+			return err
+		}
+		newV, err := loadContextValues(b)
+		if err != nil {
+			return err
+		}
+
+		changes := config.DiffValues(oldV, newV)
+		if len(changes) == 0 {
+			logger.Info("%s and %s have the same settings", a, b)
+			return nil
+		}
+		fmt.Fprintf(cCtx.App.Writer, "--- %s\n+++ %s\n", a, b)
+		for _, change := range changes {
+			switch {
+			case change.OldValue == nil:
+				fmt.Fprintf(cCtx.App.Writer, "+ %s: %s\n", change.Path, formatDiffValue(change.NewValue))
+			case change.NewValue == nil:
+				fmt.Fprintf(cCtx.App.Writer, "- %s: %s\n", change.Path, formatDiffValue(change.OldValue))
+			default:
+				fmt.Fprintf(cCtx.App.Writer, "~ %s: %s -> %s\n", change.Path, formatDiffValue(change.OldValue), formatDiffValue(change.NewValue))
+			}
+		}
+		return nil
+	},
+}
+
+// loadContextValues decodes a context by name, merged with its layers, or a context file by path
+func loadContextValues(nameOrPath string) (interface{}, error) {
+	var root *yaml.Node
+	if strings.HasSuffix(nameOrPath, ".yaml") || strings.HasSuffix(nameOrPath, ".yml") || strings.ContainsRune(nameOrPath, filepath.Separator) {
+		doc, err := common.LoadYAML(nameOrPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", nameOrPath, err)
+		}
+		root = doc
+	} else {
+		_, doc, _, err := common.LoadContext(nameOrPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load context %s: %w", nameOrPath, err)
+		}
+		root = doc
+	}
+
+	var values map[string]interface{}
+	if err := root.Decode(&values); err != nil {
+		return nil, fmt.Errorf("failed to decode %s: %w", nameOrPath, err)
+	}
+	return values, nil
+}
+
+// formatDiffValue prints scalars as they are and lists or mappings as compact JSON
+func formatDiffValue(v interface{}) string {
+	switch v.(type) {
+	case map[string]interface{}, []interface{}:
+		data, err := json.Marshal(v)
+		if err == nil {
+			return string(data)
+		}
+	case string:
+		return fmt.Sprintf("%q", v)
+	}
+	return fmt.Sprintf("%v", v)
+}
@@ -0,0 +1,280 @@
+package context
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/Layr-Labs/devkit-cli/pkg/common"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/urfave/cli/v2"
+	"gopkg.in/yaml.v3"
+)
+
+// eigenLayerContract is a core contract under context.eigenlayer.<chain> and the public getters that return the
+// addresses of other core contracts, keyed by getter name
+type eigenLayerContract struct {
+	chain   string
+	getters map[string]string
+}
+
+// eigenLayerContracts holds the links between core contracts that discovery follows. Getters only point at contracts
+// on the same chain, so an L1 anchor finds the L1 contracts and an L2 anchor the L2 ones.
+var eigenLayerContracts = map[string]eigenLayerContract{
+	"allocation_manager":         {"l1", map[string]string{"delegation": "delegation_manager"}},
+	"delegation_manager":         {"l1", map[string]string{"allocationManager": "allocation_manager", "strategyManager": "strategy_manager"}},
+	"strategy_manager":           {"l1", map[string]string{"delegation": "delegation_manager"}},
+	"key_registrar":              {"l1", map[string]string{"allocationManager": "allocation_manager"}},
+	"cross_chain_registry":       {"l1", map[string]string{"allocationManager": "allocation_manager", "keyRegistrar": "key_registrar"}},
+	"bn254_table_calculator":     {"l1", map[string]string{"allocationManager": "allocation_manager", "keyRegistrar": "key_registrar"}},
+	"release_manager":            {"l1", map[string]string{}},
+	"bn254_certificate_verifier": {"l2", map[string]string{"operatorTableUpdater": "operator_table_updater"}},
+	"operator_table_updater":     {"l2", map[string]string{"bn254CertificateVerifier": "bn254_certificate_verifier"}},
+}
+
+// DiscoverContextCommand fills context.eigenlayer by following the getters of the core contracts from one address
+var DiscoverContextCommand = &cli.Command{
+	Name:  "discover",
+	Usage: "Discover EigenLayer core addresses on-chain from one anchor contract and cross-check context.eigenlayer",
+	Flags: append([]cli.Flag{
+		&cli.StringFlag{
+			Name:  "context",
+			Usage: "Context to check and fill in, defaults to the project's context",
+		},
+		&cli.StringFlag{
+			Name:     "anchor",
+			Usage:    "Address of a core contract, e.g. the DelegationManager or CrossChainRegistry",
+			Required: true,
+		},
+		&cli.StringFlag{
+			Name:  "anchor-contract",
+			Usage: "Key of the anchor under context.eigenlayer, e.g. delegation_manager. Defaults to the key the context has the anchor under",
+		},
+		&cli.StringFlag{
+			Name:  "rpc-url",
+			Usage: "RPC to query, defaults to the rpc_url of the anchor's chain in the context",
+		},
+		&cli.BoolFlag{
+			Name:  "fix",
+			Usage: "Replace addresses that differ from the discovered ones",
+		},
+	}, common.GlobalFlags...),
+	Action: func(cCtx *cli.Context) error {
+		logger := common.LoggerFromContext(cCtx.Context)
+		contextName, err := common.SelectedContext(cCtx)
+		if err != nil {
+			return err
+		}
+
+		if !ethcommon.IsHexAddress(cCtx.String("anchor")) {
+			return fmt.Errorf("anchor %q is not an address", cCtx.String("anchor"))
+		}
+		anchor := ethcommon.HexToAddress(cCtx.String("anchor"))
+
+		yamlPath, rootNode, contextNode, err := common.LoadContext(contextName)
+		if err != nil {
+			return fmt.Errorf("failed to load context %s: %w", contextName, err)
+		}
+		var ctx common.ChainContextConfig
+		if err := common.ExpandEnvNode(contextNode).Decode(&ctx); err != nil {
+			return fmt.Errorf("failed to decode context %s: %w", contextName, err)
+		}
+		current := contextAddresses(contextNode)
+
+		anchorKey := cCtx.String("anchor-contract")
+		if anchorKey == "" {
+			if anchorKey = findAddressKey(current, anchor); anchorKey == "" {
+				return fmt.Errorf("%s is not in context.eigenlayer, set --anchor-contract to one of: %s", anchor.Hex(), strings.Join(eigenLayerContractKeys(), ", "))
+			}
+		}
+		contract, ok := eigenLayerContracts[anchorKey]
+		if !ok {
+			return fmt.Errorf("unknown anchor contract %q, use one of: %s", anchorKey, strings.Join(eigenLayerContractKeys(), ", "))
+		}
+
+		rpcURL := cCtx.String("rpc-url")
+		if rpcURL == "" {
+			chainCfg, ok := ctx.Chains[contract.chain]
+			if !ok || chainCfg.RPCURL == "" {
+				return fmt.Errorf("no rpc_url for %s in context %s, set --rpc-url", contract.chain, contextName)
+			}
+			rpcURL = chainCfg.RPCURL
+		}
+		client, err := ethclient.Dial(rpcURL)
+		if err != nil {
+			return fmt.Errorf("failed to connect to %s RPC at %s: %w", contract.chain, rpcURL, err)
+		}
+		defer client.Close()
+
+		logger.Info("Discovering EigenLayer core contracts from %s %s...", anchorKey, anchor.Hex())
+		discovered, conflicts, err := discoverEigenLayer(cCtx.Context, client, anchorKey, anchor)
+		if err != nil {
+			return err
+		}
+		for _, conflict := range conflicts {
+			logger.Warn("%s", conflict)
+		}
+
+		// Compare with the context, filling in empty entries and, with --fix, replacing mismatches
+		keys := make([]string, 0, len(discovered))
+		for key := range discovered {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		mismatches, changed := 0, false
+		w := tabwriter.NewWriter(cCtx.App.Writer, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "CONTRACT\tDISCOVERED\tSTATUS")
+		for _, key := range keys {
+			path := fmt.Sprintf("eigenlayer.%s.%s", eigenLayerContracts[key].chain, key)
+			address := discovered[key].Hex()
+			status := "ok"
+			switch existing := current[key]; {
+			case existing == "":
+				status = "filled in"
+			case !strings.EqualFold(existing, address):
+				mismatches++
+				status = "MISMATCH, context has " + existing
+				if cCtx.Bool("fix") {
+					status += " (fixed)"
+				}
+			}
+			if status != "ok" && (current[key] == "" || cCtx.Bool("fix")) {
+				if _, err := common.WriteToPath(contextNode, strings.Split(path, "."), address); err != nil {
+					return fmt.Errorf("failed to set %s: %w", path, err)
+				}
+				changed = true
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\n", path, address, status)
+		}
+		if err := w.Flush(); err != nil {
+			return err
+		}
+
+		var undiscovered []string
+		for _, key := range eigenLayerContractKeys() {
+			if _, ok := discovered[key]; !ok {
+				undiscovered = append(undiscovered, key)
+			}
+		}
+		if len(undiscovered) > 0 {
+			logger.Info("Not reachable from %s, check by hand: %s", anchorKey, strings.Join(undiscovered, ", "))
+		}
+
+		if changed {
+			if err := common.WriteContext(yamlPath, rootNode); err != nil {
+				return fmt.Errorf("failed to save context: %w", err)
+			}
+			logger.Info("Updated context %s", contextName)
+		}
+		if mismatches > 0 && !cCtx.Bool("fix") {
+			return fmt.Errorf("%d addresses in context %s differ from the chain, rerun with --fix to use the discovered ones", mismatches, contextName)
+		}
+		return nil
+	},
+}
+
+// discoverEigenLayer follows the getters of the core contracts from anchor, returning the address of every contract
+// it reached by key. Getters that disagree about an address are returned as conflicts.
+func discoverEigenLayer(ctx context.Context, client bind.ContractCaller, anchorKey string, anchor ethcommon.Address) (map[string]ethcommon.Address, []string, error) {
+	code, err := client.CodeAt(ctx, anchor, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read code at %s: %w", anchor.Hex(), err)
+	}
+	if len(code) == 0 {
+		return nil, nil, fmt.Errorf("no contract at %s on this chain", anchor.Hex())
+	}
+
+	discovered := map[string]ethcommon.Address{anchorKey: anchor}
+	var conflicts []string
+	queue := []string{anchorKey}
+	for len(queue) > 0 {
+		key := queue[0]
+		queue = queue[1:]
+
+		getters := eigenLayerContracts[key].getters
+		names := make([]string, 0, len(getters))
+		for name := range getters {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, getter := range names {
+			target := getters[getter]
+			address, err := callAddressGetter(ctx, client, discovered[key], getter)
+			if err != nil {
+				// A failing anchor means it is not the contract it was taken for
+				if key == anchorKey {
+					return nil, nil, fmt.Errorf("%s does not look like a %s: %w", anchor.Hex(), anchorKey, err)
+				}
+				conflicts = append(conflicts, fmt.Sprintf("%s: %v", key, err))
+				continue
+			}
+			if known, ok := discovered[target]; ok {
+				if known != address {
+					conflicts = append(conflicts, fmt.Sprintf("%s.%s() returned %s but %s was found at %s", key, getter, address.Hex(), target, known.Hex()))
+				}
+				continue
+			}
+			discovered[target] = address
+			queue = append(queue, target)
+		}
+	}
+	return discovered, conflicts, nil
+}
+
+// callAddressGetter calls a public getter without arguments that returns an address
+func callAddressGetter(ctx context.Context, client bind.ContractCaller, contract ethcommon.Address, getter string) (ethcommon.Address, error) {
+	selector := crypto.Keccak256([]byte(getter + "()"))[:4]
+	output, err := client.CallContract(ctx, ethereum.CallMsg{To: &contract, Data: selector}, nil)
+	if err != nil {
+		return ethcommon.Address{}, fmt.Errorf("%s() failed: %w", getter, err)
+	}
+	if len(output) != 32 {
+		return ethcommon.Address{}, fmt.Errorf("%s() returned %d bytes, expected an address", getter, len(output))
+	}
+	address := ethcommon.BytesToAddress(output)
+	if address == (ethcommon.Address{}) {
+		return ethcommon.Address{}, fmt.Errorf("%s() returned the zero address", getter)
+	}
+	return address, nil
+}
+
+// contextAddresses returns the addresses under context.eigenlayer by contract key, after expanding env references
+func contextAddresses(contextNode *yaml.Node) map[string]string {
+	addresses := map[string]string{}
+	eigenlayer := common.GetChildByKey(common.ExpandEnvNode(contextNode), "eigenlayer")
+	for key, contract := range eigenLayerContracts {
+		if chain := common.GetChildByKey(eigenlayer, contract.chain); chain != nil {
+			if value := common.GetChildByKey(chain, key); value != nil {
+				addresses[key] = value.Value
+			}
+		}
+	}
+	return addresses
+}
+
+// findAddressKey returns the contract key address is stored under, if any
+func findAddressKey(addresses map[string]string, address ethcommon.Address) string {
+	for key, value := range addresses {
+		if strings.EqualFold(value, address.Hex()) {
+			return key
+		}
+	}
+	return ""
+}
+
+func eigenLayerContractKeys() []string {
+	keys := make([]string, 0, len(eigenLayerContracts))
+	for key := range eigenLayerContracts {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
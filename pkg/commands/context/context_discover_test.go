@@ -0,0 +1,71 @@
+package context
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeChain answers address getters from a table of contract -> getter -> address
+type fakeChain map[ethcommon.Address]map[string]ethcommon.Address
+
+func (f fakeChain) CodeAt(_ context.Context, contract ethcommon.Address, _ *big.Int) ([]byte, error) {
+	if _, ok := f[contract]; ok {
+		return []byte{0x60}, nil
+	}
+	return nil, nil
+}
+
+func (f fakeChain) CallContract(_ context.Context, call ethereum.CallMsg, _ *big.Int) ([]byte, error) {
+	for getter, address := range f[*call.To] {
+		if string(crypto.Keccak256([]byte(getter + "()"))[:4]) == string(call.Data) {
+			return ethcommon.LeftPadBytes(address.Bytes(), 32), nil
+		}
+	}
+	return nil, errors.New("execution reverted")
+}
+
+func TestDiscoverEigenLayer(t *testing.T) {
+	am := ethcommon.HexToAddress("0x0000000000000000000000000000000000000a01")
+	dm := ethcommon.HexToAddress("0x0000000000000000000000000000000000000d01")
+	sm := ethcommon.HexToAddress("0x0000000000000000000000000000000000000501")
+	kr := ethcommon.HexToAddress("0x0000000000000000000000000000000000000401")
+	ccr := ethcommon.HexToAddress("0x0000000000000000000000000000000000000c01")
+	chain := fakeChain{
+		am:  {"delegation": dm},
+		dm:  {"allocationManager": am, "strategyManager": sm},
+		sm:  {"delegation": dm},
+		kr:  {"allocationManager": am},
+		ccr: {"allocationManager": am, "keyRegistrar": kr},
+	}
+
+	discovered, conflicts, err := discoverEigenLayer(context.Background(), chain, "cross_chain_registry", ccr)
+	require.NoError(t, err)
+	require.Empty(t, conflicts)
+	require.Equal(t, map[string]ethcommon.Address{
+		"cross_chain_registry": ccr,
+		"allocation_manager":   am,
+		"key_registrar":        kr,
+		"delegation_manager":   dm,
+		"strategy_manager":     sm,
+	}, discovered)
+
+	// Getters that disagree are reported
+	other := ethcommon.HexToAddress("0x0000000000000000000000000000000000000a02")
+	chain[kr] = map[string]ethcommon.Address{"allocationManager": other}
+	_, conflicts, err = discoverEigenLayer(context.Background(), chain, "cross_chain_registry", ccr)
+	require.NoError(t, err)
+	require.Equal(t, []string{"key_registrar.allocationManager() returned " + other.Hex() + " but allocation_manager was found at " + am.Hex()}, conflicts)
+
+	// The anchor must be a contract of the given kind
+	_, _, err = discoverEigenLayer(context.Background(), chain, "delegation_manager", ethcommon.HexToAddress("0x1"))
+	require.ErrorContains(t, err, "no contract at")
+	_, _, err = discoverEigenLayer(context.Background(), chain, "delegation_manager", kr)
+	require.ErrorContains(t, err, "does not look like a delegation_manager")
+}
@@ -0,0 +1,367 @@
+package context
+
+import (
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/Layr-Labs/devkit-cli/pkg/commands/config"
+	"github.com/Layr-Labs/devkit-cli/pkg/common"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/urfave/cli/v2"
+	"gopkg.in/yaml.v3"
+)
+
+// Lint rule IDs
+const (
+	lintStakerOperator  = "staker-operator"
+	lintOperatorSet     = "allocation-operator-set"
+	lintKeyAddress      = "key-address"
+	lintKeystorePath    = "keystore-path"
+	lintAddressChecksum = "address-checksum"
+	lintAllocationWads  = "allocation-wads"
+)
+
+// Lint severities, only errors fail the command
+const (
+	lintSeverityError   = "error"
+	lintSeverityWarning = "warning"
+)
+
+// maxAllocationInWads is the whole of an operator's magnitude in a strategy
+const maxAllocationInWads = "1000000000000000000"
+
+// lintRules describes every rule, listed with --rules
+var lintRules = []struct {
+	ID, Severity, Description string
+}{
+	{lintStakerOperator, lintSeverityError, "A staker's operator must be one of the context's operators"},
+	{lintOperatorSet, lintSeverityError, "Allocations must target an operator set listed in operator_sets"},
+	{lintKeyAddress, lintSeverityError, "An ecdsa_key must derive to the address next to it"},
+	{lintKeystorePath, lintSeverityError, "Keystore paths must point to an existing file"},
+	{lintAddressChecksum, lintSeverityWarning, "Addresses should be EIP-55 checksummed (fixable)"},
+	{lintAllocationWads, lintSeverityError, "An operator's allocations of a strategy must not sum over 1e18 wads"},
+}
+
+var hexAddressPattern = regexp.MustCompile(`^0x[0-9a-fA-F]{40}$`)
+
+// lintFinding is a problem found by a lint rule, located at the yaml node that causes it
+type lintFinding struct {
+	Rule     string
+	Severity string
+	Line     int
+	Column   int
+	Message  string
+	// fix rewrites the given node, the finding's node or the one it was expanded from, set for findings --fix
+	// can safely resolve
+	fix func(node *yaml.Node)
+	// node that causes the finding
+	node *yaml.Node
+}
+
+func (f lintFinding) String() string {
+	return fmt.Sprintf("%d:%d: %s [%s] %s", f.Line, f.Column, f.Severity, f.Rule, f.Message)
+}
+
+// LintContextCommand checks contexts for mistakes the schema can't catch, such as references between sections
+var LintContextCommand = &cli.Command{
+	Name:      "lint",
+	Usage:     "Check contexts for inconsistencies that would fail devnet start or a deploy, every context when none is named",
+	ArgsUsage: "[context...]",
+	Flags: append([]cli.Flag{
+		&cli.StringFlag{
+			Name:  "context",
+			Usage: "Select the context to lint",
+		},
+		&cli.BoolFlag{
+			Name:  "fix",
+			Usage: "Apply safe fixes, such as checksumming addresses",
+		},
+		&cli.BoolFlag{
+			Name:  "rules",
+			Usage: "List the lint rules and exit",
+		},
+	}, common.GlobalFlags...),
+	Action: func(cCtx *cli.Context) error {
+		logger := common.LoggerFromContext(cCtx.Context)
+
+		if cCtx.Bool("rules") {
+			for _, rule := range lintRules {
+				fmt.Fprintf(cCtx.App.Writer, "%-24s %-8s %s\n", rule.ID, rule.Severity, rule.Description)
+			}
+			return nil
+		}
+
+		names := cCtx.Args().Slice()
+		if name := cCtx.String("context"); name != "" {
+			names = append([]string{name}, names...)
+		}
+		paths, err := config.ContextFiles(names)
+		if err != nil {
+			return err
+		}
+
+		// Contexts are linted merged with the contexts they extend and their local overlay, as they load. Findings
+		// are reported in the file that sets the value, once when several contexts share it.
+		errorCount, reported := 0, make(map[string]bool)
+		for _, path := range paths {
+			view, err := common.LoadContextView(filepath.Dir(path), strings.TrimSuffix(filepath.Base(path), ".yaml"))
+			if err != nil {
+				return fmt.Errorf("failed to read %s: %w", path, err)
+			}
+			localPath := strings.TrimSuffix(view.Path, ".yaml") + common.LocalContextSuffix
+
+			// Values are linted as they load, with ${VAR} references expanded. Fixes go to the unexpanded nodes they
+			// were expanded from, and values referencing unset variables are skipped.
+			expanded := view.ExpandEnv()
+			sources := make(map[*yaml.Node]*yaml.Node)
+			sourceNodes(expanded, view.Doc, sources)
+			skipped := make(map[*yaml.Node]bool)
+			walkScalars(expanded, func(node *yaml.Node) {
+				for _, name := range common.UnresolvedEnv(sources[node].Value) {
+					skipped[node] = true
+					logger.Warn("%s:%d:%d: ${%s} is not set, the value is not linted", view.File(node), node.Line, node.Column, name)
+				}
+			})
+			findings := lintContext(expanded)
+
+			fixed := 0
+			for _, finding := range findings {
+				if skipped[finding.node] {
+					continue
+				}
+				file := view.File(finding.node)
+				line := fmt.Sprintf("%s:%s", file, finding)
+
+				// Values set by an extended context are fixed when that context is linted, values read from the
+				// environment are not fixed in the context
+				source := sources[finding.node]
+				if cCtx.Bool("fix") && finding.fix != nil && (file == view.Path || file == localPath) && !common.HasEnvReference(source.Value) {
+					finding.fix(source)
+					fixed++
+					fmt.Fprintf(cCtx.App.Writer, "%s (fixed)\n", line)
+					continue
+				}
+				if reported[line] {
+					continue
+				}
+				reported[line] = true
+				if finding.Severity == lintSeverityError {
+					errorCount++
+				}
+				fmt.Fprintln(cCtx.App.Writer, line)
+			}
+			if fixed > 0 {
+				if err := common.WriteContext(view.Path, view.Doc); err != nil {
+					return fmt.Errorf("failed to write fixes to %s: %w", path, err)
+				}
+				logger.Info("Applied %d fixes to %s", fixed, path)
+			}
+			if len(findings) == 0 {
+				logger.Info("%s has no lint findings", path)
+			}
+		}
+
+		if errorCount > 0 {
+			return fmt.Errorf("found %d lint errors", errorCount)
+		}
+		return nil
+	},
+}
+
+// lintContext runs every rule over a context document and returns the findings ordered by position
+func lintContext(doc *yaml.Node) []lintFinding {
+	if len(doc.Content) == 0 {
+		return nil
+	}
+	ctx := common.GetChildByKey(doc.Content[0], "context")
+	if ctx == nil || ctx.Kind != yaml.MappingNode {
+		return nil
+	}
+
+	var findings []lintFinding
+	add := func(rule, severity string, node *yaml.Node, format string, args ...interface{}) {
+		findings = append(findings, lintFinding{
+			Rule:     rule,
+			Severity: severity,
+			Line:     node.Line,
+			Column:   node.Column,
+			Message:  fmt.Sprintf(format, args...),
+			node:     node,
+		})
+	}
+
+	operators := sequenceItems(common.GetChildByKey(ctx, "operators"))
+	stakers := sequenceItems(common.GetChildByKey(ctx, "stakers"))
+
+	// Stakers delegate to operators of this context
+	operatorAddresses := make(map[string]bool)
+	for _, op := range operators {
+		if address := scalarChild(op, "address"); address != nil {
+			operatorAddresses[strings.ToLower(address.Value)] = true
+		}
+	}
+	for _, staker := range stakers {
+		if operator := scalarChild(staker, "operator"); operator != nil && operator.Value != "" && !operatorAddresses[strings.ToLower(operator.Value)] {
+			add(lintStakerOperator, lintSeverityError, operator, "staker delegates to %s, which is not in operators", operator.Value)
+		}
+	}
+
+	// Allocations target registered operator sets. operator_sets is filled on devnet start, so an empty list is not checked.
+	operatorSets := make(map[string]bool)
+	for _, set := range sequenceItems(common.GetChildByKey(ctx, "operator_sets")) {
+		if id := scalarChild(set, "operator_set_id"); id != nil {
+			operatorSets[id.Value] = true
+		}
+	}
+	maxWads, _ := new(big.Int).SetString(maxAllocationInWads, 10)
+	for _, op := range operators {
+		// An operator can list a strategy more than once, its magnitude is shared between all of them
+		totals := make(map[string]*big.Int)
+		var strategies []string
+		firstAllocation := make(map[string]*yaml.Node)
+		display := make(map[string]string)
+		for _, allocation := range sequenceItems(common.GetChildByKey(op, "allocations")) {
+			strategy := ""
+			if address := scalarChild(allocation, "strategy_address"); address != nil {
+				strategy = strings.ToLower(address.Value)
+				display[strategy] = address.Value
+			}
+			if _, ok := totals[strategy]; !ok {
+				totals[strategy] = new(big.Int)
+				strategies = append(strategies, strategy)
+				firstAllocation[strategy] = allocation
+			}
+			for _, setAllocation := range sequenceItems(common.GetChildByKey(allocation, "operator_set_allocations")) {
+				if set := scalarChild(setAllocation, "operator_set"); set != nil && len(operatorSets) > 0 && !operatorSets[set.Value] {
+					add(lintOperatorSet, lintSeverityError, set, "allocation to operator set %s, which is not in operator_sets", set.Value)
+				}
+				wads := scalarChild(setAllocation, "allocation_in_wads")
+				if wads == nil {
+					continue
+				}
+				amount, ok := new(big.Int).SetString(wads.Value, 10)
+				if !ok || amount.Sign() < 0 {
+					add(lintAllocationWads, lintSeverityError, wads, "allocation_in_wads %q is not a non-negative integer", wads.Value)
+					continue
+				}
+				totals[strategy].Add(totals[strategy], amount)
+			}
+		}
+		for _, strategy := range strategies {
+			if totals[strategy].Cmp(maxWads) > 0 {
+				add(lintAllocationWads, lintSeverityError, firstAllocation[strategy], "allocations of strategy %s sum to %s wads, over the maximum of 1e18", display[strategy], totals[strategy])
+			}
+		}
+	}
+
+	// Keys derive to the addresses they are listed with
+	keyOwners := append(append([]*yaml.Node{}, operators...), stakers...)
+	if avs := common.GetChildByKey(ctx, "avs"); avs != nil {
+		keyOwners = append(keyOwners, avs)
+	}
+	for _, owner := range keyOwners {
+		address := scalarChild(owner, "address")
+		key := scalarChild(owner, "ecdsa_key")
+		if key == nil {
+			key = scalarChild(owner, "avs_private_key")
+		}
+		if address == nil || key == nil || address.Value == "" || key.Value == "" {
+			continue
+		}
+		privateKey, err := crypto.HexToECDSA(strings.TrimPrefix(key.Value, "0x"))
+		if err != nil {
+			add(lintKeyAddress, lintSeverityError, key, "not a valid ECDSA private key")
+			continue
+		}
+		if derived := crypto.PubkeyToAddress(privateKey.PublicKey).Hex(); !strings.EqualFold(derived, address.Value) {
+			add(lintKeyAddress, lintSeverityError, key, "key derives to %s, not %s", derived, address.Value)
+		}
+	}
+
+	// Keystores exist, paths are relative to the project root like on devnet start
+	for _, op := range operators {
+		keystores := []*yaml.Node{scalarChild(op, "bls_keystore_path")}
+		if signer := common.GetChildByKey(op, "signer"); signer != nil {
+			keystores = append(keystores, scalarChild(signer, "keystore_path"), scalarChild(signer, "bls_keystore_path"))
+		}
+		for _, keystore := range keystores {
+			if keystore == nil || keystore.Value == "" {
+				continue
+			}
+			if _, err := os.Stat(keystore.Value); err != nil {
+				add(lintKeystorePath, lintSeverityError, keystore, "keystore %s does not exist", keystore.Value)
+			}
+		}
+	}
+
+	// Addresses are checksummed
+	walkScalars(ctx, func(node *yaml.Node) {
+		if !hexAddressPattern.MatchString(node.Value) {
+			return
+		}
+		checksummed := ethcommon.HexToAddress(node.Value).Hex()
+		if checksummed == node.Value {
+			return
+		}
+		add(lintAddressChecksum, lintSeverityWarning, node, "%s is not checksummed, expected %s", node.Value, checksummed)
+		findings[len(findings)-1].fix = func(n *yaml.Node) { n.Value = checksummed }
+	})
+
+	sort.SliceStable(findings, func(i, j int) bool {
+		if findings[i].Line != findings[j].Line {
+			return findings[i].Line < findings[j].Line
+		}
+		return findings[i].Column < findings[j].Column
+	})
+	return findings
+}
+
+// sourceNodes maps every node of expanded to the node of doc it was copied from
+func sourceNodes(expanded, doc *yaml.Node, sources map[*yaml.Node]*yaml.Node) {
+	sources[expanded] = doc
+	for i := range expanded.Content {
+		sourceNodes(expanded.Content[i], doc.Content[i], sources)
+	}
+}
+
+// sequenceItems returns the items of a sequence node, nil for anything else
+func sequenceItems(node *yaml.Node) []*yaml.Node {
+	if node == nil || node.Kind != yaml.SequenceNode {
+		return nil
+	}
+	return node.Content
+}
+
+// scalarChild returns the scalar value of key in a mapping, nil when it is absent or not a scalar
+func scalarChild(node *yaml.Node, key string) *yaml.Node {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil
+	}
+	child := common.GetChildByKey(node, key)
+	if child == nil || child.Kind != yaml.ScalarNode {
+		return nil
+	}
+	return child
+}
+
+// walkScalars calls fn with every scalar value below node, skipping mapping keys
+func walkScalars(node *yaml.Node, fn func(*yaml.Node)) {
+	switch node.Kind {
+	case yaml.ScalarNode:
+		fn(node)
+	case yaml.MappingNode:
+		for i := 1; i < len(node.Content); i += 2 {
+			walkScalars(node.Content[i], fn)
+		}
+	case yaml.SequenceNode, yaml.DocumentNode:
+		for _, child := range node.Content {
+			walkScalars(child, fn)
+		}
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"path/filepath"
 	"strings"
 
+	"github.com/Layr-Labs/devkit-cli/pkg/common"
 	tea "github.com/charmbracelet/bubbletea"
 )
 
@@ -100,7 +101,7 @@ func ListContexts(contextDir string, isList bool) ([]string, error) {
 		}
 		name := e.Name()
 		ext := filepath.Ext(name)
-		if ext != ".yaml" && ext != ".yml" {
+		if (ext != ".yaml" && ext != ".yml") || common.IsLocalContextFile(name) {
 			continue
 		}
 		names = append(names, strings.TrimSuffix(name, ext))
@@ -96,6 +96,33 @@ func TestCreateContextCommand_CreatesFile(t *testing.T) {
 	require.NoError(t, statErr)
 }
 
+func TestCreateContextWithNetwork(t *testing.T) {
+	tmp := t.TempDir()
+	path := filepath.Join(tmp, "sepolia.yaml")
+	require.NoError(t, CreateContext(path, "sepolia"))
+
+	missing, err := SetNetworkAddresses(path, "sepolia")
+	require.NoError(t, err)
+	require.Empty(t, missing)
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.Contains(t, string(data), `allocation_manager: "0x42583067658071247ec8CE0A516A58f682002d07"`)
+	require.Contains(t, string(data), `operator_table_updater: "0xB02A15c6Bd0882b35e9936A9579f35FB26E11476"`)
+
+	// Entries the address book lacks keep the template value
+	missing, err = SetNetworkAddresses(path, "mainnet")
+	require.NoError(t, err)
+	require.Contains(t, missing, "eigenlayer.l1.key_registrar")
+	data, err = os.ReadFile(path)
+	require.NoError(t, err)
+	require.Contains(t, string(data), `delegation_manager: "0x39053D51B77DC0d36036Fc1fCc8Cb819df8Ef37A"`)
+	require.Contains(t, string(data), `key_registrar: "0xA4dB30D08d8bbcA00D40600bee9F029984dB162a"`)
+
+	_, err = SetNetworkAddresses(path, "goerli")
+	require.ErrorContains(t, err, "unknown network")
+}
+
 func TestListContexts_NoDir(t *testing.T) {
 	tmp := t.TempDir()
 	_, err := ListContexts(filepath.Join(tmp, "nodir"), true)
@@ -199,6 +226,39 @@ context:
 	require.Equal(t, "new", found)
 }
 
+func TestSetFlagWritesThroughLayers(t *testing.T) {
+	tmp := t.TempDir()
+	base := filepath.Join(tmp, "config", "contexts")
+	require.NoError(t, os.MkdirAll(base, 0755))
+	testnet := "version: 0.0.8\ncontext:\n  name: testnet\n  chains:\n    l1:\n      chain_id: 17000\n      rpc_url: \"https://holesky.example\"\n"
+	staging := "version: 0.0.8\nextends: testnet\ncontext:\n  name: staging\n"
+	require.NoError(t, os.WriteFile(filepath.Join(base, "testnet.yaml"), []byte(testnet), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(base, "staging.yaml"), []byte(staging), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(base, "staging.local.yaml"), []byte("context:\n  chains:\n    l1:\n      rpc_url: \"http://localhost:8545\"\n"), 0644))
+
+	orig, err := os.Getwd()
+	require.NoError(t, err)
+	t.Cleanup(func() { require.NoError(t, os.Chdir(orig)) })
+	require.NoError(t, os.Chdir(tmp))
+
+	// The rpc_url is set by the local overlay, so the new value goes there and the contexts keep their files
+	ctx := setupCLIContext(Command, nil, map[string]string{
+		"context": "staging",
+		"set":     "chains.l1.rpc_url=http://localhost:9545",
+	})
+	require.NoError(t, Command.Action(ctx))
+
+	data, err := os.ReadFile(filepath.Join(base, "testnet.yaml"))
+	require.NoError(t, err)
+	require.Equal(t, testnet, string(data))
+	data, err = os.ReadFile(filepath.Join(base, "staging.yaml"))
+	require.NoError(t, err)
+	require.Equal(t, staging, string(data))
+	data, err = os.ReadFile(filepath.Join(base, "staging.local.yaml"))
+	require.NoError(t, err)
+	require.Contains(t, string(data), "http://localhost:9545")
+}
+
 func TestContextSetsGlobalContext(t *testing.T) {
 	// prepare temp config/config.yaml and a dummy context file
 	tmp := t.TempDir()
@@ -309,3 +369,40 @@ config:
 	require.Contains(t, err.Error(),
 		"this context does not exist, create it with `devkit avs context create foo`")
 }
+
+func TestSelectedContext(t *testing.T) {
+	tmp := t.TempDir()
+	orig, err := os.Getwd()
+	require.NoError(t, err)
+	t.Cleanup(func() { require.NoError(t, os.Chdir(orig)) })
+	require.NoError(t, os.Chdir(tmp))
+
+	// Without a --context nor a project there is nothing to fall back to
+	child := setupCLIContext(GetContextCommand, nil, nil)
+	_, err = common.SelectedContext(child)
+	require.ErrorContains(t, err, "no context selected")
+
+	// The project's context is the default
+	require.NoError(t, os.MkdirAll("config", 0755))
+	require.NoError(t, os.WriteFile(filepath.Join("config", "config.yaml"), []byte("version: 0.0.2\nconfig:\n  project:\n    name: avs\n    context: testnet\n"), 0644))
+	name, err := common.SelectedContext(child)
+	require.NoError(t, err)
+	require.Equal(t, "testnet", name)
+
+	// `devkit avs context --context staging get` reads staging
+	parent := setupCLIContext(Command, nil, map[string]string{"context": "staging"})
+	fs := flag.NewFlagSet("get", 0)
+	for _, f := range GetContextCommand.Flags {
+		require.NoError(t, f.Apply(fs))
+	}
+	child = cli.NewContext(parent.App, fs, parent)
+	name, err = common.SelectedContext(child)
+	require.NoError(t, err)
+	require.Equal(t, "staging", name)
+
+	// The subcommand's own --context wins
+	require.NoError(t, fs.Set("context", "devnet"))
+	name, err = common.SelectedContext(child)
+	require.NoError(t, err)
+	require.Equal(t, "devnet", name)
+}
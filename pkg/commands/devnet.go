@@ -1,6 +1,10 @@
 package commands
 
 import (
+	"strings"
+	"time"
+
+	"github.com/Layr-Labs/devkit-cli/config/networks"
 	"github.com/Layr-Labs/devkit-cli/pkg/common"
 	"github.com/urfave/cli/v2"
 )
@@ -51,12 +55,16 @@ var DevnetCommand = &cli.Command{
 					Usage: "Skip AVS setup steps (metadata update, registrar setup, etc.) after contract deployment",
 					Value: false,
 				},
+				&cli.StringFlag{
+					Name:  "zeus-file",
+					Usage: "Read EigenLayer core addresses from a Zeus export (`zeus env show <env> --json`) into the devnet context",
+				},
 				&cli.BoolFlag{
-					Name:  "use-zeus",
-					Usage: "Use Zeus CLI to fetch holesky core addresses",
-					Value: false,
+					Name:   "use-zeus",
+					Usage:  "Deprecated: set the devnet's EigenLayer core addresses from the holesky address book, use --zeus-file for newer deployments",
+					Hidden: true,
 				},
-			}, common.GlobalFlags...),
+			}, append(CalldataFlags, common.GlobalFlags...)...),
 			Action: StartDevnetAction,
 		},
 		{
@@ -91,16 +99,75 @@ var DevnetCommand = &cli.Command{
 		},
 		{
 			Name:   "fetch-addresses",
-			Usage:  "Fetches current EigenLayer core addresses from holesky using Zeus CLI",
+			Usage:  "Sets the EigenLayer core addresses of a context from the built-in address book or a Zeus export",
 			Action: FetchZeusAddressesAction,
 			Flags: []cli.Flag{
 				&cli.StringFlag{
 					Name:  "context",
-					Usage: "Context to update with Zeus addresses",
+					Usage: "Context to update with the addresses",
 					Value: "devnet",
 				},
+				&cli.StringFlag{
+					Name:  "network",
+					Usage: "Network of the built-in address book: " + strings.Join(networks.Names[:], ", "),
+				},
+				&cli.StringFlag{
+					Name:  "zeus-file",
+					Usage: "Zeus export (`zeus env show <env> --json`) to read addresses from, taking precedence over --network",
+				},
+				&cli.BoolFlag{
+					Name:   "use-zeus",
+					Usage:  "Deprecated: same as --network holesky",
+					Hidden: true,
+				},
 			},
 		},
+		{
+			Name:  "events",
+			Usage: "Decodes events emitted by EigenLayer and AVS contracts",
+			Flags: append([]cli.Flag{
+				&cli.StringFlag{
+					Name:  "context",
+					Usage: "Context to read contract addresses from",
+					Value: "devnet",
+				},
+				&cli.StringSliceFlag{
+					Name:  "contract",
+					Usage: "Only show events from the named contract (e.g. AllocationManager, TaskMailbox)",
+				},
+				&cli.StringSliceFlag{
+					Name:  "event",
+					Usage: "Only show events with the given name (e.g. OperatorSetCreated)",
+				},
+				&cli.StringSliceFlag{
+					Name:  "where",
+					Usage: "Only show events whose indexed argument matches (e.g. operator=0x...)",
+				},
+				&cli.Uint64Flag{
+					Name:  "from-block",
+					Usage: "First block to read events from",
+				},
+				&cli.Uint64Flag{
+					Name:  "blocks",
+					Usage: "Number of recent blocks to read when --from-block is not set",
+					Value: 1000,
+				},
+				&cli.BoolFlag{
+					Name:  "follow",
+					Usage: "Keep polling for new events until interrupted",
+				},
+				&cli.DurationFlag{
+					Name:  "interval",
+					Usage: "Polling interval used with --follow",
+					Value: 2 * time.Second,
+				},
+				&cli.BoolFlag{
+					Name:  "json",
+					Usage: "Print one JSON object per event",
+				},
+			}, common.GlobalFlags...),
+			Action: DevnetEventsAction,
+		},
 		// TODO: Surface the following actions as separate commands:
 		// - update-avs-metadata: Updates the AVS metadata URI on the devnet
 		// - set-avs-registrar: Sets the AVS registrar address on the devnet
@@ -20,17 +20,15 @@ import (
 
 	"github.com/Layr-Labs/crypto-libs/pkg/bn254"
 	"github.com/Layr-Labs/crypto-libs/pkg/keystore"
-	"github.com/Layr-Labs/devkit-cli/config/configs"
-	"github.com/Layr-Labs/devkit-cli/config/contexts"
 	"github.com/Layr-Labs/devkit-cli/pkg/common"
 	"github.com/Layr-Labs/devkit-cli/pkg/common/devnet"
 	"github.com/Layr-Labs/devkit-cli/pkg/common/iface"
-	"github.com/Layr-Labs/devkit-cli/pkg/migration"
 	allocationmanager "github.com/Layr-Labs/eigenlayer-contracts/pkg/bindings/AllocationManager"
 	ethcommon "github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/urfave/cli/v2"
+	"gopkg.in/yaml.v3"
 )
 
 type DeployContractTransport struct {
@@ -62,34 +60,23 @@ func StartDevnetAction(cCtx *cli.Context) error {
 	skipAvsRun := cCtx.Bool("skip-avs-run")
 	skipDeployContracts := cCtx.Bool("skip-deploy-contracts")
 	skipTransporter := cCtx.Bool("skip-transporter")
-	useZeus := cCtx.Bool("use-zeus")
+	zeusFile := cCtx.String("zeus-file")
 
-	// Migrate config
-	configMigrated, err := migrateConfig(logger)
+	// Record the AVS owner's setup calls instead of sending them
+	recorder, err := setupCallRecorder(cCtx)
 	if err != nil {
-		logger.Error("config migration failed: %w", err)
-	}
-	if configMigrated > 0 {
-		logger.Info("Config migration complete")
+		return err
 	}
 
-	// Migrate contexts
-	contextsMigrated, err := migrateContexts(logger)
+	// Migrate config and contexts, stopping rather than starting with half-migrated files
+	pending, err := pendingMigrations()
 	if err != nil {
-		logger.Error("context migrations failed: %w", err)
+		return fmt.Errorf("migration failed, inspect it with `devkit avs migrate --dry-run`: %w", err)
 	}
-	if contextsMigrated > 0 {
-		suffix := "s"
-		if contextsMigrated == 1 {
-			suffix = ""
+	if len(pending) > 0 {
+		if _, err := applyMigrations(logger, pending); err != nil {
+			return err
 		}
-		logger.Info("%d context migration%s complete", contextsMigrated, suffix)
-	}
-
-	// Load config for devnet
-	config, err := common.LoadConfigWithContextConfig(devnet.DEVNET_CONTEXT)
-	if err != nil {
-		return err
 	}
 
 	// Check for context
@@ -98,22 +85,28 @@ func StartDevnetAction(cCtx *cli.Context) error {
 		return fmt.Errorf("context loading failed: %w", err)
 	}
 
-	// Fetch EigenLayer addresses using Zeus if requested
-	if useZeus {
-		logger.Info("Fetching EigenLayer core addresses from Zeus...")
-		err = common.UpdateContextWithZeusAddresses(logger, contextNode, devnet.DEVNET_CONTEXT)
-		if err != nil {
-			logger.Warn("Failed to fetch addresses from Zeus: %v", err)
-			logger.Info("Continuing with addresses from config...")
-		} else {
-			logger.Info("Successfully updated context with addresses from Zeus")
+	// --use-zeus fetched the holesky addresses with the Zeus CLI, they are in the address book now
+	network := ""
+	if cCtx.Bool("use-zeus") {
+		logger.Warn("--use-zeus is deprecated, the holesky addresses are built in. Use --zeus-file to read a Zeus export")
+		network = devnet.DEVNET_NETWORK
+	}
 
-			// Write yaml back to project directory
-			if err := common.WriteYAML(yamlPath, rootNode); err != nil {
-				return fmt.Errorf("failed to save updated context: %v", err)
-			}
+	// Take EigenLayer addresses from a Zeus export if given, before the config is read
+	if zeusFile != "" || network != "" {
+		if err := updateEigenLayerAddresses(logger, contextNode, network, zeusFile); err != nil {
+			return err
+		}
+		if err := common.WriteContext(yamlPath, rootNode); err != nil {
+			return fmt.Errorf("failed to save updated context: %v", err)
 		}
 	}
+
+	// Load config for devnet
+	config, err := common.LoadConfigWithContextConfig(devnet.DEVNET_CONTEXT)
+	if err != nil {
+		return err
+	}
 	port := cCtx.Int("port")
 	if !devnet.IsPortAvailable(port) {
 		return fmt.Errorf("❌ Port %d is already in use. Please choose a different port using --port", port)
@@ -221,14 +214,14 @@ func StartDevnetAction(cCtx *cli.Context) error {
 	}
 
 	// Write yaml back to project directory
-	if err := common.WriteYAML(yamlPath, rootNode); err != nil {
+	if err := common.WriteContext(yamlPath, rootNode); err != nil {
 		return err
 	}
 
 	// Sleep for 4 second to ensure the devnet is fully started
 	time.Sleep(4 * time.Second)
 	// Fund the wallets defined in config
-	err = devnet.FundWalletsDevnet(config, rpcUrl)
+	err = devnet.FundWalletsDevnet(config, rpcUrl, logger)
 	if err != nil {
 		return err
 	}
@@ -246,7 +239,7 @@ func StartDevnetAction(cCtx *cli.Context) error {
 		}
 
 		if len(tokenAddresses) > 0 {
-			err = devnet.FundStakersWithStrategyTokens(config, rpcUrl, tokenAddresses)
+			err = devnet.FundStakersWithStrategyTokens(config, rpcUrl, tokenAddresses, logger)
 			if err != nil {
 				logger.Warn("Failed to fund stakers with strategy tokens: %v", err)
 				logger.Info("Continuing with devnet startup...")
@@ -300,6 +293,12 @@ func StartDevnetAction(cCtx *cli.Context) error {
 				return fmt.Errorf("failed to request op set generation reservation: %w", err)
 			}
 
+			// Operator steps depend on the AVS setup being executed on-chain, so stop once the calls are recorded
+			if recorder != nil {
+				logger.Info("Skipping operator setup, transporter and AVS run while preparing calldata")
+				return writeRecordedCalls(cCtx, logger, recorder, "AVS setup")
+			}
+
 			if err := RegisterOperatorsToEigenLayerFromConfigAction(cCtx, logger); err != nil {
 				return fmt.Errorf("registering operators failed: %w", err)
 			}
@@ -335,11 +334,11 @@ func StartDevnetAction(cCtx *cli.Context) error {
 	// Run Transport against schedule - exit when AVSRun exits
 	if !skipTransporter {
 		// Post initial stake roots to L1
-		if err := Transport(cCtx); err != nil && !errors.Is(err, context.Canceled) {
+		if err := runTransport(cCtx.Context, cCtx.App.Writer, devnet.DEVNET_CONTEXT); err != nil && !errors.Is(err, context.Canceled) {
 			return fmt.Errorf("transport run failed: %w", err)
 		}
 		go func() {
-			err := ScheduleTransport(cCtx, config.Context[devnet.DEVNET_CONTEXT].Transporter.Schedule)
+			err := ScheduleTransport(cCtx, devnet.DEVNET_CONTEXT, config.Context[devnet.DEVNET_CONTEXT].Transporter.Schedule)
 			if err != nil {
 				logger.Error("ScheduleTransport failed: %v", err)
 			}
@@ -450,7 +449,7 @@ func DeployContractsAction(cCtx *cli.Context) error {
 	}
 
 	// Write yaml back to project directory
-	if err := common.WriteYAML(yamlPath, rootNode); err != nil {
+	if err := common.WriteContext(yamlPath, rootNode); err != nil {
 		return err
 	}
 
@@ -619,7 +618,9 @@ func UpdateAVSMetadataAction(cCtx *cli.Context, logger iface.Logger) error {
 	allocationManagerAddr, delegationManagerAddr, strategyManagerAddr, _, _, _, _ := devnet.GetEigenLayerAddresses(cfg)
 
 	contractCaller, err := common.NewContractCaller(
+		cCtx.Context,
 		envCtx.Avs.AVSPrivateKey,
+		envCtx.Avs.Signer,
 		big.NewInt(int64(l1ChainCfg.ChainID)),
 		client,
 		ethcommon.HexToAddress(allocationManagerAddr),
@@ -628,11 +629,14 @@ func UpdateAVSMetadataAction(cCtx *cli.Context, logger iface.Logger) error {
 		ethcommon.HexToAddress(""),
 		ethcommon.HexToAddress(""),
 		ethcommon.HexToAddress(""),
+		l1ChainCfg.Transactions,
 		logger,
 	)
 	if err != nil {
 		return fmt.Errorf("failed to create contract caller: %w", err)
 	}
+	defer contractCaller.Close()
+	contractCaller.SetCallRecorder(common.CallRecorderFromContext(cCtx.Context))
 
 	avsAddr := ethcommon.HexToAddress(envCtx.Avs.Address)
 	return contractCaller.UpdateAVSMetadata(cCtx.Context, avsAddr, uri)
@@ -660,7 +664,9 @@ func SetAVSRegistrarAction(cCtx *cli.Context, logger iface.Logger) error {
 	allocationManagerAddr, delegationManagerAddr, strategyManagerAddr, _, _, _, _ := devnet.GetEigenLayerAddresses(cfg)
 
 	contractCaller, err := common.NewContractCaller(
+		cCtx.Context,
 		envCtx.Avs.AVSPrivateKey,
+		envCtx.Avs.Signer,
 		big.NewInt(int64(l1ChainCfg.ChainID)),
 		client,
 		ethcommon.HexToAddress(allocationManagerAddr),
@@ -669,11 +675,14 @@ func SetAVSRegistrarAction(cCtx *cli.Context, logger iface.Logger) error {
 		ethcommon.HexToAddress(""),
 		ethcommon.HexToAddress(""),
 		ethcommon.HexToAddress(""),
+		l1ChainCfg.Transactions,
 		logger,
 	)
 	if err != nil {
 		return fmt.Errorf("failed to create contract caller: %w", err)
 	}
+	defer contractCaller.Close()
+	contractCaller.SetCallRecorder(common.CallRecorderFromContext(cCtx.Context))
 
 	avsAddr := ethcommon.HexToAddress(envCtx.Avs.Address)
 	var registrarAddr ethcommon.Address
@@ -716,7 +725,9 @@ func CreateAVSOperatorSetsAction(cCtx *cli.Context, logger iface.Logger) error {
 	allocationManagerAddr, delegationManagerAddr, strategyManagerAddr, _, _, _, _ := devnet.GetEigenLayerAddresses(cfg)
 
 	contractCaller, err := common.NewContractCaller(
+		cCtx.Context,
 		envCtx.Avs.AVSPrivateKey,
+		envCtx.Avs.Signer,
 		big.NewInt(int64(l1ChainCfg.ChainID)),
 		client,
 		ethcommon.HexToAddress(allocationManagerAddr),
@@ -725,11 +736,14 @@ func CreateAVSOperatorSetsAction(cCtx *cli.Context, logger iface.Logger) error {
 		ethcommon.HexToAddress(""),
 		ethcommon.HexToAddress(""),
 		ethcommon.HexToAddress(""),
+		l1ChainCfg.Transactions,
 		logger,
 	)
 	if err != nil {
 		return fmt.Errorf("failed to create contract caller: %w", err)
 	}
+	defer contractCaller.Close()
+	contractCaller.SetCallRecorder(common.CallRecorderFromContext(cCtx.Context))
 
 	avsAddr := ethcommon.HexToAddress(envCtx.Avs.Address)
 	if len(envCtx.OperatorSets) == 0 {
@@ -854,43 +868,68 @@ func RegisterOperatorsToAvsFromConfigAction(cCtx *cli.Context, logger iface.Logg
 func FetchZeusAddressesAction(cCtx *cli.Context) error {
 	logger := common.LoggerFromContext(cCtx.Context)
 	contextName := cCtx.String("context")
+	network := cCtx.String("network")
+	zeusFile := cCtx.String("zeus-file")
+	if cCtx.Bool("use-zeus") {
+		logger.Warn("--use-zeus is deprecated, use --network %s or --zeus-file", devnet.DEVNET_NETWORK)
+		if network == "" {
+			network = devnet.DEVNET_NETWORK
+		}
+	}
+	if network == "" && zeusFile == "" {
+		return fmt.Errorf("set --network to use the built-in addresses, or --zeus-file to read a Zeus export")
+	}
 
 	// Check for context
-	yamlPath, rootNode, contextNode, err := common.LoadContext("devnet") // @TODO: use selected context name
+	yamlPath, rootNode, contextNode, err := common.LoadContext(contextName)
 	if err != nil {
 		return fmt.Errorf("context loading failed: %w", err)
 	}
 
-	// Fetch addresses from Zeus
-	logger.Info("Fetching EigenLayer core addresses from Zeus...")
-	addresses, err := common.GetZeusAddresses(logger)
-	if err != nil {
-		return fmt.Errorf("failed to get addresses from Zeus for %s: %w", contextName, err)
+	// Update the context with the addresses
+	if err := updateEigenLayerAddresses(logger, contextNode, network, zeusFile); err != nil {
+		return fmt.Errorf("failed to update context (%s): %w", contextName, err)
 	}
 
-	// Print the fetched addresses
-	payload := common.ZeusAddressData{
-		AllocationManager: addresses.AllocationManager,
-		DelegationManager: addresses.DelegationManager,
+	// Write yaml back to project directory
+	if err := common.WriteContext(yamlPath, rootNode); err != nil {
+		return fmt.Errorf("failed to save updated context: %v", err)
 	}
-	b, err := json.Marshal(payload)
-	if err != nil {
-		return fmt.Errorf("found addresses (marshal failed): %w", err)
+
+	logger.Info("Successfully updated %s context with EigenLayer core addresses", contextName)
+	return nil
+}
+
+// updateEigenLayerAddresses sets context.eigenlayer from a Zeus export and the address book of network, the export
+// taking precedence. Either source may be empty.
+func updateEigenLayerAddresses(logger iface.Logger, contextNode *yaml.Node, network, zeusFile string) error {
+	addresses := &common.EigenLayerConfig{}
+	if zeusFile != "" {
+		logger.Info("Reading EigenLayer core addresses from %s...", zeusFile)
+		zeusAddresses, err := common.ReadZeusAddresses(zeusFile)
+		if err != nil {
+			return err
+		}
+		addresses = zeusAddresses
+	}
+	if network != "" {
+		book, err := common.LoadNetworkAddressBook(network)
+		if err != nil {
+			return err
+		}
+		logger.Info("Using %s addresses from address book v%s", network, book.Version)
+		common.MergeEigenLayerAddresses(addresses, &book.EigenLayer)
 	}
-	logger.Info("Found addresses: %s", b)
 
-	// Update the context with the fetched addresses
-	err = common.UpdateContextWithZeusAddresses(logger, contextNode, contextName)
+	b, err := json.Marshal(addresses)
 	if err != nil {
-		return fmt.Errorf("failed to update context (%s) with Zeus addresses: %w", contextName, err)
+		return fmt.Errorf("found addresses (marshal failed): %w", err)
 	}
+	logger.Info("Found addresses: %s", b)
 
-	// Write yaml back to project directory
-	if err := common.WriteYAML(yamlPath, rootNode); err != nil {
-		return fmt.Errorf("failed to save updated context: %v", err)
+	for _, key := range common.UpdateContextWithEigenLayerAddresses(contextNode, addresses) {
+		logger.Warn("No address for %s, it keeps its current value", key)
 	}
-
-	logger.Info("Successfully updated %s context with EigenLayer core addresses", contextName)
 	return nil
 }
 
@@ -928,24 +967,16 @@ func registerOperatorEL(cCtx *cli.Context, operatorAddress string, logger iface.
 	}
 	defer client.Close()
 
-	var operatorPrivateKey string
-	for _, op := range envCtx.Operators {
-		key, keyErr := crypto.HexToECDSA(strings.TrimPrefix(op.ECDSAKey, "0x"))
-		if keyErr != nil {
-			continue
-		}
-		if strings.EqualFold(crypto.PubkeyToAddress(key.PublicKey).Hex(), operatorAddress) {
-			operatorPrivateKey = op.ECDSAKey
-			break
-		}
-	}
-	if operatorPrivateKey == "" {
+	operator, ok := findOperatorSpec(envCtx.Operators, operatorAddress)
+	if !ok {
 		return fmt.Errorf("operator with address %s not found in config", operatorAddress)
 	}
 	allocationManagerAddr, delegationManagerAddr, strategyManagerAddr, _, _, _, _ := devnet.GetEigenLayerAddresses(cfg)
 
 	contractCaller, err := common.NewContractCaller(
-		operatorPrivateKey,
+		cCtx.Context,
+		operator.ECDSAKey,
+		operator.Signer,
 		big.NewInt(int64(l1Cfg.ChainID)),
 		client,
 		ethcommon.HexToAddress(allocationManagerAddr),
@@ -954,15 +985,34 @@ func registerOperatorEL(cCtx *cli.Context, operatorAddress string, logger iface.
 		ethcommon.HexToAddress(""),
 		ethcommon.HexToAddress(""),
 		ethcommon.HexToAddress(""),
+		l1Cfg.Transactions,
 		logger,
 	)
 	if err != nil {
 		return fmt.Errorf("failed to create contract caller: %w", err)
 	}
+	defer contractCaller.Close()
 
 	return contractCaller.RegisterAsOperator(cCtx.Context, ethcommon.HexToAddress(operatorAddress), 0, "test")
 }
 
+// findOperatorSpec returns the operator whose configured address, or the address of its ECDSA key, matches operatorAddress
+func findOperatorSpec(operators []common.OperatorSpec, operatorAddress string) (common.OperatorSpec, bool) {
+	for _, op := range operators {
+		if op.Address != "" && strings.EqualFold(op.Address, operatorAddress) {
+			return op, true
+		}
+		key, keyErr := crypto.HexToECDSA(strings.TrimPrefix(op.ECDSAKey, "0x"))
+		if keyErr != nil {
+			continue
+		}
+		if strings.EqualFold(crypto.PubkeyToAddress(key.PublicKey).Hex(), operatorAddress) {
+			return op, true
+		}
+	}
+	return common.OperatorSpec{}, false
+}
+
 func registerOperatorAVS(cCtx *cli.Context, logger iface.Logger, operatorAddress string, operatorSetID uint32, payloadHex string) error {
 	if operatorAddress == "" {
 		return fmt.Errorf("operatorAddress parameter is required and cannot be empty")
@@ -991,25 +1041,17 @@ func registerOperatorAVS(cCtx *cli.Context, logger iface.Logger, operatorAddress
 	}
 	defer client.Close()
 
-	var operatorPrivateKey string
-	for _, op := range envCtx.Operators {
-		key, keyErr := crypto.HexToECDSA(strings.TrimPrefix(op.ECDSAKey, "0x"))
-		if keyErr != nil {
-			continue
-		}
-		if strings.EqualFold(crypto.PubkeyToAddress(key.PublicKey).Hex(), operatorAddress) {
-			operatorPrivateKey = op.ECDSAKey
-			break
-		}
-	}
-	if operatorPrivateKey == "" {
+	operator, ok := findOperatorSpec(envCtx.Operators, operatorAddress)
+	if !ok {
 		return fmt.Errorf("operator with address %s not found in config", operatorAddress)
 	}
 
 	allocationManagerAddr, delegationManagerAddr, strategyManagerAddr, _, _, _, _ := devnet.GetEigenLayerAddresses(cfg)
 
 	contractCaller, err := common.NewContractCaller(
-		operatorPrivateKey,
+		cCtx.Context,
+		operator.ECDSAKey,
+		operator.Signer,
 		big.NewInt(int64(l1Cfg.ChainID)),
 		client,
 		ethcommon.HexToAddress(allocationManagerAddr),
@@ -1018,11 +1060,13 @@ func registerOperatorAVS(cCtx *cli.Context, logger iface.Logger, operatorAddress
 		ethcommon.HexToAddress(""),
 		ethcommon.HexToAddress(""),
 		ethcommon.HexToAddress(""),
+		l1Cfg.Transactions,
 		logger,
 	)
 	if err != nil {
 		return fmt.Errorf("failed to create contract caller: %w", err)
 	}
+	defer contractCaller.Close()
 
 	payloadBytes, err := hex.DecodeString(payloadHex)
 	if err != nil {
@@ -1068,7 +1112,9 @@ func depositIntoStrategy(cCtx *cli.Context, stakerSpec common.StakerSpec, logger
 	stakerPrivateKey := strings.TrimPrefix(stakerSpec.StakerECDSAKey, "0x")
 
 	contractCaller, err := common.NewContractCaller(
+		cCtx.Context,
 		stakerPrivateKey,
+		nil,
 		big.NewInt(int64(l1Cfg.ChainID)),
 		client,
 		ethcommon.HexToAddress(allocationManagerAddr),
@@ -1077,11 +1123,13 @@ func depositIntoStrategy(cCtx *cli.Context, stakerSpec common.StakerSpec, logger
 		ethcommon.HexToAddress(""),
 		ethcommon.HexToAddress(""),
 		ethcommon.HexToAddress(""),
+		l1Cfg.Transactions,
 		logger,
 	)
 	if err != nil {
 		return fmt.Errorf("failed to create contract caller: %w", err)
 	}
+	defer contractCaller.Close()
 
 	for _, deposit := range stakerSpec.Deposits {
 		strategyAddress := deposit.StrategyAddress
@@ -1125,7 +1173,9 @@ func delegateToOperator(cCtx *cli.Context, stakerSpec common.StakerSpec, operato
 	stakerPrivateKey := strings.TrimPrefix(stakerSpec.StakerECDSAKey, "0x")
 
 	contractCaller, err := common.NewContractCaller(
+		cCtx.Context,
 		stakerPrivateKey,
+		nil,
 		big.NewInt(int64(l1Cfg.ChainID)),
 		client,
 		ethcommon.HexToAddress(allocationManagerAddr),
@@ -1134,11 +1184,13 @@ func delegateToOperator(cCtx *cli.Context, stakerSpec common.StakerSpec, operato
 		ethcommon.HexToAddress(""),
 		ethcommon.HexToAddress(""),
 		ethcommon.HexToAddress(""),
+		l1Cfg.Transactions,
 		logger,
 	)
 	if err != nil {
 		return fmt.Errorf("failed to create contract caller: %w", err)
 	}
+	defer contractCaller.Close()
 	// After depositing, delegate to the operator
 	// Extract the private key of the operator we are delegating to in order to create an approval signature
 	var operatorPrivateKey string
@@ -1232,75 +1284,6 @@ func extractContractOutputs(cCtx *cli.Context, context string, contractsList []D
 	return nil
 }
 
-func migrateConfig(logger iface.Logger) (int, error) {
-	// Set path for context yamls
-	configDir := filepath.Join("config")
-	configPath := filepath.Join(configDir, "config.yaml")
-
-	// Migrate the config
-	err := migration.MigrateYaml(logger, configPath, configs.LatestVersion, configs.MigrationChain)
-	// Check for already upto date and ignore
-	alreadyUptoDate := errors.Is(err, migration.ErrAlreadyUpToDate)
-
-	// For any other error, migration has failed
-	if err != nil && !alreadyUptoDate {
-		return 0, fmt.Errorf("failed to migrate: %v", err)
-	}
-
-	// If config was migrated
-	if !alreadyUptoDate {
-		logger.Info("Migrated %s\n", configPath)
-
-		return 1, nil
-	}
-
-	return 0, nil
-}
-
-func migrateContexts(logger iface.Logger) (int, error) {
-	// Count the number of contexts we migrate
-	contextsMigrated := 0
-
-	// Set path for context yamls
-	contextDir := filepath.Join("config", "contexts")
-
-	// Read all contexts/*.yamls
-	entries, err := os.ReadDir(contextDir)
-	if err != nil {
-		return 0, fmt.Errorf("unable to read context directory: %v", err)
-	}
-
-	// Attempt to upgrade every entry
-	for _, e := range entries {
-		if e.IsDir() || !strings.HasSuffix(e.Name(), ".yaml") {
-			continue
-		}
-		contextPath := filepath.Join(contextDir, e.Name())
-
-		// Migrate the context
-		err := migration.MigrateYaml(logger, contextPath, contexts.LatestVersion, contexts.MigrationChain)
-		// Check for already upto date and ignore
-		alreadyUptoDate := errors.Is(err, migration.ErrAlreadyUpToDate)
-
-		// For every other error, migration failed
-		if err != nil && !alreadyUptoDate {
-			logger.Error("failed to migrate: %v", err)
-			continue
-		}
-
-		// If context was migrated
-		if !alreadyUptoDate {
-			// Incr number of contextsMigrated
-			contextsMigrated += 1
-
-			// If migration succeeds
-			logger.Info("Migrated %s\n", contextPath)
-		}
-	}
-
-	return contextsMigrated, nil
-}
-
 func ModifyAllocationsAction(cCtx *cli.Context, logger iface.Logger) error {
 	cfg, err := common.LoadConfigWithContextConfig(devnet.DEVNET_CONTEXT)
 	if err != nil {
@@ -1376,6 +1359,28 @@ func modifyAllocations(cCtx *cli.Context, operatorAddress string, operatorPrivat
 		return nil
 	}
 
+	// One caller signs every allocation of the operator
+	allocationManagerAddr, delegationManagerAddr, strategyManagerAddr, _, _, _, _ := devnet.GetEigenLayerAddresses(cfg)
+	contractCaller, err := common.NewContractCaller(
+		cCtx.Context,
+		operatorPrivateKey,
+		nil,
+		big.NewInt(int64(l1Cfg.ChainID)),
+		client,
+		ethcommon.HexToAddress(allocationManagerAddr),
+		ethcommon.HexToAddress(delegationManagerAddr),
+		ethcommon.HexToAddress(strategyManagerAddr),
+		ethcommon.HexToAddress(""),
+		ethcommon.HexToAddress(""),
+		ethcommon.HexToAddress(""),
+		l1Cfg.Transactions,
+		logger,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create contract caller: %w", err)
+	}
+	defer contractCaller.Close()
+
 	// For each allocation in the operator config
 	for _, allocation := range targetOperator.Allocations {
 		strategyAddress := allocation.StrategyAddress
@@ -1408,24 +1413,6 @@ func modifyAllocations(cCtx *cli.Context, operatorAddress string, operatorPrivat
 			logger.Info("Modifying allocation for operator %s: operator_set=%s, strategy=%s, allocation=%s",
 				operatorAddress, operatorSetID, strategyAddress, allocationInWads)
 
-			allocationManagerAddr, delegationManagerAddr, strategyManagerAddr, _, _, _, _ := devnet.GetEigenLayerAddresses(cfg)
-
-			contractCaller, err := common.NewContractCaller(
-				operatorPrivateKey,
-				big.NewInt(int64(l1Cfg.ChainID)),
-				client,
-				ethcommon.HexToAddress(allocationManagerAddr),
-				ethcommon.HexToAddress(delegationManagerAddr),
-				ethcommon.HexToAddress(strategyManagerAddr),
-				ethcommon.HexToAddress(""),
-				ethcommon.HexToAddress(""),
-				ethcommon.HexToAddress(""),
-				logger,
-			)
-			if err != nil {
-				return fmt.Errorf("failed to create contract caller: %w", err)
-			}
-
 			// Convert operatorSetID string to uint32
 			operatorSetIDUint32, err := strconv.ParseUint(operatorSetID, 10, 32)
 			if err != nil {
@@ -1584,7 +1571,9 @@ func ConfigureOpSetCurveTypeAction(cCtx *cli.Context, logger iface.Logger) error
 	_, _, _, keyRegistrarAddr, _, _, _ := devnet.GetEigenLayerAddresses(cfg)
 
 	contractCaller, err := common.NewContractCaller(
+		cCtx.Context,
 		avsPrivateKeyOrGivenPermissionByAvs,
+		envCtx.Avs.Signer,
 		big.NewInt(int64(l1Cfg.ChainID)),
 		client,
 		ethcommon.HexToAddress(""),
@@ -1593,11 +1582,14 @@ func ConfigureOpSetCurveTypeAction(cCtx *cli.Context, logger iface.Logger) error
 		ethcommon.HexToAddress(keyRegistrarAddr),
 		ethcommon.HexToAddress(""),
 		ethcommon.HexToAddress(""),
+		l1Cfg.Transactions,
 		logger,
 	)
 	if err != nil {
 		return fmt.Errorf("failed to create contract caller: %w", err)
 	}
+	defer contractCaller.Close()
+	contractCaller.SetCallRecorder(common.CallRecorderFromContext(cCtx.Context))
 	// For each created operator set, configure the curve type
 	for _, opSet := range envCtx.OperatorSets {
 		logger.Info("Configuring curve type for operator set %s", opSet.OperatorSetID)
@@ -1639,7 +1631,9 @@ func CreateGenerationReservationAction(cCtx *cli.Context, logger iface.Logger) e
 	_, _, _, keyRegistrarAddr, crossChainRegistryAddr, bn254TableCalculatorAddr, _ := devnet.GetEigenLayerAddresses(cfg)
 
 	contractCaller, err := common.NewContractCaller(
+		cCtx.Context,
 		avsPrivateKeyOrGivenPermissionByAvs,
+		envCtx.Avs.Signer,
 		big.NewInt(int64(l1Cfg.ChainID)),
 		client,
 		ethcommon.HexToAddress(""),
@@ -1648,11 +1642,14 @@ func CreateGenerationReservationAction(cCtx *cli.Context, logger iface.Logger) e
 		ethcommon.HexToAddress(keyRegistrarAddr),
 		ethcommon.HexToAddress(crossChainRegistryAddr),
 		ethcommon.HexToAddress(""),
+		l1Cfg.Transactions,
 		logger,
 	)
 	if err != nil {
 		return fmt.Errorf("failed to create contract caller: %w", err)
 	}
+	defer contractCaller.Close()
+	contractCaller.SetCallRecorder(common.CallRecorderFromContext(cCtx.Context))
 
 	for _, opSet := range envCtx.OperatorSets {
 		err = contractCaller.CreateGenerationReservation(cCtx.Context, uint32(opSet.OperatorSetID), ethcommon.HexToAddress(bn254TableCalculatorAddr), avsAddress)
@@ -1700,7 +1697,9 @@ func WhitelistChainIdInCrossRegistryAction(cCtx *cli.Context, logger iface.Logge
 	avsPrivateKeyOrGivenPermissionByAvs := envCtx.Avs.AVSPrivateKey
 
 	contractCaller, err := common.NewContractCaller(
+		cCtx.Context,
 		avsPrivateKeyOrGivenPermissionByAvs,
+		nil,
 		big.NewInt(int64(l1Cfg.ChainID)),
 		client,
 		ethcommon.HexToAddress(""),
@@ -1709,11 +1708,13 @@ func WhitelistChainIdInCrossRegistryAction(cCtx *cli.Context, logger iface.Logge
 		ethcommon.HexToAddress(""),
 		crossChainRegistryAddr,
 		ethcommon.HexToAddress(""),
+		l1Cfg.Transactions,
 		logger,
 	)
 	if err != nil {
 		return fmt.Errorf("failed to create contract caller: %w", err)
 	}
+	defer contractCaller.Close()
 
 	err = contractCaller.WhitelistChainIdInCrossRegistry(cCtx.Context, operatorTableUpdater, uint64(l1Cfg.ChainID))
 	if err != nil {
@@ -1743,72 +1744,83 @@ func RegisterKeyInKeyRegistrarAction(cCtx *cli.Context, logger iface.Logger) err
 	if err != nil {
 		return fmt.Errorf("failed to connect to L1 RPC: %w", err)
 	}
+	defer client.Close()
 
 	avsAddress := ethcommon.HexToAddress(envCtx.Avs.Address)
 	_, _, _, keyRegistrarAddr, _, _, _ := devnet.GetEigenLayerAddresses(cfg)
 
 	for _, op := range envCtx.OperatorRegistrations {
-
 		for _, operator := range envCtx.Operators {
+			if op.Address != operator.Address {
+				continue
+			}
+			if err := registerOperatorKey(cCtx, logger, client, l1Cfg, ethcommon.HexToAddress(keyRegistrarAddr), avsAddress, op, operator); err != nil {
+				return err
+			}
+		}
+	}
+	logger.Info("Successfully registered keys in key registrar")
+	return nil
+}
 
-			if op.Address == operator.Address {
-				operatorPrivateKey := strings.Trim(operator.ECDSAKey, "0x")
-				operatorAddress := ethcommon.HexToAddress(op.Address)
-				contractCaller, err := common.NewContractCaller(
-					operatorPrivateKey,
-					big.NewInt(int64(l1Cfg.ChainID)),
-					client,
-					ethcommon.HexToAddress(""),
-					ethcommon.HexToAddress(""),
-					ethcommon.HexToAddress(""),
-					ethcommon.HexToAddress(keyRegistrarAddr),
-					ethcommon.HexToAddress(""),
-					ethcommon.HexToAddress(""),
-					logger,
-				)
-				if err != nil {
-					return fmt.Errorf("failed to create contract caller: %w", err)
-				}
-				blskeystorePath := operator.BlsKeystorePath
-				blskeystorePassword := operator.BlsKeystorePassword
-				keystoreData, err := keystore.LoadKeystoreFile(blskeystorePath)
-
-				if err != nil {
-					return fmt.Errorf("failed to load the keystore file from given path %s error %w", blskeystorePath, err)
-				}
-
-				privateKey, err := keystoreData.GetBN254PrivateKey(blskeystorePassword)
-				if err != nil {
-					return fmt.Errorf("failed to extract the private key from the keystore file")
+// registerOperatorKey registers the operator's BLS key for the operator set of a registration, with a contract caller
+// signing as the operator that is closed once the key is registered
+func registerOperatorKey(cCtx *cli.Context, logger iface.Logger, client *ethclient.Client, l1Cfg common.ChainConfig, keyRegistrarAddr, avsAddress ethcommon.Address, op common.OperatorRegistration, operator common.OperatorSpec) error {
+	operatorPrivateKey := strings.Trim(operator.ECDSAKey, "0x")
+	operatorAddress := ethcommon.HexToAddress(op.Address)
+	contractCaller, err := common.NewContractCaller(
+		cCtx.Context,
+		operatorPrivateKey,
+		operator.Signer,
+		big.NewInt(int64(l1Cfg.ChainID)),
+		client,
+		ethcommon.HexToAddress(""),
+		ethcommon.HexToAddress(""),
+		ethcommon.HexToAddress(""),
+		keyRegistrarAddr,
+		ethcommon.HexToAddress(""),
+		ethcommon.HexToAddress(""),
+		l1Cfg.Transactions,
+		logger,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create contract caller: %w", err)
+	}
+	defer contractCaller.Close()
 
-				}
+	blskeystorePath := operator.BlsKeystorePath
+	blskeystorePassword := operator.BlsKeystorePassword
+	keystoreData, err := keystore.LoadKeystoreFile(blskeystorePath)
+	if err != nil {
+		return fmt.Errorf("failed to load the keystore file from given path %s error %w", blskeystorePath, err)
+	}
 
-				keyData, err := contractCaller.EncodeBN254KeyData(privateKey.Public())
-				if err != nil {
-					return fmt.Errorf("failed to encode key data: %w", err)
-				}
+	privateKey, err := keystoreData.GetBN254PrivateKey(blskeystorePassword)
+	if err != nil {
+		return fmt.Errorf("failed to extract the private key from the keystore file")
+	}
 
-				messageHash, err := contractCaller.GetOperatorRegistrationMessageHash(cCtx.Context, operatorAddress, avsAddress, uint32(op.OperatorSetID), keyData)
-				if err != nil {
-					return fmt.Errorf("failed to get operator registration message hash: %w", err)
-				}
+	keyData, err := contractCaller.EncodeBN254KeyData(privateKey.Public())
+	if err != nil {
+		return fmt.Errorf("failed to encode key data: %w", err)
+	}
 
-				signature, err := privateKey.SignSolidityCompatible(messageHash)
-				if err != nil {
-					return fmt.Errorf("failed to sign message hash: %w", err)
-				}
+	messageHash, err := contractCaller.GetOperatorRegistrationMessageHash(cCtx.Context, operatorAddress, avsAddress, uint32(op.OperatorSetID), keyData)
+	if err != nil {
+		return fmt.Errorf("failed to get operator registration message hash: %w", err)
+	}
 
-				bn254Signature := bn254.Signature(*signature)
+	signature, err := privateKey.SignSolidityCompatible(messageHash)
+	if err != nil {
+		return fmt.Errorf("failed to sign message hash: %w", err)
+	}
 
-				err = contractCaller.RegisterKeyInKeyRegistrar(cCtx.Context, operatorAddress, avsAddress, uint32(op.OperatorSetID), keyData, bn254Signature)
-				if err != nil {
-					return fmt.Errorf("failed to register key in key registrar: %w", err)
-				}
-				logger.Info("Successfully registered key in key registrar for operator %s", operator.Address)
-			}
-		}
+	bn254Signature := bn254.Signature(*signature)
 
+	err = contractCaller.RegisterKeyInKeyRegistrar(cCtx.Context, operatorAddress, avsAddress, uint32(op.OperatorSetID), keyData, bn254Signature)
+	if err != nil {
+		return fmt.Errorf("failed to register key in key registrar: %w", err)
 	}
-	logger.Info("Successfully registered keys in key registrar")
+	logger.Info("Successfully registered key in key registrar for operator %s", operator.Address)
 	return nil
 }
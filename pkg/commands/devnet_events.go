@@ -0,0 +1,333 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/Layr-Labs/devkit-cli/pkg/common"
+	"github.com/Layr-Labs/devkit-cli/pkg/common/contracts"
+	"github.com/Layr-Labs/devkit-cli/pkg/common/devnet"
+	"github.com/Layr-Labs/devkit-cli/pkg/common/iface"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/urfave/cli/v2"
+)
+
+// Maximum number of blocks requested in a single eth_getLogs call
+const eventsBlockChunkSize = 2000
+
+// eventWatcher polls a single chain for logs emitted by the decoder's contracts
+type eventWatcher struct {
+	chain     string
+	client    *ethclient.Client
+	decoder   *contracts.EventDecoder
+	addresses []ethcommon.Address
+	nextBlock uint64
+}
+
+// DevnetEventsAction decodes and prints events emitted by the EigenLayer and AVS contracts of a context
+func DevnetEventsAction(cCtx *cli.Context) error {
+	logger := common.LoggerFromContext(cCtx.Context)
+	contextName := cCtx.String("context")
+
+	cfg, err := common.LoadConfigWithContextConfig(contextName)
+	if err != nil {
+		return fmt.Errorf("failed to load configurations: %w", err)
+	}
+	envCtx, ok := cfg.Context[contextName]
+	if !ok {
+		return fmt.Errorf("context '%s' not found in configuration", contextName)
+	}
+
+	indexed, err := contracts.ParseIndexedFilters(cCtx.StringSlice("where"))
+	if err != nil {
+		return err
+	}
+	filter := contracts.EventFilter{
+		Contracts: cCtx.StringSlice("contract"),
+		Events:    cCtx.StringSlice("event"),
+		Indexed:   indexed,
+	}
+
+	watchers, err := buildEventWatchers(cCtx, logger, contextName, envCtx, filter)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		for _, w := range watchers {
+			w.client.Close()
+		}
+	}()
+
+	jsonOutput := cCtx.Bool("json")
+	multiChain := len(watchers) > 1
+	for {
+		for _, w := range watchers {
+			events, err := w.poll(cCtx.Context, logger, filter)
+			if err != nil {
+				return fmt.Errorf("failed to fetch %s events: %w", w.chain, err)
+			}
+			for _, ev := range events {
+				if err := printDecodedEvent(cCtx.App.Writer, ev, jsonOutput, multiChain); err != nil {
+					return err
+				}
+			}
+		}
+
+		if !cCtx.Bool("follow") {
+			return nil
+		}
+
+		select {
+		case <-cCtx.Context.Done():
+			return nil
+		case <-time.After(cCtx.Duration("interval")):
+		}
+	}
+}
+
+// buildEventWatchers creates a watcher per configured chain, each holding the contracts deployed on it
+func buildEventWatchers(cCtx *cli.Context, logger iface.Logger, contextName string, envCtx common.ChainContextConfig, filter contracts.EventFilter) ([]*eventWatcher, error) {
+	avsABIs := loadDeployedContractABIs(logger, contextName, envCtx.DeployedContracts)
+
+	var watchers []*eventWatcher
+	seenRPC := map[string]*eventWatcher{}
+	for _, chainName := range []string{devnet.L1, devnet.L2} {
+		chainCfg, ok := envCtx.Chains[chainName]
+		if !ok || chainCfg.RPCURL == "" {
+			continue
+		}
+
+		// L1 and L2 may point to the same node, in which case a single watcher covers both
+		w, shared := seenRPC[chainCfg.RPCURL]
+		if !shared {
+			client, err := ethclient.Dial(chainCfg.RPCURL)
+			if err != nil {
+				return nil, fmt.Errorf("failed to connect to %s RPC at %s: %w", chainName, chainCfg.RPCURL, err)
+			}
+			w = &eventWatcher{
+				chain:   chainName,
+				client:  client,
+				decoder: contracts.NewEventDecoder(),
+			}
+			seenRPC[chainCfg.RPCURL] = w
+			watchers = append(watchers, w)
+		}
+
+		if err := addCoreContracts(w.decoder, envCtx.EigenLayer, chainName); err != nil {
+			return nil, err
+		}
+		for name, entry := range avsABIs {
+			w.decoder.AddContract(name, entry.address, entry.abi)
+		}
+	}
+	if len(watchers) == 0 {
+		return nil, fmt.Errorf("no chains with an rpc_url configured in context '%s'", contextName)
+	}
+
+	var active []*eventWatcher
+	for _, w := range watchers {
+		w.addresses = w.decoder.Addresses(filter.Contracts...)
+		if len(w.addresses) == 0 {
+			w.client.Close()
+			continue
+		}
+
+		head, err := w.client.BlockNumber(cCtx.Context)
+		if err != nil {
+			w.client.Close()
+			return nil, fmt.Errorf("failed to get %s block number: %w", w.chain, err)
+		}
+		w.nextBlock = startBlock(cCtx, head)
+		active = append(active, w)
+	}
+	if len(active) == 0 {
+		return nil, fmt.Errorf("no contracts matching %v found in context '%s'", filter.Contracts, contextName)
+	}
+	return active, nil
+}
+
+// startBlock resolves the first block to query from --from-block or the --blocks lookback
+func startBlock(cCtx *cli.Context, head uint64) uint64 {
+	if cCtx.IsSet("from-block") {
+		return cCtx.Uint64("from-block")
+	}
+	lookback := cCtx.Uint64("blocks")
+	if lookback > head {
+		return 0
+	}
+	return head - lookback + 1
+}
+
+// addCoreContracts registers the EigenLayer contracts that live on the given chain
+func addCoreContracts(decoder *contracts.EventDecoder, el *common.EigenLayerConfig, chainName string) error {
+	if el == nil {
+		return nil
+	}
+
+	var entries map[contracts.ContractType]string
+	switch chainName {
+	case devnet.L1:
+		entries = map[contracts.ContractType]string{
+			contracts.AllocationManagerContract:  el.L1.AllocationManager,
+			contracts.DelegationManagerContract:  el.L1.DelegationManager,
+			contracts.StrategyManagerContract:    el.L1.StrategyManager,
+			contracts.KeyRegistrarContract:       el.L1.KeyRegistrar,
+			contracts.CrossChainRegistryContract: el.L1.CrossChainRegistry,
+			contracts.ReleaseManagerContract:     el.L1.ReleaseManager,
+		}
+	case devnet.L2:
+		entries = map[contracts.ContractType]string{
+			contracts.OperatorTableUpdaterContract: el.L2.OperatorTableUpdater,
+		}
+	}
+
+	for contractType, address := range entries {
+		if address == "" {
+			continue
+		}
+		contractABI, err := contracts.GetBindingABI(contractType)
+		if err != nil {
+			return err
+		}
+		decoder.AddContract(string(contractType), ethcommon.HexToAddress(address), contractABI)
+	}
+	return nil
+}
+
+type deployedContractABI struct {
+	address ethcommon.Address
+	abi     *abi.ABI
+}
+
+// loadDeployedContractABIs reads the ABIs stored for the AVS contracts, preferring contracts/outputs/<context>
+func loadDeployedContractABIs(logger iface.Logger, contextName string, deployed []common.DeployedContract) map[string]deployedContractABI {
+	out := make(map[string]deployedContractABI, len(deployed))
+	for _, dc := range deployed {
+		if dc.Address == "" {
+			continue
+		}
+
+		paths := []string{filepath.Join("contracts", "outputs", contextName, dc.Name+".json")}
+		if dc.Abi != "" {
+			paths = append(paths, dc.Abi)
+		}
+
+		var parsed *abi.ABI
+		for _, path := range paths {
+			contractABI, err := readABIFile(path)
+			if err != nil {
+				logger.Debug("Skipping ABI for %s from %s: %v", dc.Name, path, err)
+				continue
+			}
+			parsed = contractABI
+			break
+		}
+		if parsed == nil {
+			logger.Warn("No ABI found for deployed contract %s, its events will not be decoded", dc.Name)
+			continue
+		}
+		out[dc.Name] = deployedContractABI{address: ethcommon.HexToAddress(dc.Address), abi: parsed}
+	}
+	return out
+}
+
+// readABIFile parses the "abi" field of a contract output or forge artifact
+func readABIFile(path string) (*abi.ABI, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var artifact struct {
+		ABI json.RawMessage `json:"abi"`
+	}
+	if err := json.Unmarshal(raw, &artifact); err != nil {
+		return nil, err
+	}
+	if len(artifact.ABI) == 0 {
+		return nil, fmt.Errorf("no abi field")
+	}
+	parsed, err := abi.JSON(strings.NewReader(string(artifact.ABI)))
+	if err != nil {
+		return nil, err
+	}
+	return &parsed, nil
+}
+
+// poll fetches and decodes all logs between the watcher's next block and the current head
+func (w *eventWatcher) poll(ctx context.Context, logger iface.Logger, filter contracts.EventFilter) ([]*contracts.DecodedEvent, error) {
+	head, err := w.client.BlockNumber(ctx)
+	if err != nil {
+		if errors.Is(ctx.Err(), context.Canceled) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var events []*contracts.DecodedEvent
+	for from := w.nextBlock; from <= head; from += eventsBlockChunkSize {
+		to := from + eventsBlockChunkSize - 1
+		if to > head {
+			to = head
+		}
+		logs, err := w.client.FilterLogs(ctx, ethereum.FilterQuery{
+			FromBlock: new(big.Int).SetUint64(from),
+			ToBlock:   new(big.Int).SetUint64(to),
+			Addresses: w.addresses,
+		})
+		if err != nil {
+			if errors.Is(ctx.Err(), context.Canceled) {
+				return events, nil
+			}
+			return nil, err
+		}
+
+		for _, log := range logs {
+			ev, err := w.decoder.Decode(log)
+			if errors.Is(err, contracts.ErrUnknownEvent) {
+				logger.Debug("Skipping undecodable log %s:%d from %s", log.TxHash.Hex(), log.Index, log.Address.Hex())
+				continue
+			}
+			if err != nil {
+				logger.Warn("Failed to decode log %s:%d: %v", log.TxHash.Hex(), log.Index, err)
+				continue
+			}
+			if !filter.Matches(ev) {
+				continue
+			}
+			ev.Chain = w.chain
+			events = append(events, ev)
+		}
+		w.nextBlock = to + 1
+	}
+	return events, nil
+}
+
+// printDecodedEvent writes an event to w as a JSON line or a human-readable line
+func printDecodedEvent(w io.Writer, ev *contracts.DecodedEvent, jsonOutput bool, withChain bool) error {
+	if jsonOutput {
+		b, err := json.Marshal(ev)
+		if err != nil {
+			return fmt.Errorf("failed to marshal event: %w", err)
+		}
+		_, err = fmt.Fprintln(w, string(b))
+		return err
+	}
+	if withChain {
+		_, err := fmt.Fprintf(w, "[%s] %s\n", ev.Chain, ev.String())
+		return err
+	}
+	_, err := fmt.Fprintln(w, ev.String())
+	return err
+}
@@ -0,0 +1,28 @@
+package commands
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/Layr-Labs/devkit-cli/pkg/common/contracts"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrintDecodedEvent(t *testing.T) {
+	ev := &contracts.DecodedEvent{
+		Chain:       "l1",
+		Contract:    "AllocationManager",
+		Event:       "OperatorSetCreated",
+		BlockNumber: 7,
+		TxHash:      "0xabc",
+		Args:        map[string]interface{}{"id": 1},
+	}
+
+	var out bytes.Buffer
+	require.NoError(t, printDecodedEvent(&out, ev, false, true))
+	require.Equal(t, "[l1] [block 7] AllocationManager.OperatorSetCreated(id=1) tx=0xabc log=0\n", out.String())
+
+	out.Reset()
+	require.NoError(t, printDecodedEvent(&out, ev, true, false))
+	require.JSONEq(t, `{"chain":"l1","contract":"AllocationManager","address":"","event":"OperatorSetCreated","blockNumber":7,"txHash":"0xabc","logIndex":0,"args":{"id":1}}`, out.String())
+}
@@ -0,0 +1,293 @@
+package commands
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Layr-Labs/devkit-cli/config/configs"
+	"github.com/Layr-Labs/devkit-cli/config/contexts"
+	"github.com/Layr-Labs/devkit-cli/pkg/common"
+	"github.com/Layr-Labs/devkit-cli/pkg/common/iface"
+	"github.com/Layr-Labs/devkit-cli/pkg/migration"
+	"github.com/urfave/cli/v2"
+)
+
+// migrationBackupDir holds a timestamped copy of the project's config for every migration
+var migrationBackupDir = filepath.Join("config", ".backups")
+
+// migrationBackupLayout names backups so they sort by time
+const migrationBackupLayout = "20060102-150405"
+
+// MigrateCommand brings config.yaml and the contexts up to the versions this devkit expects
+var MigrateCommand = &cli.Command{
+	Name:  "migrate",
+	Usage: "Migrate config.yaml and contexts to the latest version, backing them up first",
+	Flags: append([]cli.Flag{
+		&cli.BoolFlag{
+			Name:  "dry-run",
+			Usage: "Print the changes as a unified diff without writing them",
+		},
+	}, common.GlobalFlags...),
+	Subcommands: []*cli.Command{
+		{
+			Name:      "rollback",
+			Usage:     "Restore config.yaml and contexts from a migration backup",
+			ArgsUsage: "[backup]",
+			Flags: append([]cli.Flag{
+				&cli.BoolFlag{
+					Name:  "list",
+					Usage: "List the available backups",
+				},
+				&cli.BoolFlag{
+					Name:  "yes",
+					Usage: "Overwrite the current files without asking",
+				},
+			}, common.GlobalFlags...),
+			Action: MigrateRollbackAction,
+		},
+	},
+	Action: MigrateAction,
+}
+
+// MigrateAction migrates the project, or prints what would change with --dry-run
+func MigrateAction(cCtx *cli.Context) error {
+	logger := common.LoggerFromContext(cCtx.Context)
+
+	pending, err := pendingMigrations()
+	if err != nil {
+		return err
+	}
+	if len(pending) == 0 {
+		logger.Info("Config and contexts are up to date")
+		return nil
+	}
+
+	if cCtx.Bool("dry-run") {
+		for _, p := range pending {
+			diff, err := p.Diff()
+			if err != nil {
+				return fmt.Errorf("failed to diff %s: %w", p.Path, err)
+			}
+			fmt.Fprint(cCtx.App.Writer, diff)
+		}
+		logger.Info("%d files would be migrated, run without --dry-run to apply", len(pending))
+		return nil
+	}
+
+	_, err = applyMigrations(logger, pending)
+	return err
+}
+
+// MigrateRollbackAction restores the files of a backup, the latest one unless named
+func MigrateRollbackAction(cCtx *cli.Context) error {
+	logger := common.LoggerFromContext(cCtx.Context)
+
+	backups, err := listMigrationBackups()
+	if err != nil {
+		return err
+	}
+	if cCtx.Bool("list") {
+		for _, backup := range backups {
+			fmt.Fprintln(cCtx.App.Writer, backup)
+		}
+		return nil
+	}
+	if len(backups) == 0 {
+		return fmt.Errorf("no backups in %s", migrationBackupDir)
+	}
+
+	backup := backups[len(backups)-1]
+	if cCtx.Args().Len() > 0 {
+		backup = cCtx.Args().First()
+	}
+	// Backups are named by their timestamp, anything else could restore files from outside the backup directory
+	if !filepath.IsLocal(backup) || filepath.Base(backup) != backup {
+		return fmt.Errorf("invalid backup %q, use a name from `devkit avs migrate rollback --list`", backup)
+	}
+	dir := filepath.Join(migrationBackupDir, backup)
+	if _, err := os.Stat(dir); err != nil {
+		return fmt.Errorf("backup %s not found, see `devkit avs migrate rollback --list`", backup)
+	}
+
+	if !cCtx.Bool("yes") {
+		fmt.Fprintf(cCtx.App.Writer, "Overwrite config.yaml and the contexts with backup %s? [y/N]: ", backup)
+		response, _ := bufio.NewReader(cCtx.App.Reader).ReadString('\n')
+		if response = strings.ToLower(strings.TrimSpace(response)); response != "y" && response != "yes" {
+			return fmt.Errorf("rollback cancelled, pass --yes to restore without asking")
+		}
+	}
+
+	restored, err := restoreMigrationBackup(dir)
+	if err != nil {
+		return err
+	}
+	for _, path := range restored {
+		logger.Info("Restored %s", path)
+	}
+	logger.Info("Rolled back to backup %s", backup)
+	return nil
+}
+
+// pendingMigrations plans the migration of config.yaml and every context, failing if any of them cannot be migrated.
+// Only root contexts are brought up to the latest template, contexts that extend another and local overlays hold the
+// settings they override and only get their existing keys migrated.
+func pendingMigrations() ([]*migration.PendingMigration, error) {
+	var pending []*migration.PendingMigration
+	add := func(p *migration.PendingMigration, err error) error {
+		if errors.Is(err, migration.ErrAlreadyUpToDate) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		pending = append(pending, p)
+		return nil
+	}
+
+	if err := add(migration.PlanYaml(filepath.Join("config", common.BaseConfig), configs.LatestVersion, configs.MigrationChain)); err != nil {
+		return nil, err
+	}
+
+	contextDir := filepath.Join("config", "contexts")
+	entries, err := os.ReadDir(contextDir)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read context directory: %v", err)
+	}
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), ".yaml") {
+			continue
+		}
+		path := filepath.Join(contextDir, e.Name())
+
+		// Local overlays have no version of their own, they follow their context
+		if common.IsLocalContextFile(e.Name()) {
+			contextPath := filepath.Join(contextDir, strings.TrimSuffix(e.Name(), common.LocalContextSuffix)+".yaml")
+			doc, err := common.LoadYAML(contextPath)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read the context of %s: %w", path, err)
+			}
+			version := migration.ResolveNode(doc, []string{"version"})
+			if version == nil {
+				return nil, fmt.Errorf("no version field %s", contextPath)
+			}
+			if err := add(migration.PlanOverlayYaml(path, version.Value, contexts.LatestVersion, contexts.MigrationChain)); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		doc, err := common.LoadYAML(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		if migration.ResolveNode(doc, []string{"extends"}) != nil {
+			err = add(migration.PlanOverlayYaml(path, "", contexts.LatestVersion, contexts.MigrationChain))
+		} else {
+			err = add(migration.PlanYaml(path, contexts.LatestVersion, contexts.MigrationChain))
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return pending, nil
+}
+
+// applyMigrations backs up the files of pending, then writes their migrations. If a write fails every file is
+// restored from the backup, so the project is never left half-migrated. It returns the backup directory.
+func applyMigrations(logger iface.Logger, pending []*migration.PendingMigration) (string, error) {
+	dir, err := writeMigrationBackup(pending)
+	if err != nil {
+		return "", err
+	}
+	logger.Info("Backed up %d files to %s", len(pending), dir)
+
+	for _, p := range pending {
+		logger.Info("Migrating %s v%s -> v%s", p.Path, p.From, p.To)
+		if err := p.Write(); err != nil {
+			if _, restoreErr := restoreMigrationBackup(dir); restoreErr != nil {
+				return dir, fmt.Errorf("%w, and restoring %s failed: %v", err, dir, restoreErr)
+			}
+			return dir, fmt.Errorf("%w, restored the files from %s", err, dir)
+		}
+	}
+	logger.Info("Migrated %d files, undo with `devkit avs migrate rollback`", len(pending))
+	return dir, nil
+}
+
+// writeMigrationBackup copies the files of pending into a new timestamped backup directory
+func writeMigrationBackup(pending []*migration.PendingMigration) (string, error) {
+	name := time.Now().UTC().Format(migrationBackupLayout)
+	dir := filepath.Join(migrationBackupDir, name)
+	for i := 1; ; i++ {
+		if _, err := os.Stat(dir); errors.Is(err, os.ErrNotExist) {
+			break
+		}
+		dir = filepath.Join(migrationBackupDir, fmt.Sprintf("%s-%d", name, i))
+	}
+
+	for _, p := range pending {
+		// Keep the path relative to config/ so rollback knows where each file goes
+		rel, err := filepath.Rel("config", p.Path)
+		if err != nil {
+			return "", fmt.Errorf("failed to back up %s: %w", p.Path, err)
+		}
+		dst := filepath.Join(dir, rel)
+		if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+			return "", fmt.Errorf("failed to create backup dir: %w", err)
+		}
+		if err := os.WriteFile(dst, p.Before, 0644); err != nil {
+			return "", fmt.Errorf("failed to back up %s: %w", p.Path, err)
+		}
+	}
+	return dir, nil
+}
+
+// restoreMigrationBackup copies every file of a backup back under config/
+func restoreMigrationBackup(dir string) ([]string, error) {
+	var restored []string
+	err := filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		dst := filepath.Join("config", rel)
+		if err := os.WriteFile(dst, data, 0644); err != nil {
+			return fmt.Errorf("failed to restore %s: %w", dst, err)
+		}
+		restored = append(restored, dst)
+		return nil
+	})
+	return restored, err
+}
+
+// listMigrationBackups returns the backup names, oldest first
+func listMigrationBackups() ([]string, error) {
+	entries, err := os.ReadDir(migrationBackupDir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", migrationBackupDir, err)
+	}
+	var backups []string
+	for _, e := range entries {
+		if e.IsDir() {
+			backups = append(backups, e.Name())
+		}
+	}
+	sort.Strings(backups)
+	return backups, nil
+}
@@ -0,0 +1,164 @@
+package commands
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Layr-Labs/devkit-cli/config/configs"
+	"github.com/Layr-Labs/devkit-cli/config/contexts"
+	"github.com/Layr-Labs/devkit-cli/pkg/common"
+	"github.com/Layr-Labs/devkit-cli/pkg/common/logger"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli/v2"
+)
+
+// setupMigrateProject writes an old config.yaml and devnet context into a temp project and moves into it
+func setupMigrateProject(t *testing.T) (configPath, contextPath string) {
+	tmp := t.TempDir()
+	configPath = filepath.Join("config", common.BaseConfig)
+	contextPath = filepath.Join("config", "contexts", "devnet.yaml")
+	require.NoError(t, os.MkdirAll(filepath.Join(tmp, "config", "contexts"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(tmp, configPath), configs.ConfigYamls["0.0.1"], 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(tmp, contextPath), contexts.ContextYamls["0.0.7"], 0644))
+
+	orig, err := os.Getwd()
+	require.NoError(t, err)
+	t.Cleanup(func() { require.NoError(t, os.Chdir(orig)) })
+	require.NoError(t, os.Chdir(tmp))
+	return configPath, contextPath
+}
+
+func runMigrate(t *testing.T, args ...string) (string, error) {
+	return runMigrateWithInput(t, "", args...)
+}
+
+// runMigrateWithInput runs `migrate <args...>` answering prompts with input
+func runMigrateWithInput(t *testing.T, input string, args ...string) (string, error) {
+	var out bytes.Buffer
+	app := &cli.App{
+		Name:     "devkit",
+		Reader:   strings.NewReader(input),
+		Writer:   &out,
+		Commands: []*cli.Command{MigrateCommand},
+		Before: func(cCtx *cli.Context) error {
+			cCtx.Context = common.WithLogger(cCtx.Context, logger.NewNoopLogger())
+			return nil
+		},
+	}
+	err := app.Run(append([]string{"devkit", "migrate"}, args...))
+	return out.String(), err
+}
+
+func TestMigrateDryRunWritesNothing(t *testing.T) {
+	configPath, contextPath := setupMigrateProject(t)
+
+	out, err := runMigrate(t, "--dry-run")
+	require.NoError(t, err)
+	require.Contains(t, out, "--- config/config.yaml (v0.0.1)\n+++ config/config.yaml (v"+configs.LatestVersion+")")
+	require.Contains(t, out, "--- config/contexts/devnet.yaml (v0.0.7)\n+++ config/contexts/devnet.yaml (v"+contexts.LatestVersion+")")
+	require.Contains(t, out, "-version: 0.0.7\n+version: "+contexts.LatestVersion)
+
+	data, err := os.ReadFile(contextPath)
+	require.NoError(t, err)
+	require.Equal(t, contexts.ContextYamls["0.0.7"], data)
+	data, err = os.ReadFile(configPath)
+	require.NoError(t, err)
+	require.Equal(t, configs.ConfigYamls["0.0.1"], data)
+	require.NoDirExists(t, migrationBackupDir)
+}
+
+func TestMigrateBacksUpAndRollsBack(t *testing.T) {
+	configPath, contextPath := setupMigrateProject(t)
+
+	_, err := runMigrate(t)
+	require.NoError(t, err)
+	doc, err := common.LoadYAML(contextPath)
+	require.NoError(t, err)
+	require.Equal(t, contexts.LatestVersion, common.GetChildByKey(doc.Content[0], "version").Value)
+
+	// The originals are kept in a timestamped backup
+	backups, err := listMigrationBackups()
+	require.NoError(t, err)
+	require.Len(t, backups, 1)
+	data, err := os.ReadFile(filepath.Join(migrationBackupDir, backups[0], "contexts", "devnet.yaml"))
+	require.NoError(t, err)
+	require.Equal(t, contexts.ContextYamls["0.0.7"], data)
+
+	// Nothing is left to migrate
+	out, err := runMigrate(t, "--dry-run")
+	require.NoError(t, err)
+	require.Empty(t, out)
+
+	// Backup names stay inside the backup directory, and the current files are only overwritten when confirmed
+	_, err = runMigrate(t, "rollback", "--yes", "../contexts")
+	require.ErrorContains(t, err, "invalid backup")
+	_, err = runMigrate(t, "rollback", "--yes", "/tmp")
+	require.ErrorContains(t, err, "invalid backup")
+	_, err = runMigrate(t, "rollback")
+	require.ErrorContains(t, err, "rollback cancelled")
+	data, err = os.ReadFile(contextPath)
+	require.NoError(t, err)
+	require.NotEqual(t, contexts.ContextYamls["0.0.7"], data)
+
+	_, err = runMigrateWithInput(t, "y\n", "rollback")
+	require.NoError(t, err)
+	data, err = os.ReadFile(contextPath)
+	require.NoError(t, err)
+	require.Equal(t, contexts.ContextYamls["0.0.7"], data)
+	data, err = os.ReadFile(configPath)
+	require.NoError(t, err)
+	require.Equal(t, configs.ConfigYamls["0.0.1"], data)
+}
+
+func TestMigrateFailureWritesNothing(t *testing.T) {
+	configPath, _ := setupMigrateProject(t)
+	broken := filepath.Join("config", "contexts", "broken.yaml")
+	require.NoError(t, os.WriteFile(broken, []byte("version: 9.9.9\ncontext:\n  name: broken\n"), 0644))
+
+	_, err := runMigrate(t)
+	require.ErrorContains(t, err, "broken.yaml")
+
+	// config.yaml migrates fine on its own but is left alone with the rest
+	data, err := os.ReadFile(configPath)
+	require.NoError(t, err)
+	require.Equal(t, configs.ConfigYamls["0.0.1"], data)
+	require.NoDirExists(t, migrationBackupDir)
+}
+
+func TestMigrateLayeredContexts(t *testing.T) {
+	_, contextPath := setupMigrateProject(t)
+	testnetPath := filepath.Join("config", "contexts", "testnet.yaml")
+	localPath := filepath.Join("config", "contexts", "devnet.local.yaml")
+	testnet := "version: 0.0.7\nextends: devnet\ncontext:\n  name: testnet\n  artifact:\n    artifactId: sha256:1111\n    component: avs\n    registry: ghcr.io/acme/avs\n"
+	local := "context:\n  chains:\n    l1:\n      rpc_url: http://localhost:9545\n"
+	require.NoError(t, os.WriteFile(testnetPath, []byte(testnet), 0644))
+	require.NoError(t, os.WriteFile(localPath, []byte(local), 0644))
+
+	_, err := runMigrate(t)
+	require.NoError(t, err)
+
+	// The root context gets the sections of the latest template
+	doc, err := common.LoadYAML(contextPath)
+	require.NoError(t, err)
+	require.NotNil(t, common.GetChildByKey(common.GetChildByKey(doc.Content[0], "context"), "artifact"))
+
+	// A context extending it only gets its own keys migrated
+	doc, err = common.LoadYAML(testnetPath)
+	require.NoError(t, err)
+	require.Equal(t, contexts.LatestVersion, common.GetChildByKey(doc.Content[0], "version").Value)
+	artifact := common.GetChildByKey(common.GetChildByKey(doc.Content[0], "context"), "artifact")
+	var migrated common.ArtifactConfig
+	require.NoError(t, artifact.Decode(&migrated))
+	require.Equal(t, "ghcr.io/acme/avs", migrated.Registry)
+	require.Equal(t, []common.ArtifactComponent{{Name: "avs", Image: "avs", ArtifactId: "sha256:1111"}}, migrated.Components)
+	require.Nil(t, common.GetChildByKey(artifact, "version"))
+	require.Nil(t, common.GetChildByKey(common.GetChildByKey(doc.Content[0], "context"), "operators"))
+
+	// The local overlay had nothing to migrate, no defaults are added to it
+	data, err := os.ReadFile(localPath)
+	require.NoError(t, err)
+	require.Equal(t, local, string(data))
+}
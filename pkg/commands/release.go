@@ -5,19 +5,23 @@ import (
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"io"
 	"math/big"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/Layr-Labs/devkit-cli/pkg/common"
+	"github.com/Layr-Labs/devkit-cli/pkg/common/contracts"
 	"github.com/Layr-Labs/devkit-cli/pkg/common/devnet"
 	"github.com/Layr-Labs/devkit-cli/pkg/common/iface"
 	releasemanager "github.com/Layr-Labs/eigenlayer-contracts/pkg/bindings/ReleaseManager"
 	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/urfave/cli/v2"
 	"gopkg.in/yaml.v3"
@@ -27,6 +31,8 @@ import (
 type OperatorSetRelease struct {
 	Digest   string `json:"digest"`
 	Registry string `json:"registry"`
+	// Platforms found in the registry when the artifact was verified
+	Platforms []string `json:"platforms,omitempty"`
 }
 
 // parseOperatorSetMapping parses the JSON output from the release script
@@ -47,46 +53,8 @@ func parseOperatorSetMapping(jsonOutput string) (map[string][]OperatorSetRelease
 	return releases, nil
 }
 
-// updateContextWithDigest updates the context YAML file with the digest after successful release
-func updateContextWithDigest(digest string) error {
-	// Load the context yaml file
-	contextPath := filepath.Join("config", "contexts", "devnet.yaml") // TODO: make context configurable
-	contextNode, err := common.LoadYAML(contextPath)
-	if err != nil {
-		return fmt.Errorf("failed to load context yaml: %w", err)
-	}
-
-	// Get the root node (first content node)
-	rootNode := contextNode.Content[0]
-
-	// Get the context section
-	contextSection := common.GetChildByKey(rootNode, "context")
-	if contextSection == nil {
-		return fmt.Errorf("context section not found in yaml")
-	}
-
-	// Get or create artifacts section
-	artifactsSection := common.GetChildByKey(contextSection, "artifact")
-	if artifactsSection == nil {
-		return fmt.Errorf("artifact section not found in context")
-	}
-
-	// Update digest field
-	common.SetMappingValue(artifactsSection,
-		&yaml.Node{Kind: yaml.ScalarNode, Value: "digest"},
-		&yaml.Node{Kind: yaml.ScalarNode, Value: digest})
-
-	// Write the updated yaml back to file
-	if err := common.WriteYAML(contextPath, contextNode); err != nil {
-		return fmt.Errorf("failed to write updated yaml: %w", err)
-	}
-
-	return nil
-}
-
-// updateContextWithVersion updates the context YAML file with the new version
-func updateContextWithVersion(version string) error {
-	// Load the context yaml file
+// updateContextWithRelease records the published version, and the digest of each released component, in a single write
+func updateContextWithRelease(version string, digests map[string]string) error {
 	yamlPath, rootNode, contextNode, err := common.LoadContext(devnet.DEVNET_CONTEXT)
 	if err != nil {
 		return err
@@ -101,16 +69,30 @@ func updateContextWithVersion(version string) error {
 			artifactSection)
 	}
 
-	// Update version field
 	common.SetMappingValue(artifactSection,
 		&yaml.Node{Kind: yaml.ScalarNode, Value: "version"},
 		&yaml.Node{Kind: yaml.ScalarNode, Value: version})
 
-	// Write the updated yaml back to file
-	if err := common.WriteYAML(yamlPath, rootNode); err != nil {
-		return fmt.Errorf("failed to write updated yaml: %w", err)
+	if components := common.GetChildByKey(artifactSection, "components"); components != nil && components.Kind == yaml.SequenceNode {
+		for _, component := range components.Content {
+			name := common.GetChildByKey(component, "name")
+			if name == nil || digests[name.Value] == "" {
+				continue
+			}
+			common.SetMappingValue(component,
+				&yaml.Node{Kind: yaml.ScalarNode, Value: "digest"},
+				&yaml.Node{Kind: yaml.ScalarNode, Value: digests[name.Value]})
+		}
+	} else if component := common.GetChildByKey(artifactSection, "component"); component != nil && digests[component.Value] != "" {
+		// Contexts before 0.0.8 hold a single component
+		common.SetMappingValue(artifactSection,
+			&yaml.Node{Kind: yaml.ScalarNode, Value: "digest"},
+			&yaml.Node{Kind: yaml.ScalarNode, Value: digests[component.Value]})
 	}
 
+	if err := common.WriteContext(yamlPath, rootNode); err != nil {
+		return fmt.Errorf("failed to write updated yaml: %w", err)
+	}
 	return nil
 }
 
@@ -118,11 +100,11 @@ func updateContextWithVersion(version string) error {
 var ReleaseCommand = &cli.Command{
 	Name:  "release",
 	Usage: "Manage AVS releases and artifacts",
-	Subcommands: []*cli.Command{
+	Subcommands: append([]*cli.Command{
 		{
 			Name:  "publish",
 			Usage: "Publish a new AVS release",
-			Flags: append(common.GlobalFlags, []cli.Flag{
+			Flags: append(common.GlobalFlags, append([]cli.Flag{
 				&cli.Int64Flag{
 					Name:     "upgrade-by-time",
 					Usage:    "Unix timestamp by which the upgrade must be completed",
@@ -130,78 +112,215 @@ var ReleaseCommand = &cli.Command{
 				},
 				&cli.StringFlag{
 					Name:  "registry",
-					Usage: "Registry to use for the release. If not provided, will use registry from context",
+					Usage: "Registry to use for components without their own registry. If not provided, will use registry from context",
+				},
+				&cli.StringFlag{
+					Name:  "version",
+					Usage: "Semver version of the release, must be greater than the last published version",
+				},
+				&cli.StringFlag{
+					Name:  "bump",
+					Usage: "Derive the version by bumping the last published version: major, minor or patch (default: patch)",
+				},
+				&cli.StringFlag{
+					Name:  "metadata-uri",
+					Usage: "URI of the release notes (changelog, compatibility notes), recorded in the manifest and release history",
+				},
+				&cli.BoolFlag{
+					Name:  "skip-verify",
+					Usage: "Publish the digests reported by the release script without resolving them in their registries",
+				},
+				&cli.BoolFlag{
+					Name:  "dry-run",
+					Usage: "Run the release script and print the artifacts and calldata per operator set without publishing",
 				},
-			}...),
+				&cli.StringFlag{
+					Name:  "manifest",
+					Usage: "Where to write the release manifest for review",
+					Value: "release-manifest.json",
+				},
+			}, CalldataFlags...)...),
 			Action: publishReleaseAction,
 		},
-	},
+	}, releaseQueryCommands...),
 }
 
-// processOperatorSets processes each operator set and publishes releases on chain
-func processOperatorSetsAndPublishReleaseOnChain(cCtx *cli.Context, logger iface.Logger, operatorSetMapping map[string][]OperatorSetRelease, avs string, upgradeByTime int64, registry string) error {
-	// Publish releases for each operator set
+// ReleaseManifest describes everything a release publishes, written before anything is sent so it can be reviewed
+type ReleaseManifest struct {
+	Avs             string                       `json:"avs"`
+	ReleaseManager  string                       `json:"releaseManager"`
+	ChainID         uint64                       `json:"chainId"`
+	PreviousVersion string                       `json:"previousVersion"`
+	Version         string                       `json:"version"`
+	MetadataURI     string                       `json:"metadataUri,omitempty"`
+	Registry        string                       `json:"registry"`
+	UpgradeByTime   int64                        `json:"upgradeByTime"`
+	DryRun          bool                         `json:"dryRun"`
+	Verified        bool                         `json:"verified"`
+	Components      []ReleaseManifestComponent   `json:"components"`
+	OperatorSets    []ReleaseManifestOperatorSet `json:"operatorSets"`
+}
+
+// ReleaseManifestComponent is a build component and the digest its image was released under
+type ReleaseManifestComponent struct {
+	Name         string   `json:"name"`
+	Image        string   `json:"image"`
+	Registry     string   `json:"registry"`
+	Digest       string   `json:"digest"`
+	OperatorSets []uint32 `json:"operatorSets"`
+}
+
+// ReleaseManifestOperatorSet is the release of one operator set and the publishRelease call that carries it
+type ReleaseManifestOperatorSet struct {
+	OperatorSetID uint32               `json:"operatorSetId"`
+	Artifacts     []OperatorSetRelease `json:"artifacts"`
+	Calldata      string               `json:"calldata"`
+}
+
+// buildReleaseManifest validates the release script's mapping and encodes the publishRelease call of each operator set.
+// Operator sets are ordered by ID so the manifest and the publish order are stable.
+func buildReleaseManifest(manifest *ReleaseManifest, operatorSetMapping map[string][]OperatorSetRelease) error {
+	releaseManagerABI, err := contracts.GetBindingABI(contracts.ReleaseManagerContract)
+	if err != nil {
+		return err
+	}
+
+	manifest.OperatorSets = make([]ReleaseManifestOperatorSet, 0, len(operatorSetMapping))
 	for opSetId, opSetDataArray := range operatorSetMapping {
 		opSetIdInt, err := strconv.ParseUint(opSetId, 10, 32)
 		if err != nil {
-			logger.Warn("Failed to parse operator set ID %s: %v", opSetId, err)
-			continue
+			return fmt.Errorf("invalid operator set ID %q in release script output: %w", opSetId, err)
 		}
 
-		logger.Info("Processing operator set %s with %d artifacts:", opSetId, len(opSetDataArray))
-
-		// Create artifacts array for this operator set
-		var artifacts []releasemanager.IReleaseManagerTypesArtifact
+		artifacts := make([]releasemanager.IReleaseManagerTypesArtifact, 0, len(opSetDataArray))
 		for i, opSetData := range opSetDataArray {
-			logger.Info("Artifact %d:", i+1)
-			logger.Info("Digest: %s", opSetData.Digest)
-			logger.Info("Registry: %s", opSetData.Registry)
-
-			// this means this is the component
-			if opSetData.Registry == registry {
-				err := updateContextWithDigest(opSetData.Digest)
-				if err != nil {
-					logger.Warn("Failed to update context with digest for operator set %s artifact %d: %v", opSetId, i+1, err)
-					continue
-				}
-				logger.Info("Successfully updated context with digest for operator set %s artifact %d", opSetId, i+1)
-			}
-
-			// Convert digest to bytes32
 			digestBytes, err := hexStringToBytes32(opSetData.Digest)
 			if err != nil {
-				logger.Warn("Failed to convert digest to bytes32 for operator set %s artifact %d: %v", opSetId, i+1, err)
-				continue
+				return fmt.Errorf("invalid digest for operator set %s artifact %d: %w", opSetId, i+1, err)
 			}
-
-			artifact := releasemanager.IReleaseManagerTypesArtifact{
+			artifacts = append(artifacts, releasemanager.IReleaseManagerTypesArtifact{
 				Digest:      digestBytes,
 				RegistryUrl: opSetData.Registry,
+			})
+		}
+
+		calldata, err := releaseManagerABI.Pack("publishRelease",
+			releasemanager.OperatorSet{Avs: ethcommon.HexToAddress(manifest.Avs), Id: uint32(opSetIdInt)},
+			releasemanager.IReleaseManagerTypesRelease{Artifacts: artifacts, UpgradeByTime: uint32(manifest.UpgradeByTime)},
+		)
+		if err != nil {
+			return fmt.Errorf("failed to encode publishRelease for operator set %s: %w", opSetId, err)
+		}
+
+		manifest.OperatorSets = append(manifest.OperatorSets, ReleaseManifestOperatorSet{
+			OperatorSetID: uint32(opSetIdInt),
+			Artifacts:     opSetDataArray,
+			Calldata:      hexutil.Encode(calldata),
+		})
+	}
+	sort.Slice(manifest.OperatorSets, func(i, j int) bool {
+		return manifest.OperatorSets[i].OperatorSetID < manifest.OperatorSets[j].OperatorSetID
+	})
+	return nil
+}
+
+// componentDigest returns the digest of the artifact a component's release script pushed to its registry, if any
+func componentDigest(mapping map[string][]OperatorSetRelease, registry string) string {
+	opsets := make([]string, 0, len(mapping))
+	for opset := range mapping {
+		opsets = append(opsets, opset)
+	}
+	sort.Strings(opsets)
+	for _, opset := range opsets {
+		for _, artifact := range mapping[opset] {
+			if artifact.Registry == registry {
+				return artifact.Digest
 			}
-			artifacts = append(artifacts, artifact)
 		}
+	}
+	return ""
+}
 
-		if len(artifacts) == 0 {
-			logger.Warn("No valid artifacts for operator set %s, skipping", opSetId)
-			continue
+// mergeComponentMapping adds a component's artifacts to the release mapping. A component listing its operator sets
+// releases every artifact its script reported to exactly those sets, otherwise the script's mapping is used as is.
+func mergeComponentMapping(mapping, componentMapping map[string][]OperatorSetRelease, operatorSets []uint32) {
+	if len(operatorSets) > 0 {
+		opsets := make([]string, 0, len(componentMapping))
+		for opset := range componentMapping {
+			opsets = append(opsets, opset)
+		}
+		sort.Strings(opsets)
+		var artifacts []OperatorSetRelease
+		for _, opset := range opsets {
+			artifacts = append(artifacts, componentMapping[opset]...)
 		}
 
-		logger.Info("Publishing release for operator set %s with %d artifacts...", opSetId, len(artifacts))
-		if err := publishReleaseToReleaseManagerAction(cCtx.Context, logger, avs, uint32(opSetIdInt), upgradeByTime, artifacts); err != nil {
-			if strings.Contains(err.Error(), "connection refused") {
-				logger.Warn("Failed to publish release for operator set %s: %v", opSetId, err)
-				logger.Info("Check if devnet is running and try again")
-				return err
+		componentMapping = make(map[string][]OperatorSetRelease, len(operatorSets))
+		for _, id := range operatorSets {
+			componentMapping[strconv.FormatUint(uint64(id), 10)] = artifacts
+		}
+	}
+
+	for opset, artifacts := range componentMapping {
+		for _, artifact := range artifacts {
+			duplicate := false
+			for _, existing := range mapping[opset] {
+				if existing.Digest == artifact.Digest && existing.Registry == artifact.Registry {
+					duplicate = true
+					break
+				}
+			}
+			if !duplicate {
+				mapping[opset] = append(mapping[opset], artifact)
 			}
 		}
-		logger.Info("Successfully published release for operator set %s", opSetId)
 	}
+}
 
+// writeReleaseManifest stores the manifest as indented JSON
+func writeReleaseManifest(path string, manifest *ReleaseManifest) error {
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode release manifest: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write release manifest: %w", err)
+	}
 	return nil
 }
 
+// writeReleasePlan prints the artifacts and calldata of each operator set for a dry run
+func writeReleasePlan(out io.Writer, manifest *ReleaseManifest) {
+	fmt.Fprintf(out, "Release %s (previous %s) of AVS %s\n", manifest.Version, manifest.PreviousVersion, manifest.Avs)
+	fmt.Fprintf(out, "Upgrade by: %s\n", time.Unix(manifest.UpgradeByTime, 0).UTC().Format(time.RFC3339))
+	if manifest.MetadataURI != "" {
+		fmt.Fprintf(out, "Metadata:   %s\n", manifest.MetadataURI)
+	}
+	for _, component := range manifest.Components {
+		fmt.Fprintf(out, "Component:  %s (%s) -> %s@%s\n", component.Name, component.Image, component.Registry, component.Digest)
+	}
+	for _, opset := range manifest.OperatorSets {
+		fmt.Fprintf(out, "\nOperator set %d\n", opset.OperatorSetID)
+		for i, artifact := range opset.Artifacts {
+			fmt.Fprintf(out, "  artifact %d: %s@%s\n", i+1, artifact.Registry, artifact.Digest)
+			if len(artifact.Platforms) > 0 {
+				fmt.Fprintf(out, "              platforms %s\n", strings.Join(artifact.Platforms, ", "))
+			}
+		}
+		fmt.Fprintf(out, "  to:       %s (chain %d)\n", manifest.ReleaseManager, manifest.ChainID)
+		fmt.Fprintf(out, "  calldata: %s\n", opset.Calldata)
+	}
+}
+
 func publishReleaseAction(cCtx *cli.Context) error {
 	logger := common.LoggerFromContext(cCtx.Context)
+	dryRun := cCtx.Bool("dry-run")
+
+	// Record the PublishRelease calls instead of sending them when the AVS is owned by a multisig
+	recorder, err := setupCallRecorder(cCtx)
+	if err != nil {
+		return err
+	}
 
 	// Get values from flags
 	upgradeByTime := cCtx.Int64("upgrade-by-time")
@@ -224,10 +343,58 @@ func publishReleaseAction(cCtx *cli.Context) error {
 		return fmt.Errorf("AVS addressempty in context")
 	}
 
-	version := artifact.Version
 	// first time publishing, version is empty
-	if version == "" {
-		version = "0"
+	lastVersion, err := normalizeReleaseVersion(artifact.Version)
+	if err != nil {
+		return err
+	}
+	contextVersion := lastVersion
+
+	// Releases already onchain may carry a higher version than the context. Onchain releases are only numbered, their
+	// versions are looked up in the local .devkit/releases/<context>.jsonl history. A latest release missing from it
+	// was published from another checkout and could carry any version, so publishing stops until it is recorded.
+	query, closeQuery, err := newReleaseQuery(cCtx.Context, cfg, devnet.DEVNET_CONTEXT)
+	if err != nil {
+		return err
+	}
+	defer closeQuery()
+	var operatorSetIds []uint32
+	for _, opset := range cfg.Context[devnet.DEVNET_CONTEXT].OperatorSets {
+		operatorSetIds = append(operatorSetIds, uint32(opset.OperatorSetID))
+	}
+	onchainVersion, unknown, err := latestPublishedVersion(query, operatorSetIds)
+	switch {
+	case err != nil && !dryRun:
+		return fmt.Errorf("failed to read published releases: %w", err)
+	case err != nil:
+		logger.Warn("Could not read published releases, only checking against the context version: %v", err)
+	default:
+		if len(unknown) > 0 && !dryRun {
+			return fmt.Errorf("the latest releases of operator sets %v are missing from %s, so their versions can't be compared; copy the release history from the checkout that published them", unknown, releaseHistoryPath(devnet.DEVNET_CONTEXT))
+		}
+		if len(unknown) > 0 {
+			logger.Warn("The latest releases of operator sets %v are missing from %s, their versions can't be compared", unknown, releaseHistoryPath(devnet.DEVNET_CONTEXT))
+		}
+		if greater, _ := common.CompareVersions(onchainVersion, lastVersion); greater {
+			lastVersion = onchainVersion
+		}
+	}
+
+	// A version published onchain but not yet recorded in the context was interrupted after some operator sets, the
+	// same publish resumes it
+	var version, previousVersion string
+	if requested := strings.TrimPrefix(cCtx.String("version"), "v"); lastVersion != contextVersion && (requested == lastVersion || requested == "" && cCtx.String("bump") == "") {
+		version, previousVersion = lastVersion, contextVersion
+		logger.Info("Version %s was only published to some operator sets, resuming it", version)
+	} else {
+		if version, err = nextReleaseVersion(lastVersion, cCtx.String("version"), cCtx.String("bump")); err != nil {
+			return err
+		}
+		previousVersion = lastVersion
+	}
+	// The images carry the version they were built for, publishing another one would mislabel them
+	if built := strings.TrimPrefix(artifact.BuildVersion, "v"); built != "" && built != version {
+		return fmt.Errorf("the images were built for version %s but this release would be %s, rebuild with `devkit avs build` and the same --version or --bump, or publish with --version %s", built, version, built)
 	}
 
 	// Validate upgradeByTime is in the future
@@ -235,88 +402,202 @@ func publishReleaseAction(cCtx *cli.Context) error {
 		return fmt.Errorf("upgrade-by-time timestamp %d must be in the future (current time: %d)", upgradeByTime, time.Now().Unix())
 	}
 
-	if artifact.Component == "" {
-		return fmt.Errorf("no component found in context. Please run 'devkit avs build' first")
+	components := artifact.ComponentList()
+	if len(components) == 0 {
+		return fmt.Errorf("no components found in context. Please run 'devkit avs build' first")
 	}
 
 	logger.Info("Publishing AVS release...")
 	logger.Info("AVS address: %s", avs)
-	logger.Info("Version: %s", version)
-	logger.Info("Registry: %s", registry)
+	logger.Info("Version: %s (last published %s)", version, previousVersion)
+	logger.Info("Components: %d", len(components))
 	logger.Info("UpgradeByTime: %s", time.Unix(upgradeByTime, 0).Format(time.RFC3339))
 
 	// Call release.sh script to check if image has changed
 	scriptsDir := filepath.Join(".hourglass", "scripts")
 	releaseScriptPath := filepath.Join(scriptsDir, "release.sh")
 
-	// Get registry from flag or context
+	// Get registry from flag or context, components may release to their own registry
 	finalRegistry := registry
 	if finalRegistry == "" {
-		if artifact.Registry == "" {
-			return fmt.Errorf("no registry found in context")
-		}
 		finalRegistry = artifact.Registry
 		logger.Info("Using registry from context: %s", finalRegistry)
 	} else {
 		logger.Info("Using provided registry: %s", finalRegistry)
 	}
-	component := cfg.Context[devnet.DEVNET_CONTEXT].Artifact.Component
-	// Execute release script with version and registry
-	releaseCmd := exec.CommandContext(cCtx.Context, "bash", releaseScriptPath,
-		"--version", version,
-		"--registry", finalRegistry,
-		"--image", component,
-		"--original-image-id", artifact.ArtifactId)
-	releaseCmd.Stderr = os.Stderr // Show stderr in terminal
-
-	// Capture stdout to get the operator set mapping JSON
-	output, err := releaseCmd.Output()
-	if err != nil {
-		// Script returned non-zero exit code, meaning image has changed
-		logger.Info("Image has changed since last build. Please ensure your build is stable before releasing.")
-		logger.Info("Run 'devkit avs build' again and verify no code changes were made.")
+
+	// Run the release script once per component and merge the operator set mappings they report
+	operatorSetMapping := make(map[string][]OperatorSetRelease)
+	releasedComponents := make([]ReleaseManifestComponent, 0, len(components))
+	for _, component := range components {
+		componentRegistry := component.Registry
+		if componentRegistry == "" {
+			componentRegistry = finalRegistry
+		}
+		if componentRegistry == "" {
+			return fmt.Errorf("no registry found in context for component %s", component.Name)
+		}
+
+		logger.Info("Releasing component %s (%s) to %s", component.Name, component.Image, componentRegistry)
+		// Execute release script with version and registry
+		releaseCmd := exec.CommandContext(cCtx.Context, "bash", releaseScriptPath,
+			"--version", version,
+			"--registry", componentRegistry,
+			"--image", component.Image,
+			"--original-image-id", component.ArtifactId)
+		releaseCmd.Stderr = os.Stderr // Show stderr in terminal
+
+		// Capture stdout to get the operator set mapping JSON
+		output, err := releaseCmd.Output()
+		if err != nil {
+			// Script returned non-zero exit code, meaning image has changed
+			logger.Info("Image of component %s has changed since last build. Please ensure your build is stable before releasing.", component.Name)
+			logger.Info("Run 'devkit avs build' again and verify no code changes were made.")
+			return err
+		}
+
+		// Parse the operator set mapping JSON from script output
+		componentMapping, err := parseOperatorSetMapping(string(output))
+		if err != nil {
+			logger.Warn("Failed to parse operator set mapping in hourglass release script for component %s: %v", component.Name, err)
+			return err
+		}
+		mergeComponentMapping(operatorSetMapping, componentMapping, component.OperatorSets)
+
+		released := ReleaseManifestComponent{
+			Name:         component.Name,
+			Image:        component.Image,
+			Registry:     componentRegistry,
+			Digest:       componentDigest(componentMapping, componentRegistry),
+			OperatorSets: component.OperatorSets,
+		}
+		if len(released.OperatorSets) == 0 {
+			for opset := range componentMapping {
+				id, err := strconv.ParseUint(opset, 10, 32)
+				if err != nil {
+					return fmt.Errorf("invalid operator set ID %q in release script output: %w", opset, err)
+				}
+				released.OperatorSets = append(released.OperatorSets, uint32(id))
+			}
+			sort.Slice(released.OperatorSets, func(i, j int) bool { return released.OperatorSets[i] < released.OperatorSets[j] })
+		}
+		releasedComponents = append(releasedComponents, released)
+	}
+
+	logger.Info("Retrieved operator set mapping with %d operator sets", len(operatorSetMapping))
+
+	_, _, _, _, _, _, releaseManagerAddress := devnet.GetEigenLayerAddresses(cfg)
+	manifest := &ReleaseManifest{
+		Avs:             ethcommon.HexToAddress(avs).Hex(),
+		ReleaseManager:  ethcommon.HexToAddress(releaseManagerAddress).Hex(),
+		ChainID:         uint64(cfg.Context[devnet.DEVNET_CONTEXT].Chains[devnet.L1].ChainID),
+		PreviousVersion: previousVersion,
+		Version:         version,
+		MetadataURI:     cCtx.String("metadata-uri"),
+		Registry:        finalRegistry,
+		UpgradeByTime:   upgradeByTime,
+		DryRun:          dryRun,
+		Components:      releasedComponents,
+	}
+	if err := buildReleaseManifest(manifest, operatorSetMapping); err != nil {
 		return err
 	}
 
-	// update version in context, by incrementing it
-	version, err = incrementVersion(version)
-	if err != nil {
-		return fmt.Errorf("failed to increment version: %w", err)
+	// Make sure every digest exists in its registry and covers the required platforms before anything is published
+	if cCtx.Bool("skip-verify") {
+		logger.Warn("Skipping artifact verification, publishing the digests reported by the release script")
+	} else {
+		logger.Info("Verifying artifacts in their registries...")
+		if err := verifyReleaseArtifacts(cCtx.Context, newOCIRegistryClient(), manifest); err != nil {
+			// A dry run reports what a real publish would refuse, along with the rest of the plan
+			if !dryRun {
+				return err
+			}
+			logger.Warn("%v", err)
+		}
 	}
+	if err := writeReleaseManifest(cCtx.String("manifest"), manifest); err != nil {
+		return err
+	}
+	logger.Info("Wrote release manifest to %s", cCtx.String("manifest"))
 
-	// Update version in context
-	if err := updateContextWithVersion(version); err != nil {
-		return fmt.Errorf("failed to update context with version: %w", err)
+	if dryRun {
+		writeReleasePlan(cCtx.App.Writer, manifest)
+		logger.Info("Dry run, nothing was published and the context was not updated")
+		return nil
 	}
 
-	// Parse the operator set mapping JSON from script output
-	logger.Info("Processing operator set mapping from script output...")
-	operatorSetMapping, err := parseOperatorSetMapping(string(output))
-	if err != nil {
-		logger.Warn("Failed to parse operator set mapping in hourglass release script: %v", err)
-		return err
+	// Publish releases for each operator set, the context is only updated once every publish succeeded
+	var published []string
+	history := ReleaseHistoryEntry{Version: version, MetadataURI: manifest.MetadataURI}
+	for _, opset := range manifest.OperatorSets {
+		artifacts := make([]releasemanager.IReleaseManagerTypesArtifact, 0, len(opset.Artifacts))
+		for _, a := range opset.Artifacts {
+			digest, _ := hexStringToBytes32(a.Digest) // validated by buildReleaseManifest
+			artifacts = append(artifacts, releasemanager.IReleaseManagerTypesArtifact{Digest: digest, RegistryUrl: a.Registry})
+		}
+
+		// The new release takes the next index in the operator set. A set whose latest release already carries these
+		// artifacts got them from an interrupted run of this release and is not published twice.
+		var index uint64
+		if recorder == nil {
+			if index, err = query.total(opset.OperatorSetID); err != nil {
+				return err
+			}
+			done, err := query.latestCarries(opset.OperatorSetID, index, artifacts)
+			if err != nil {
+				return err
+			}
+			if done {
+				logger.Info("Operator set %d already has these artifacts in release %d, skipping it", opset.OperatorSetID, index-1)
+				history.OperatorSets = append(history.OperatorSets, ReleaseHistoryOperatorSet{ID: opset.OperatorSetID, Index: index - 1})
+				continue
+			}
+		}
+
+		logger.Info("Publishing release for operator set %d with %d artifacts...", opset.OperatorSetID, len(artifacts))
+		if err := publishReleaseToReleaseManagerAction(cCtx.Context, logger, avs, opset.OperatorSetID, upgradeByTime, artifacts); err != nil {
+			if strings.Contains(err.Error(), "connection refused") {
+				logger.Info("Check if devnet is running and try again")
+			}
+			// The sets published so far keep their version in the history, the context only moves to it once every
+			// set has the release. Running the same publish again resumes it.
+			if len(published) > 0 && recorder == nil {
+				logger.Warn("Operator sets %s were already published, the context still records version %s. Run the same publish again to release the remaining operator sets", strings.Join(published, ", "), contextVersion)
+				history.Time = time.Now().UTC()
+				if err := appendReleaseHistory(releaseHistoryPath(devnet.DEVNET_CONTEXT), history); err != nil {
+					logger.Error("Failed to record the published operator sets: %v", err)
+				}
+			}
+			return fmt.Errorf("failed to publish release for operator set %d: %w", opset.OperatorSetID, err)
+		}
+		published = append(published, strconv.FormatUint(uint64(opset.OperatorSetID), 10))
+		history.OperatorSets = append(history.OperatorSets, ReleaseHistoryOperatorSet{ID: opset.OperatorSetID, Index: index})
+		logger.Info("Successfully published release for operator set %d", opset.OperatorSetID)
 	}
 
-	logger.Info("Retrieved operator set mapping with %d operator sets", len(operatorSetMapping))
+	// Recorded calls are only published once the multisig executes them, so neither the context nor the history
+	// may record the release yet
+	if recorder != nil {
+		logger.Info("The context and release history were not updated, the release is only published once the calls are executed")
+		return writeRecordedCalls(cCtx, logger, recorder, fmt.Sprintf("Publish release %s", version))
+	}
 
-	// Publish releases for each operator set
-	if err := processOperatorSetsAndPublishReleaseOnChain(cCtx, logger, operatorSetMapping, avs, upgradeByTime, finalRegistry); err != nil {
+	digests := make(map[string]string, len(manifest.Components))
+	for _, component := range manifest.Components {
+		digests[component.Name] = component.Digest
+	}
+	if err := updateContextWithRelease(version, digests); err != nil {
+		return fmt.Errorf("failed to update context with release: %w", err)
+	}
+	history.Time = time.Now().UTC()
+	if err := appendReleaseHistory(releaseHistoryPath(devnet.DEVNET_CONTEXT), history); err != nil {
 		return err
 	}
 
 	return nil
 }
 
-func incrementVersion(version string) (string, error) {
-	// version is a int
-	versionInt, err := strconv.Atoi(version)
-	if err != nil {
-		return "", fmt.Errorf("failed to convert version to int: %w", err)
-	}
-	versionInt++
-	return strconv.Itoa(versionInt), nil
-}
-
 func publishReleaseToReleaseManagerAction(ctx context.Context, logger iface.Logger, avs string, operatorSetId uint32, upgradeByTime int64, artifacts []releasemanager.IReleaseManagerTypesArtifact) error {
 
 	cfg, err := common.LoadConfigWithContextConfig(devnet.DEVNET_CONTEXT)
@@ -343,7 +624,7 @@ func publishReleaseToReleaseManagerAction(ctx context.Context, logger iface.Logg
 	upgradeByTime = int64(upgradeByTime)
 
 	avsPrivateKey := envCtx.Avs.AVSPrivateKey
-	if avsPrivateKey == "" {
+	if avsPrivateKey == "" && envCtx.Avs.Signer == nil && common.CallRecorderFromContext(ctx) == nil {
 		return fmt.Errorf("AVS private key not found in context")
 	}
 	// Trim 0x
@@ -351,7 +632,9 @@ func publishReleaseToReleaseManagerAction(ctx context.Context, logger iface.Logg
 	_, _, _, _, _, _, releaseManagerAddress := devnet.GetEigenLayerAddresses(cfg)
 
 	contractCaller, err := common.NewContractCaller(
+		ctx,
 		avsPrivateKey,
+		envCtx.Avs.Signer,
 		big.NewInt(int64(l1Cfg.ChainID)),
 		client,
 		ethcommon.HexToAddress(""),
@@ -360,11 +643,14 @@ func publishReleaseToReleaseManagerAction(ctx context.Context, logger iface.Logg
 		ethcommon.HexToAddress(""),
 		ethcommon.HexToAddress(""),
 		ethcommon.HexToAddress(releaseManagerAddress),
+		l1Cfg.Transactions,
 		logger,
 	)
 	if err != nil {
 		return fmt.Errorf("failed to create contract caller: %w", err)
 	}
+	defer contractCaller.Close()
+	contractCaller.SetCallRecorder(common.CallRecorderFromContext(ctx))
 
 	// Use the artifacts array passed in
 	err = contractCaller.PublishRelease(ctx, ethcommon.HexToAddress(avs), artifacts, operatorSetId, upgradeByTime)
@@ -0,0 +1,347 @@
+package commands
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Platforms every released image must provide
+var requiredReleasePlatforms = []string{"linux/amd64", "linux/arm64"}
+
+// Manifest media types accepted when resolving a digest
+const (
+	ociImageIndexMediaType       = "application/vnd.oci.image.index.v1+json"
+	ociImageManifestMediaType    = "application/vnd.oci.image.manifest.v1+json"
+	dockerManifestListMediaType  = "application/vnd.docker.distribution.manifest.list.v2+json"
+	dockerImageManifestMediaType = "application/vnd.docker.distribution.manifest.v2+json"
+)
+
+// ociManifest holds the fields of an image index or image manifest that verification needs
+type ociManifest struct {
+	MediaType string `json:"mediaType"`
+	Manifests []struct {
+		Digest   string `json:"digest"`
+		Platform *struct {
+			OS           string `json:"os"`
+			Architecture string `json:"architecture"`
+			Variant      string `json:"variant,omitempty"`
+		} `json:"platform,omitempty"`
+	} `json:"manifests"`
+	Config *struct {
+		Digest string `json:"digest"`
+	} `json:"config"`
+}
+
+// ociRegistryClient resolves manifests through the OCI distribution API, authenticating with the credentials of
+// `docker login` when the registry asks for them and anonymously otherwise
+type ociRegistryClient struct {
+	http *http.Client
+	// Authorization headers by repository, registries hand out one token per repository
+	auth map[string]string
+	// Looks up the credentials of a registry host, empty when there are none
+	credentials func(host string) (username, password string, err error)
+}
+
+func newOCIRegistryClient() *ociRegistryClient {
+	return &ociRegistryClient{
+		http:        &http.Client{Timeout: 30 * time.Second},
+		auth:        make(map[string]string),
+		credentials: dockerCredentials,
+	}
+}
+
+// splitImageReference splits a registry url like ghcr.io/acme/avs into host and repository, defaulting to Docker Hub
+func splitImageReference(ref string) (host, repository string, err error) {
+	ref = strings.TrimPrefix(strings.TrimPrefix(ref, "https://"), "http://")
+	ref = strings.TrimSuffix(ref, "/")
+	if ref == "" {
+		return "", "", fmt.Errorf("empty registry")
+	}
+
+	first, rest, found := strings.Cut(ref, "/")
+	if found && (strings.ContainsAny(first, ".:") || first == "localhost") {
+		host, repository = first, rest
+	} else {
+		host, repository = "registry-1.docker.io", ref
+		if !strings.Contains(repository, "/") {
+			repository = "library/" + repository
+		}
+	}
+	// A tag in the registry url does not matter, the digest selects the manifest
+	if i := strings.LastIndex(repository, ":"); i > strings.LastIndex(repository, "/") {
+		repository = repository[:i]
+	}
+	if repository == "" {
+		return "", "", fmt.Errorf("registry %q has no repository", ref)
+	}
+	return host, repository, nil
+}
+
+// registryScheme talks plain http to registries on the local machine, such as a registry:2 container
+func registryScheme(host string) string {
+	hostname := host
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		hostname = h
+	}
+	if hostname == "localhost" || hostname == "host.docker.internal" {
+		return "http"
+	}
+	if ip := net.ParseIP(hostname); ip != nil && ip.IsLoopback() {
+		return "http"
+	}
+	return "https"
+}
+
+// fetchManifest returns the manifest stored under digest and checks that its content hashes to that digest
+func (c *ociRegistryClient) fetchManifest(ctx context.Context, registry, digest string) (*ociManifest, error) {
+	body, err := c.get(ctx, registry, "manifests/"+digest, strings.Join([]string{
+		ociImageIndexMediaType, dockerManifestListMediaType, ociImageManifestMediaType, dockerImageManifestMediaType,
+	}, ", "))
+	if err != nil {
+		return nil, err
+	}
+
+	sum := sha256.Sum256(body)
+	if actual := "sha256:" + hex.EncodeToString(sum[:]); actual != digest {
+		return nil, fmt.Errorf("registry returned manifest %s for %s", actual, digest)
+	}
+
+	var manifest ociManifest
+	if err := json.Unmarshal(body, &manifest); err != nil {
+		return nil, fmt.Errorf("invalid manifest %s: %w", digest, err)
+	}
+	return &manifest, nil
+}
+
+// platforms lists the os/architecture pairs an image provides. An image index names them per manifest,
+// a single image manifest only describes its platform in the image config.
+func (c *ociRegistryClient) platforms(ctx context.Context, registry string, manifest *ociManifest) ([]string, error) {
+	var platforms []string
+	if len(manifest.Manifests) > 0 {
+		for _, m := range manifest.Manifests {
+			// Attestation manifests are listed as unknown/unknown
+			if m.Platform == nil || m.Platform.OS == "unknown" {
+				continue
+			}
+			platform := m.Platform.OS + "/" + m.Platform.Architecture
+			if m.Platform.Variant != "" {
+				platform += "/" + m.Platform.Variant
+			}
+			platforms = append(platforms, platform)
+		}
+		sort.Strings(platforms)
+		return platforms, nil
+	}
+
+	if manifest.Config == nil {
+		return nil, fmt.Errorf("manifest has neither platform manifests nor an image config")
+	}
+	body, err := c.get(ctx, registry, "blobs/"+manifest.Config.Digest, "")
+	if err != nil {
+		return nil, err
+	}
+	var config struct {
+		OS           string `json:"os"`
+		Architecture string `json:"architecture"`
+	}
+	if err := json.Unmarshal(body, &config); err != nil {
+		return nil, fmt.Errorf("invalid image config %s: %w", manifest.Config.Digest, err)
+	}
+	return []string{config.OS + "/" + config.Architecture}, nil
+}
+
+// get reads path below /v2/<repository>/, authenticating when the registry requires it
+func (c *ociRegistryClient) get(ctx context.Context, registry, path, accept string) ([]byte, error) {
+	host, repository, err := splitImageReference(registry)
+	if err != nil {
+		return nil, err
+	}
+	endpoint := fmt.Sprintf("%s://%s/v2/%s/%s", registryScheme(host), host, repository, path)
+
+	resp, err := c.do(ctx, endpoint, accept, c.auth[host+"/"+repository])
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusUnauthorized {
+		challenge := resp.Header.Get("WWW-Authenticate")
+		resp.Body.Close()
+		authorization, err := c.authorize(ctx, host, repository, challenge)
+		if err != nil {
+			return nil, fmt.Errorf("failed to authenticate with %s: %w", host, err)
+		}
+		c.auth[host+"/"+repository] = authorization
+		if resp, err = c.do(ctx, endpoint, accept, authorization); err != nil {
+			return nil, err
+		}
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 4<<20))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", endpoint, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("GET %s returned %s", endpoint, resp.Status)
+	}
+	return body, nil
+}
+
+func (c *ociRegistryClient) do(ctx context.Context, endpoint, accept, authorization string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+	if authorization != "" {
+		req.Header.Set("Authorization", authorization)
+	}
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reach registry: %w", err)
+	}
+	return resp, nil
+}
+
+var authParamRegex = regexp.MustCompile(`(\w+)="([^"]*)"`)
+
+// authorize answers a WWW-Authenticate challenge with the Authorization header to retry with. Basic challenges need
+// docker credentials, Bearer challenges get a pull token that is anonymous when there are none.
+func (c *ociRegistryClient) authorize(ctx context.Context, host, repository, challenge string) (string, error) {
+	username, password, err := c.credentials(host)
+	if err != nil {
+		return "", fmt.Errorf("failed to read docker credentials: %w", err)
+	}
+	var basic string
+	if username != "" || password != "" {
+		basic = "Basic " + base64.StdEncoding.EncodeToString([]byte(username+":"+password))
+	}
+
+	switch scheme, _, _ := strings.Cut(challenge, " "); strings.ToLower(scheme) {
+	case "basic":
+		if basic == "" {
+			return "", fmt.Errorf("registry requires credentials, run `docker login %s`", host)
+		}
+		return basic, nil
+	case "bearer":
+		token, err := c.token(ctx, challenge, repository, basic)
+		if err != nil {
+			return "", err
+		}
+		return "Bearer " + token, nil
+	default:
+		return "", fmt.Errorf("unsupported authentication challenge %q", challenge)
+	}
+}
+
+// token requests a pull token as described by a Bearer WWW-Authenticate challenge, with the given credentials if any
+func (c *ociRegistryClient) token(ctx context.Context, challenge, repository, authorization string) (string, error) {
+	params := make(map[string]string)
+	for _, match := range authParamRegex.FindAllStringSubmatch(challenge, -1) {
+		params[match[1]] = match[2]
+	}
+	if params["realm"] == "" {
+		return "", fmt.Errorf("authentication challenge has no realm")
+	}
+
+	query := url.Values{}
+	if params["service"] != "" {
+		query.Set("service", params["service"])
+	}
+	scope := params["scope"]
+	if scope == "" {
+		scope = "repository:" + repository + ":pull"
+	}
+	query.Set("scope", scope)
+
+	resp, err := c.do(ctx, params["realm"]+"?"+query.Encode(), "", authorization)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusUnauthorized && authorization == "" {
+		return "", fmt.Errorf("token request returned %s, private repositories need `docker login`", resp.Status)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("token request returned %s", resp.Status)
+	}
+	var body struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return "", fmt.Errorf("invalid token response: %w", err)
+	}
+	if body.Token != "" {
+		return body.Token, nil
+	}
+	return body.AccessToken, nil
+}
+
+// verifyReleaseArtifacts resolves every artifact of the manifest in its registry, records the platforms
+// it provides and fails when any digest can't be resolved or misses a required platform
+func verifyReleaseArtifacts(ctx context.Context, client *ociRegistryClient, manifest *ReleaseManifest) error {
+	var problems []string
+	for i := range manifest.OperatorSets {
+		opset := &manifest.OperatorSets[i]
+		for j := range opset.Artifacts {
+			artifact := &opset.Artifacts[j]
+			digest := artifact.Digest
+			if !strings.HasPrefix(digest, "sha256:") {
+				digest = "sha256:" + digest
+			}
+
+			image, err := client.fetchManifest(ctx, artifact.Registry, digest)
+			if err != nil {
+				problems = append(problems, fmt.Sprintf("operator set %d: %s@%s: %v", opset.OperatorSetID, artifact.Registry, digest, err))
+				continue
+			}
+			platforms, err := client.platforms(ctx, artifact.Registry, image)
+			if err != nil {
+				problems = append(problems, fmt.Sprintf("operator set %d: %s@%s: %v", opset.OperatorSetID, artifact.Registry, digest, err))
+				continue
+			}
+			artifact.Platforms = platforms
+
+			if missing := missingPlatforms(platforms, requiredReleasePlatforms); len(missing) > 0 {
+				problems = append(problems, fmt.Sprintf("operator set %d: %s@%s is missing platforms %s", opset.OperatorSetID, artifact.Registry, digest, strings.Join(missing, ", ")))
+			}
+		}
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("artifact verification failed (use --skip-verify to publish anyway):\n  %s", strings.Join(problems, "\n  "))
+	}
+	manifest.Verified = true
+	return nil
+}
+
+// missingPlatforms returns the required platforms not provided, a variant (linux/arm64/v8) covers its platform
+func missingPlatforms(provided, required []string) []string {
+	var missing []string
+	for _, want := range required {
+		found := false
+		for _, have := range provided {
+			if have == want || strings.HasPrefix(have, want+"/") {
+				found = true
+				break
+			}
+		}
+		if !found {
+			missing = append(missing, want)
+		}
+	}
+	return missing
+}
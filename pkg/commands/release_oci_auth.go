@@ -0,0 +1,130 @@
+package commands
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// dockerConfig holds the fields of ~/.docker/config.json that hold registry credentials
+type dockerConfig struct {
+	Auths map[string]struct {
+		Auth     string `json:"auth"`
+		Username string `json:"username"`
+		Password string `json:"password"`
+	} `json:"auths"`
+	CredsStore  string            `json:"credsStore"`
+	CredHelpers map[string]string `json:"credHelpers"`
+}
+
+// dockerConfigPath returns the docker config file, honouring DOCKER_CONFIG like the docker CLI
+func dockerConfigPath() (string, error) {
+	if dir := os.Getenv("DOCKER_CONFIG"); dir != "" {
+		return filepath.Join(dir, "config.json"), nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".docker", "config.json"), nil
+}
+
+// dockerCredentialKeys lists the keys the docker CLI stores credentials of host under
+func dockerCredentialKeys(host string) []string {
+	if host == "registry-1.docker.io" {
+		return []string{"https://index.docker.io/v1/", "index.docker.io", "docker.io", host}
+	}
+	return []string{host, "https://" + host, "http://" + host}
+}
+
+// dockerCredentials returns the credentials `docker login` stored for host, from the registry's credential helper,
+// the default credential store or the auths of the docker config. No credentials and no docker config are not errors,
+// the registry is then accessed anonymously.
+func dockerCredentials(host string) (username, password string, err error) {
+	path, err := dockerConfigPath()
+	if err != nil {
+		return "", "", nil
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return "", "", nil
+	}
+	if err != nil {
+		return "", "", fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	var config dockerConfig
+	if err := json.Unmarshal(data, &config); err != nil {
+		return "", "", fmt.Errorf("invalid docker config %s: %w", path, err)
+	}
+
+	for _, key := range dockerCredentialKeys(host) {
+		helper := config.CredHelpers[key]
+		if helper == "" {
+			helper = config.CredsStore
+		}
+		if helper == "" {
+			continue
+		}
+		username, password, err := credentialHelperGet(helper, key)
+		if err != nil {
+			return "", "", err
+		}
+		if username != "" || password != "" {
+			return username, password, nil
+		}
+	}
+
+	for _, key := range dockerCredentialKeys(host) {
+		auth, ok := config.Auths[key]
+		if !ok {
+			continue
+		}
+		if auth.Auth == "" {
+			if auth.Username != "" {
+				return auth.Username, auth.Password, nil
+			}
+			continue
+		}
+		decoded, err := base64.StdEncoding.DecodeString(auth.Auth)
+		if err != nil {
+			return "", "", fmt.Errorf("invalid auth for %s in %s: %w", key, path, err)
+		}
+		username, password, found := strings.Cut(string(decoded), ":")
+		if !found {
+			return "", "", fmt.Errorf("invalid auth for %s in %s, expected user:password", key, path)
+		}
+		return username, password, nil
+	}
+	return "", "", nil
+}
+
+// credentialHelperGet asks docker-credential-<helper> for the credentials of serverURL, returning none when the
+// helper has no entry for it
+func credentialHelperGet(helper, serverURL string) (username, password string, err error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command("docker-credential-"+helper, "get")
+	cmd.Stdin = strings.NewReader(serverURL)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		// Helpers print this on stdout when they have nothing stored for the server
+		if strings.Contains(stdout.String()+stderr.String(), "credentials not found") {
+			return "", "", nil
+		}
+		return "", "", fmt.Errorf("docker-credential-%s failed: %w %s", helper, err, strings.TrimSpace(stderr.String()))
+	}
+	var creds struct {
+		Username string `json:"Username"`
+		Secret   string `json:"Secret"`
+	}
+	if err := json.Unmarshal(stdout.Bytes(), &creds); err != nil {
+		return "", "", fmt.Errorf("invalid output from docker-credential-%s: %w", helper, err)
+	}
+	return creds.Username, creds.Secret, nil
+}
@@ -0,0 +1,183 @@
+package commands
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// fakeOCIRegistry serves manifests and blobs by digest for one repository, optionally behind a bearer token that
+// may itself require basic credentials
+type fakeOCIRegistry struct {
+	server *httptest.Server
+	// Content by digest
+	objects  map[string]string
+	token    string
+	username string
+	password string
+}
+
+func newFakeOCIRegistry(t *testing.T) *fakeOCIRegistry {
+	r := &fakeOCIRegistry{objects: make(map[string]string)}
+	r.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Path == "/token" {
+			if username, password, _ := req.BasicAuth(); username != r.username || password != r.password {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			fmt.Fprintf(w, `{"token": %q}`, r.token)
+			return
+		}
+		if r.token != "" && req.Header.Get("Authorization") != "Bearer "+r.token {
+			w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm="%s/token",service="fake",scope="repository:acme/avs:pull"`, r.server.URL))
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		digest := req.URL.Path[strings.LastIndex(req.URL.Path, "/")+1:]
+		body, ok := r.objects[digest]
+		if !strings.HasPrefix(req.URL.Path, "/v2/acme/avs/") || !ok {
+			http.NotFound(w, req)
+			return
+		}
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(r.server.Close)
+	return r
+}
+
+// add stores content and returns its digest
+func (r *fakeOCIRegistry) add(content string) string {
+	sum := sha256.Sum256([]byte(content))
+	digest := "sha256:" + hex.EncodeToString(sum[:])
+	r.objects[digest] = content
+	return digest
+}
+
+// repository is the registry url of the served repository
+func (r *fakeOCIRegistry) repository() string {
+	return strings.TrimPrefix(r.server.URL, "http://") + "/acme/avs"
+}
+
+// addIndex stores an image index listing platforms and returns its digest
+func (r *fakeOCIRegistry) addIndex(platforms ...string) string {
+	manifests := make([]string, 0, len(platforms))
+	for _, platform := range platforms {
+		os, arch, _ := strings.Cut(platform, "/")
+		manifests = append(manifests, fmt.Sprintf(`{"mediaType":%q,"digest":"sha256:%064d","platform":{"os":%q,"architecture":%q}}`, ociImageManifestMediaType, len(manifests), os, arch))
+	}
+	return r.add(fmt.Sprintf(`{"schemaVersion":2,"mediaType":%q,"manifests":[%s]}`, ociImageIndexMediaType, strings.Join(manifests, ",")))
+}
+
+func TestSplitImageReference(t *testing.T) {
+	tests := []struct {
+		ref, host, repository string
+	}{
+		{"ghcr.io/acme/avs", "ghcr.io", "acme/avs"},
+		{"localhost:5001/avs:1.4.0", "localhost:5001", "avs"},
+		{"http://127.0.0.1:5000/acme/avs/", "127.0.0.1:5000", "acme/avs"},
+		{"acme/avs", "registry-1.docker.io", "acme/avs"},
+		{"nginx", "registry-1.docker.io", "library/nginx"},
+	}
+	for _, tt := range tests {
+		host, repository, err := splitImageReference(tt.ref)
+		require.NoError(t, err, tt.ref)
+		require.Equal(t, tt.host, host, tt.ref)
+		require.Equal(t, tt.repository, repository, tt.ref)
+	}
+
+	_, _, err := splitImageReference("")
+	require.Error(t, err)
+	require.Equal(t, "http", registryScheme("localhost:5001"))
+	require.Equal(t, "http", registryScheme("127.0.0.1:5000"))
+	require.Equal(t, "https", registryScheme("ghcr.io"))
+}
+
+func TestVerifyReleaseArtifacts(t *testing.T) {
+	registry := newFakeOCIRegistry(t)
+	multiArch := registry.addIndex("linux/amd64", "linux/arm64", "unknown/unknown")
+	config := registry.add(`{"os":"linux","architecture":"amd64"}`)
+	singleArch := registry.add(fmt.Sprintf(`{"schemaVersion":2,"mediaType":%q,"config":{"digest":%q}}`, ociImageManifestMediaType, config))
+	unknown := "sha256:" + strings.Repeat("0", 64)
+
+	manifest := &ReleaseManifest{OperatorSets: []ReleaseManifestOperatorSet{
+		{OperatorSetID: 0, Artifacts: []OperatorSetRelease{{Digest: multiArch, Registry: registry.repository()}}},
+	}}
+	require.NoError(t, verifyReleaseArtifacts(context.Background(), newOCIRegistryClient(), manifest))
+	require.True(t, manifest.Verified)
+	require.Equal(t, []string{"linux/amd64", "linux/arm64"}, manifest.OperatorSets[0].Artifacts[0].Platforms)
+
+	manifest = &ReleaseManifest{OperatorSets: []ReleaseManifestOperatorSet{
+		{OperatorSetID: 0, Artifacts: []OperatorSetRelease{{Digest: singleArch, Registry: registry.repository()}}},
+		{OperatorSetID: 1, Artifacts: []OperatorSetRelease{{Digest: unknown, Registry: registry.repository()}}},
+	}}
+	err := verifyReleaseArtifacts(context.Background(), newOCIRegistryClient(), manifest)
+	require.ErrorContains(t, err, "is missing platforms linux/arm64")
+	require.ErrorContains(t, err, "operator set 1")
+	require.ErrorContains(t, err, "404 Not Found")
+	require.ErrorContains(t, err, "--skip-verify")
+	require.False(t, manifest.Verified)
+	require.Equal(t, []string{"linux/amd64"}, manifest.OperatorSets[0].Artifacts[0].Platforms)
+}
+
+func TestOCIRegistryClientAuthenticates(t *testing.T) {
+	registry := newFakeOCIRegistry(t)
+	registry.token = "pull-token"
+	digest := registry.addIndex("linux/amd64", "linux/arm64")
+	t.Setenv("DOCKER_CONFIG", t.TempDir())
+
+	client := newOCIRegistryClient()
+	manifest, err := client.fetchManifest(context.Background(), registry.repository(), digest)
+	require.NoError(t, err)
+	require.Len(t, manifest.Manifests, 2)
+	require.Equal(t, "Bearer pull-token", client.auth[registry.repository()])
+}
+
+func TestOCIRegistryClientUsesDockerCredentials(t *testing.T) {
+	registry := newFakeOCIRegistry(t)
+	registry.token = "private-token"
+	registry.username, registry.password = "acme", "s3cret"
+	digest := registry.addIndex("linux/amd64", "linux/arm64")
+	host, _, err := splitImageReference(registry.repository())
+	require.NoError(t, err)
+
+	// Without credentials the token is refused
+	t.Setenv("DOCKER_CONFIG", t.TempDir())
+	_, err = newOCIRegistryClient().fetchManifest(context.Background(), registry.repository(), digest)
+	require.ErrorContains(t, err, "docker login")
+
+	// Credentials stored by docker login in the auths of the config
+	auth := base64.StdEncoding.EncodeToString([]byte("acme:s3cret"))
+	config := fmt.Sprintf(`{"auths": {%q: {"auth": %q}}}`, host, auth)
+	require.NoError(t, os.WriteFile(filepath.Join(os.Getenv("DOCKER_CONFIG"), "config.json"), []byte(config), 0600))
+	_, err = newOCIRegistryClient().fetchManifest(context.Background(), registry.repository(), digest)
+	require.NoError(t, err)
+
+	// Credentials from a credential helper take precedence
+	bin := t.TempDir()
+	helper := "#!/bin/sh\nread server\necho '{\"ServerURL\": \"'$server'\", \"Username\": \"acme\", \"Secret\": \"s3cret\"}'\n"
+	require.NoError(t, os.WriteFile(filepath.Join(bin, "docker-credential-fake"), []byte(helper), 0755))
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+	config = fmt.Sprintf(`{"auths": {%q: {"auth": %q}}, "credHelpers": {%q: "fake"}}`, host, base64.StdEncoding.EncodeToString([]byte("acme:wrong")), host)
+	require.NoError(t, os.WriteFile(filepath.Join(os.Getenv("DOCKER_CONFIG"), "config.json"), []byte(config), 0600))
+	username, password, err := dockerCredentials(host)
+	require.NoError(t, err)
+	require.Equal(t, "acme", username)
+	require.Equal(t, "s3cret", password)
+	_, err = newOCIRegistryClient().fetchManifest(context.Background(), registry.repository(), digest)
+	require.NoError(t, err)
+}
+
+func TestMissingPlatforms(t *testing.T) {
+	require.Empty(t, missingPlatforms([]string{"linux/amd64", "linux/arm64/v8"}, requiredReleasePlatforms))
+	require.Equal(t, []string{"linux/arm64"}, missingPlatforms([]string{"linux/amd64"}, requiredReleasePlatforms))
+}
@@ -0,0 +1,342 @@
+package commands
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/Layr-Labs/devkit-cli/pkg/common"
+	"github.com/Layr-Labs/devkit-cli/pkg/common/contracts"
+	"github.com/Layr-Labs/devkit-cli/pkg/common/devnet"
+	releasemanager "github.com/Layr-Labs/eigenlayer-contracts/pkg/bindings/ReleaseManager"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/urfave/cli/v2"
+)
+
+// releaseReader is the part of the ReleaseManager the query commands read from
+type releaseReader interface {
+	GetTotalReleases(opts *bind.CallOpts, operatorSet releasemanager.OperatorSet) (*big.Int, error)
+	GetRelease(opts *bind.CallOpts, operatorSet releasemanager.OperatorSet, releaseId *big.Int) (releasemanager.IReleaseManagerTypesRelease, error)
+}
+
+// PublishedRelease is a release read back from the ReleaseManager
+type PublishedRelease struct {
+	OperatorSetID uint32 `json:"operatorSetId"`
+	// Version is taken from the release history, it is empty for releases that were not published from this project
+	Version       string                     `json:"version,omitempty"`
+	Index         uint64                     `json:"index"`
+	UpgradeByTime uint32                     `json:"upgradeByTime"`
+	Artifacts     []PublishedReleaseArtifact `json:"artifacts"`
+}
+
+// PublishedReleaseArtifact is a single artifact of a published release
+type PublishedReleaseArtifact struct {
+	Digest   string `json:"digest"`
+	Registry string `json:"registry"`
+}
+
+var releaseQueryFlags = append([]cli.Flag{
+	&cli.StringFlag{
+		Name:  "context",
+		Usage: "Context to read the AVS and ReleaseManager from (defaults to the project's context)",
+	},
+	&cli.Uint64Flag{
+		Name:  "operator-set",
+		Usage: "Only show releases of this operator set (defaults to every operator set in the context)",
+	},
+	&cli.BoolFlag{
+		Name:  "json",
+		Usage: "Output the releases as JSON",
+	},
+}, common.GlobalFlags...)
+
+// releaseQueryCommands read published releases back from the ReleaseManager
+var releaseQueryCommands = []*cli.Command{
+	{
+		Name:  "list",
+		Usage: "List every release published for the AVS",
+		Flags: releaseQueryFlags,
+		Action: func(cCtx *cli.Context) error {
+			return releaseQueryAction(cCtx, (*releaseQuery).list)
+		},
+	},
+	{
+		Name:      "show",
+		Usage:     "Show a single published release",
+		ArgsUsage: "<version>",
+		Flags:     releaseQueryFlags,
+		Action: func(cCtx *cli.Context) error {
+			if cCtx.NArg() != 1 {
+				return fmt.Errorf("usage: devkit avs release show <version>")
+			}
+			version := strings.TrimPrefix(cCtx.Args().First(), "v")
+			if !common.IsSemver(version) {
+				return fmt.Errorf("invalid version %q, expected semver such as 1.4.0", cCtx.Args().First())
+			}
+			return releaseQueryAction(cCtx, func(q *releaseQuery, operatorSetIds []uint32) ([]PublishedRelease, error) {
+				return q.show(operatorSetIds, version)
+			})
+		},
+	},
+	{
+		Name:  "latest",
+		Usage: "Show the latest release of each operator set",
+		Flags: releaseQueryFlags,
+		Action: func(cCtx *cli.Context) error {
+			return releaseQueryAction(cCtx, (*releaseQuery).latest)
+		},
+	},
+}
+
+// releaseQueryAction connects to the ReleaseManager of the context and prints the releases returned by query
+func releaseQueryAction(cCtx *cli.Context, query func(*releaseQuery, []uint32) ([]PublishedRelease, error)) error {
+	contextName, err := common.SelectedContext(cCtx)
+	if err != nil {
+		return err
+	}
+	cfg, err := common.LoadConfigWithContextConfig(contextName)
+	if err != nil {
+		return fmt.Errorf("failed to load context config: %w", err)
+	}
+	envCtx, ok := cfg.Context[contextName]
+	if !ok {
+		return fmt.Errorf("context '%s' not found in configuration", contextName)
+	}
+	if envCtx.Avs.Address == "" {
+		return fmt.Errorf("AVS address empty in context")
+	}
+
+	var operatorSetIds []uint32
+	if cCtx.IsSet("operator-set") {
+		operatorSetIds = []uint32{uint32(cCtx.Uint64("operator-set"))}
+	} else {
+		for _, opset := range envCtx.OperatorSets {
+			operatorSetIds = append(operatorSetIds, uint32(opset.OperatorSetID))
+		}
+	}
+	if len(operatorSetIds) == 0 {
+		return fmt.Errorf("no operator sets in context '%s', pass --operator-set", contextName)
+	}
+
+	q, closeQuery, err := newReleaseQuery(cCtx.Context, cfg, contextName)
+	if err != nil {
+		return err
+	}
+	defer closeQuery()
+
+	releases, err := query(q, operatorSetIds)
+	if err != nil {
+		return err
+	}
+
+	if cCtx.Bool("json") {
+		enc := json.NewEncoder(cCtx.App.Writer)
+		enc.SetIndent("", "  ")
+		return enc.Encode(releases)
+	}
+	return writeReleases(cCtx.App.Writer, releases)
+}
+
+// releaseQuery reads the releases an AVS published to the ReleaseManager
+type releaseQuery struct {
+	reader releaseReader
+	opts   *bind.CallOpts
+	avs    ethcommon.Address
+	// Versions from the release history by operator set and release index
+	versions map[uint32]map[uint64]string
+}
+
+// newReleaseQuery connects to the ReleaseManager of a context, the returned func closes the connection
+func newReleaseQuery(ctx context.Context, cfg *common.ConfigWithContextConfig, contextName string) (*releaseQuery, func(), error) {
+	envCtx := cfg.Context[contextName]
+	l1Cfg, ok := envCtx.Chains[devnet.L1]
+	if !ok {
+		return nil, nil, fmt.Errorf("failed to get l1 chain config for context '%s'", contextName)
+	}
+
+	history, err := readReleaseHistory(releaseHistoryPath(contextName))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Devnet falls back to the address book of the forked network, other contexts must carry the address
+	var releaseManagerAddress string
+	if contextName == devnet.DEVNET_CONTEXT {
+		_, _, _, _, _, _, releaseManagerAddress = devnet.GetEigenLayerAddresses(cfg)
+	} else if envCtx.EigenLayer != nil {
+		releaseManagerAddress = envCtx.EigenLayer.L1.ReleaseManager
+	}
+	if releaseManagerAddress == "" {
+		return nil, nil, fmt.Errorf("eigenlayer.l1.release_manager is not set in context '%s'", contextName)
+	}
+
+	client, err := ethclient.Dial(l1Cfg.RPCURL)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to connect to L1 RPC: %w", err)
+	}
+
+	address := ethcommon.HexToAddress(releaseManagerAddress)
+	builder, err := contracts.NewRegistryBuilder(client).AddReleaseManager(address)
+	if err != nil {
+		client.Close()
+		return nil, nil, fmt.Errorf("failed to register ReleaseManager: %w", err)
+	}
+	releaseManager, err := builder.Build().GetReleaseManager(address)
+	if err != nil {
+		client.Close()
+		return nil, nil, fmt.Errorf("failed to get ReleaseManager: %w", err)
+	}
+
+	return &releaseQuery{
+		reader:   releaseManager,
+		opts:     &bind.CallOpts{Context: ctx},
+		avs:      ethcommon.HexToAddress(envCtx.Avs.Address),
+		versions: releaseVersionsByIndex(history),
+	}, client.Close, nil
+}
+
+// list returns every release of each operator set, oldest first
+func (q *releaseQuery) list(operatorSetIds []uint32) ([]PublishedRelease, error) {
+	releases := []PublishedRelease{}
+	for _, id := range operatorSetIds {
+		total, err := q.total(id)
+		if err != nil {
+			return nil, err
+		}
+		for index := uint64(0); index < total; index++ {
+			release, err := q.release(id, index)
+			if err != nil {
+				return nil, err
+			}
+			releases = append(releases, release)
+		}
+	}
+	return releases, nil
+}
+
+// latest returns the newest release of each operator set that has one
+func (q *releaseQuery) latest(operatorSetIds []uint32) ([]PublishedRelease, error) {
+	releases := []PublishedRelease{}
+	for _, id := range operatorSetIds {
+		total, err := q.total(id)
+		if err != nil {
+			return nil, err
+		}
+		if total == 0 {
+			continue
+		}
+		release, err := q.release(id, total-1)
+		if err != nil {
+			return nil, err
+		}
+		releases = append(releases, release)
+	}
+	return releases, nil
+}
+
+// show returns the release with the given version from each operator set that has published it
+func (q *releaseQuery) show(operatorSetIds []uint32, version string) ([]PublishedRelease, error) {
+	releases := []PublishedRelease{}
+	for _, id := range operatorSetIds {
+		for index, v := range q.versions[id] {
+			if v != version {
+				continue
+			}
+			release, err := q.release(id, index)
+			if err != nil {
+				return nil, err
+			}
+			releases = append(releases, release)
+			break
+		}
+	}
+	if len(releases) == 0 {
+		return nil, fmt.Errorf("release %s has not been published", version)
+	}
+	return releases, nil
+}
+
+func (q *releaseQuery) total(operatorSetId uint32) (uint64, error) {
+	total, err := q.reader.GetTotalReleases(q.opts, releasemanager.OperatorSet{Avs: q.avs, Id: operatorSetId})
+	if err != nil {
+		return 0, fmt.Errorf("failed to get release count for operator set %d: %w", operatorSetId, err)
+	}
+	if total == nil {
+		return 0, nil
+	}
+	return total.Uint64(), nil
+}
+
+func (q *releaseQuery) release(operatorSetId uint32, index uint64) (PublishedRelease, error) {
+	release, err := q.reader.GetRelease(q.opts, releasemanager.OperatorSet{Avs: q.avs, Id: operatorSetId}, new(big.Int).SetUint64(index))
+	if err != nil {
+		return PublishedRelease{}, fmt.Errorf("failed to get release %d of operator set %d: %w", index, operatorSetId, err)
+	}
+
+	published := PublishedRelease{
+		OperatorSetID: operatorSetId,
+		Version:       q.versions[operatorSetId][index],
+		Index:         index,
+		UpgradeByTime: release.UpgradeByTime,
+		Artifacts:     make([]PublishedReleaseArtifact, 0, len(release.Artifacts)),
+	}
+	for _, artifact := range release.Artifacts {
+		published.Artifacts = append(published.Artifacts, PublishedReleaseArtifact{
+			Digest:   "sha256:" + hex.EncodeToString(artifact.Digest[:]),
+			Registry: artifact.RegistryUrl,
+		})
+	}
+	return published, nil
+}
+
+// latestCarries reports whether the latest of the total releases of an operator set has exactly the given artifacts
+func (q *releaseQuery) latestCarries(operatorSetId uint32, total uint64, artifacts []releasemanager.IReleaseManagerTypesArtifact) (bool, error) {
+	if total == 0 {
+		return false, nil
+	}
+	release, err := q.reader.GetRelease(q.opts, releasemanager.OperatorSet{Avs: q.avs, Id: operatorSetId}, new(big.Int).SetUint64(total-1))
+	if err != nil {
+		return false, fmt.Errorf("failed to get release %d of operator set %d: %w", total-1, operatorSetId, err)
+	}
+	if len(release.Artifacts) != len(artifacts) {
+		return false, nil
+	}
+	for i, artifact := range release.Artifacts {
+		if artifact.Digest != artifacts[i].Digest || artifact.RegistryUrl != artifacts[i].RegistryUrl {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// writeReleases prints one row per artifact
+func writeReleases(out io.Writer, releases []PublishedRelease) error {
+	if len(releases) == 0 {
+		_, err := fmt.Fprintln(out, "No releases published.")
+		return err
+	}
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "OPERATOR SET\tVERSION\tINDEX\tUPGRADE BY\tREGISTRY\tDIGEST")
+	for _, release := range releases {
+		version := release.Version
+		if version == "" {
+			version = "-"
+		}
+		upgradeBy := time.Unix(int64(release.UpgradeByTime), 0).UTC().Format(time.RFC3339)
+		if len(release.Artifacts) == 0 {
+			fmt.Fprintf(w, "%d\t%s\t%d\t%s\t-\t-\n", release.OperatorSetID, version, release.Index, upgradeBy)
+		}
+		for _, artifact := range release.Artifacts {
+			fmt.Fprintf(w, "%d\t%s\t%d\t%s\t%s\t%s\n", release.OperatorSetID, version, release.Index, upgradeBy, artifact.Registry, artifact.Digest)
+		}
+	}
+	return w.Flush()
+}
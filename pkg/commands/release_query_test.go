@@ -0,0 +1,162 @@
+package commands
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/Layr-Labs/devkit-cli/pkg/common"
+	releasemanager "github.com/Layr-Labs/eigenlayer-contracts/pkg/bindings/ReleaseManager"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeReleaseReader serves releases per operator set ID
+type fakeReleaseReader struct {
+	avs      ethcommon.Address
+	releases map[uint32][]releasemanager.IReleaseManagerTypesRelease
+}
+
+func (f *fakeReleaseReader) GetTotalReleases(_ *bind.CallOpts, operatorSet releasemanager.OperatorSet) (*big.Int, error) {
+	if operatorSet.Avs != f.avs {
+		return nil, errors.New("unknown avs")
+	}
+	return big.NewInt(int64(len(f.releases[operatorSet.Id]))), nil
+}
+
+func (f *fakeReleaseReader) GetRelease(_ *bind.CallOpts, operatorSet releasemanager.OperatorSet, releaseId *big.Int) (releasemanager.IReleaseManagerTypesRelease, error) {
+	releases := f.releases[operatorSet.Id]
+	if releaseId.Uint64() >= uint64(len(releases)) {
+		return releasemanager.IReleaseManagerTypesRelease{}, errors.New("invalid release id")
+	}
+	return releases[releaseId.Uint64()], nil
+}
+
+func newTestReleaseQuery() *releaseQuery {
+	avs := ethcommon.HexToAddress("0x70997970C51812dc3A010C7d01b50e0d17dc79C8")
+	release := func(digest byte, upgradeBy uint32) releasemanager.IReleaseManagerTypesRelease {
+		return releasemanager.IReleaseManagerTypesRelease{
+			Artifacts:     []releasemanager.IReleaseManagerTypesArtifact{{Digest: [32]byte{digest}, RegistryUrl: "ghcr.io/acme/avs"}},
+			UpgradeByTime: upgradeBy,
+		}
+	}
+	return &releaseQuery{
+		reader: &fakeReleaseReader{
+			avs: avs,
+			releases: map[uint32][]releasemanager.IReleaseManagerTypesRelease{
+				0: {release(0xa1, 1000), release(0xa2, 2000)},
+				1: {release(0xb1, 1500)},
+			},
+		},
+		avs: avs,
+		versions: releaseVersionsByIndex([]ReleaseHistoryEntry{
+			{Version: "1.0.0", OperatorSets: []ReleaseHistoryOperatorSet{{ID: 0, Index: 0}}},
+			{Version: "1.1.0", OperatorSets: []ReleaseHistoryOperatorSet{{ID: 0, Index: 1}, {ID: 1, Index: 0}}},
+		}),
+	}
+}
+
+func TestReleaseQueryList(t *testing.T) {
+	q := newTestReleaseQuery()
+
+	releases, err := q.list([]uint32{0, 1, 2})
+	require.NoError(t, err)
+	require.Len(t, releases, 3)
+	require.Equal(t, uint32(0), releases[0].OperatorSetID)
+	require.Equal(t, "1.0.0", releases[0].Version)
+	require.Equal(t, uint64(0), releases[0].Index)
+	require.Equal(t, "1.1.0", releases[1].Version)
+	require.Equal(t, uint32(2000), releases[1].UpgradeByTime)
+	require.Equal(t, "sha256:a2"+strings.Repeat("00", 31), releases[1].Artifacts[0].Digest)
+	require.Equal(t, "ghcr.io/acme/avs", releases[1].Artifacts[0].Registry)
+	require.Equal(t, uint32(1), releases[2].OperatorSetID)
+
+	releases, err = q.list([]uint32{2})
+	require.NoError(t, err)
+	require.Empty(t, releases)
+}
+
+func TestReleaseQueryLatest(t *testing.T) {
+	releases, err := newTestReleaseQuery().latest([]uint32{0, 1, 2})
+	require.NoError(t, err)
+	require.Len(t, releases, 2)
+	require.Equal(t, "1.1.0", releases[0].Version)
+	require.Equal(t, uint32(1), releases[1].OperatorSetID)
+	require.Equal(t, "1.1.0", releases[1].Version)
+}
+
+func TestReleaseQueryLatestCarries(t *testing.T) {
+	q := newTestReleaseQuery()
+	artifacts := func(digest byte, registry string) []releasemanager.IReleaseManagerTypesArtifact {
+		return []releasemanager.IReleaseManagerTypesArtifact{{Digest: [32]byte{digest}, RegistryUrl: registry}}
+	}
+
+	// Only the latest release counts, an older one with the same artifacts is a different release
+	done, err := q.latestCarries(0, 2, artifacts(0xa2, "ghcr.io/acme/avs"))
+	require.NoError(t, err)
+	require.True(t, done)
+	done, err = q.latestCarries(0, 2, artifacts(0xa1, "ghcr.io/acme/avs"))
+	require.NoError(t, err)
+	require.False(t, done)
+	done, err = q.latestCarries(0, 2, artifacts(0xa2, "docker.io/acme/avs"))
+	require.NoError(t, err)
+	require.False(t, done)
+	done, err = q.latestCarries(2, 0, artifacts(0xa2, "ghcr.io/acme/avs"))
+	require.NoError(t, err)
+	require.False(t, done)
+}
+
+func TestReleaseQueryShow(t *testing.T) {
+	q := newTestReleaseQuery()
+
+	releases, err := q.show([]uint32{0, 1}, "1.0.0")
+	require.NoError(t, err)
+	require.Len(t, releases, 1)
+	require.Equal(t, uint32(0), releases[0].OperatorSetID)
+	require.Equal(t, uint64(0), releases[0].Index)
+
+	releases, err = q.show([]uint32{0, 1}, "1.1.0")
+	require.NoError(t, err)
+	require.Len(t, releases, 2)
+	require.Equal(t, uint64(1), releases[0].Index)
+	require.Equal(t, uint64(0), releases[1].Index)
+
+	_, err = q.show([]uint32{0, 1}, "2.0.0")
+	require.ErrorContains(t, err, "release 2.0.0 has not been published")
+}
+
+func TestNewReleaseQueryRequiresReleaseManager(t *testing.T) {
+	// Outside devnet there is no address book to fall back to
+	cfg := &common.ConfigWithContextConfig{Context: map[string]common.ChainContextConfig{
+		"testnet": {Chains: map[string]common.ChainConfig{"l1": {ChainID: 11155111, RPCURL: "http://localhost:8545"}}},
+	}}
+	_, _, err := newReleaseQuery(context.Background(), cfg, "testnet")
+	require.ErrorContains(t, err, "eigenlayer.l1.release_manager is not set in context 'testnet'")
+}
+
+func TestWriteReleases(t *testing.T) {
+	releases, err := newTestReleaseQuery().list([]uint32{0})
+	require.NoError(t, err)
+
+	var out bytes.Buffer
+	require.NoError(t, writeReleases(&out, releases))
+	require.Contains(t, out.String(), "OPERATOR SET")
+	require.Contains(t, out.String(), "1970-01-01T00:33:20Z")
+	require.Contains(t, out.String(), "sha256:a1")
+	require.Contains(t, out.String(), "1.1.0")
+
+	// Releases missing from the history have no version
+	q := newTestReleaseQuery()
+	q.versions = nil
+	releases, err = q.latest([]uint32{1})
+	require.NoError(t, err)
+	require.Empty(t, releases[0].Version)
+
+	out.Reset()
+	require.NoError(t, writeReleases(&out, nil))
+	require.Equal(t, "No releases published.\n", out.String())
+}
@@ -0,0 +1,362 @@
+package commands
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Layr-Labs/devkit-cli/pkg/common"
+	"github.com/Layr-Labs/devkit-cli/pkg/common/contracts"
+	"github.com/Layr-Labs/devkit-cli/pkg/common/devnet"
+	"github.com/Layr-Labs/devkit-cli/pkg/testutils"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli/v2"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	testReleaseDigestA = "sha256:" + strings.Repeat("a1", 32)
+	testReleaseDigestB = "sha256:" + strings.Repeat("b2", 32)
+)
+
+func TestBuildReleaseManifest(t *testing.T) {
+	manifest := &ReleaseManifest{
+		Avs:           "0x70997970C51812dc3A010C7d01b50e0d17dc79C8",
+		Registry:      "ghcr.io/acme/avs",
+		UpgradeByTime: 1750000000,
+	}
+	err := buildReleaseManifest(manifest, map[string][]OperatorSetRelease{
+		"1": {{Digest: testReleaseDigestB, Registry: "ghcr.io/acme/aggregator"}},
+		"0": {{Digest: testReleaseDigestA, Registry: "ghcr.io/acme/avs"}},
+	})
+	require.NoError(t, err)
+	require.Len(t, manifest.OperatorSets, 2)
+	require.Equal(t, uint32(0), manifest.OperatorSets[0].OperatorSetID)
+	require.Equal(t, uint32(1), manifest.OperatorSets[1].OperatorSetID)
+
+	// The calldata decodes back to the operator set and release
+	releaseManagerABI, err := contracts.GetBindingABI(contracts.ReleaseManagerContract)
+	require.NoError(t, err)
+	calldata, err := hexutil.Decode(manifest.OperatorSets[1].Calldata)
+	require.NoError(t, err)
+	method, err := releaseManagerABI.MethodById(calldata[:4])
+	require.NoError(t, err)
+	require.Equal(t, "publishRelease", method.Name)
+	args, err := method.Inputs.Unpack(calldata[4:])
+	require.NoError(t, err)
+	require.Len(t, args, 2)
+	encoded, err := json.Marshal(args)
+	require.NoError(t, err)
+	require.Contains(t, string(encoded), "ghcr.io/acme/aggregator")
+	require.Contains(t, string(encoded), `"upgradeByTime":1750000000`)
+}
+
+func TestMergeComponentMapping(t *testing.T) {
+	aggregator := OperatorSetRelease{Digest: testReleaseDigestA, Registry: "ghcr.io/acme/aggregator"}
+	executor := OperatorSetRelease{Digest: testReleaseDigestB, Registry: "ghcr.io/acme/executor"}
+
+	mapping := make(map[string][]OperatorSetRelease)
+	mergeComponentMapping(mapping, map[string][]OperatorSetRelease{"0": {aggregator}}, nil)
+	// A component with operator sets releases its artifacts to exactly those sets
+	mergeComponentMapping(mapping, map[string][]OperatorSetRelease{"0": {executor}}, []uint32{0, 2})
+	// Artifacts already in an operator set are not listed twice
+	mergeComponentMapping(mapping, map[string][]OperatorSetRelease{"0": {aggregator}}, nil)
+
+	require.Equal(t, map[string][]OperatorSetRelease{
+		"0": {aggregator, executor},
+		"2": {executor},
+	}, mapping)
+
+	require.Equal(t, testReleaseDigestB, componentDigest(mapping, "ghcr.io/acme/executor"))
+	require.Empty(t, componentDigest(mapping, "ghcr.io/acme/performer"))
+}
+
+func TestBuildReleaseManifestRejectsInvalidArtifacts(t *testing.T) {
+	manifest := &ReleaseManifest{Avs: "0x70997970C51812dc3A010C7d01b50e0d17dc79C8"}
+
+	err := buildReleaseManifest(manifest, map[string][]OperatorSetRelease{"0": {{Digest: "sha256:1234", Registry: "r"}}})
+	require.ErrorContains(t, err, "invalid digest for operator set 0 artifact 1")
+
+	err = buildReleaseManifest(manifest, map[string][]OperatorSetRelease{"x": {{Digest: testReleaseDigestA, Registry: "r"}}})
+	require.ErrorContains(t, err, `invalid operator set ID "x"`)
+}
+
+// setupReleaseProject creates a project with built components, a single avs component by default,
+// and a release script printing mapping
+func setupReleaseProject(t *testing.T, mapping string, components ...common.ArtifactComponent) {
+	projectDir, err := testutils.CreateTempAVSProject(t)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = os.RemoveAll(projectDir) })
+
+	originalCwd, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(projectDir))
+	t.Cleanup(func() { _ = os.Chdir(originalCwd) })
+
+	yamlPath, rootNode, contextNode, err := common.LoadContext(devnet.DEVNET_CONTEXT)
+	require.NoError(t, err)
+	artifact := common.GetChildByKey(contextNode, "artifact")
+	for key, value := range map[string]string{"registry": "ghcr.io/acme/avs", "version": "3"} {
+		common.SetMappingValue(artifact, &yaml.Node{Kind: yaml.ScalarNode, Value: key}, &yaml.Node{Kind: yaml.ScalarNode, Value: value})
+	}
+	if len(components) == 0 {
+		components = []common.ArtifactComponent{{Name: "avs", Image: "avs", ArtifactId: "sha256:1234"}}
+	}
+	componentsNode := &yaml.Node{}
+	require.NoError(t, componentsNode.Encode(components))
+	common.SetMappingValue(artifact, &yaml.Node{Kind: yaml.ScalarNode, Value: "components"}, componentsNode)
+	require.NoError(t, common.WriteYAML(yamlPath, rootNode))
+
+	scriptsDir := filepath.Join(projectDir, ".hourglass", "scripts")
+	require.NoError(t, os.MkdirAll(scriptsDir, 0755))
+	script := fmt.Sprintf("#!/bin/bash\necho '%s'\n", mapping)
+	require.NoError(t, os.WriteFile(filepath.Join(scriptsDir, "release.sh"), []byte(script), 0755))
+}
+
+func TestPublishReleaseDryRun(t *testing.T) {
+	registry := newFakeOCIRegistry(t)
+	digest := registry.addIndex("linux/amd64", "linux/arm64")
+	setupReleaseProject(t, fmt.Sprintf(`{"0": [{"digest": "%s", "registry": "%s"}]}`, digest, registry.repository()))
+
+	var out bytes.Buffer
+	app := &cli.App{
+		Name:     "test",
+		Writer:   &out,
+		Commands: []*cli.Command{testutils.WithTestConfigAndNoopLogger(ReleaseCommand)},
+	}
+	upgradeBy := fmt.Sprint(time.Now().Add(time.Hour).Unix())
+	require.NoError(t, app.Run([]string{"app", "release", "publish", "--upgrade-by-time", upgradeBy, "--dry-run"}))
+
+	require.Contains(t, out.String(), "Release 0.0.4 (previous 0.0.3)")
+	require.Contains(t, out.String(), registry.repository()+"@"+digest)
+	require.Contains(t, out.String(), "platforms linux/amd64, linux/arm64")
+	require.Contains(t, out.String(), "calldata: 0x")
+
+	data, err := os.ReadFile("release-manifest.json")
+	require.NoError(t, err)
+	var manifest ReleaseManifest
+	require.NoError(t, json.Unmarshal(data, &manifest))
+	require.True(t, manifest.DryRun)
+	require.True(t, manifest.Verified)
+	require.Equal(t, "0.0.4", manifest.Version)
+	require.Equal(t, ethcommon.HexToAddress("0x70997970C51812dc3A010C7d01b50e0d17dc79C8").Hex(), manifest.Avs)
+	require.Len(t, manifest.OperatorSets, 1)
+
+	// Nothing was published, so the context keeps its version and digest
+	cfg, err := common.LoadConfigWithContextConfig(devnet.DEVNET_CONTEXT)
+	require.NoError(t, err)
+	require.Equal(t, "3", cfg.Context[devnet.DEVNET_CONTEXT].Artifact.Version)
+	require.Empty(t, cfg.Context[devnet.DEVNET_CONTEXT].Artifact.Components[0].Digest)
+}
+
+func TestPublishReleaseDryRunMultipleComponents(t *testing.T) {
+	registry := newFakeOCIRegistry(t)
+	aggregator := registry.addIndex("linux/amd64", "linux/arm64")
+	executor := registry.addIndex("linux/arm64", "linux/amd64")
+	setupReleaseProject(t, "{}",
+		common.ArtifactComponent{Name: "aggregator", Image: "acme-aggregator", Registry: registry.repository()},
+		common.ArtifactComponent{Name: "executor", Image: "acme-executor", Registry: registry.repository(), OperatorSets: []uint32{1}},
+	)
+
+	// The release script reports the digest of the image it was given
+	script := fmt.Sprintf(`#!/bin/bash
+while [ $# -gt 0 ]; do [ "$1" = "--image" ] && image=$2; shift; done
+case $image in
+  acme-aggregator) echo '{"0": [{"digest": "%s", "registry": "%s"}]}' ;;
+  acme-executor) echo '{"0": [{"digest": "%s", "registry": "%s"}]}' ;;
+esac
+`, aggregator, registry.repository(), executor, registry.repository())
+	require.NoError(t, os.WriteFile(filepath.Join(".hourglass", "scripts", "release.sh"), []byte(script), 0755))
+
+	var out bytes.Buffer
+	app := &cli.App{
+		Name:     "test",
+		Writer:   &out,
+		Commands: []*cli.Command{testutils.WithTestConfigAndNoopLogger(ReleaseCommand)},
+	}
+	upgradeBy := fmt.Sprint(time.Now().Add(time.Hour).Unix())
+	require.NoError(t, app.Run([]string{"app", "release", "publish", "--upgrade-by-time", upgradeBy, "--dry-run"}))
+	require.Contains(t, out.String(), "Component:  executor (acme-executor) -> "+registry.repository()+"@"+executor)
+
+	data, err := os.ReadFile("release-manifest.json")
+	require.NoError(t, err)
+	var manifest ReleaseManifest
+	require.NoError(t, json.Unmarshal(data, &manifest))
+	require.Equal(t, []ReleaseManifestComponent{
+		{Name: "aggregator", Image: "acme-aggregator", Registry: registry.repository(), Digest: aggregator, OperatorSets: []uint32{0}},
+		{Name: "executor", Image: "acme-executor", Registry: registry.repository(), Digest: executor, OperatorSets: []uint32{1}},
+	}, manifest.Components)
+
+	// The executor is released to the operator set listed in the context rather than the one its script reported
+	require.Len(t, manifest.OperatorSets, 2)
+	require.Len(t, manifest.OperatorSets[0].Artifacts, 1)
+	require.Equal(t, aggregator, manifest.OperatorSets[0].Artifacts[0].Digest)
+	require.Equal(t, uint32(1), manifest.OperatorSets[1].OperatorSetID)
+	require.Equal(t, executor, manifest.OperatorSets[1].Artifacts[0].Digest)
+}
+
+func TestPublishReleaseRefusesUnresolvableDigests(t *testing.T) {
+	registry := newFakeOCIRegistry(t)
+	setupReleaseProject(t, fmt.Sprintf(`{"0": [{"digest": "%s", "registry": "%s"}]}`, testReleaseDigestA, registry.repository()))
+
+	app := &cli.App{
+		Name:     "test",
+		Writer:   &bytes.Buffer{},
+		Commands: []*cli.Command{testutils.WithTestConfigAndNoopLogger(ReleaseCommand)},
+	}
+	upgradeBy := fmt.Sprint(time.Now().Add(time.Hour).Unix())
+
+	// Publishing stops before any call is made
+	node := rpc.NewServer()
+	require.NoError(t, node.RegisterName("eth", releaseManagerWithoutReleases{}))
+	l1 := httptest.NewServer(node)
+	t.Cleanup(l1.Close)
+	yamlPath, rootNode, contextNode, err := common.LoadContext(devnet.DEVNET_CONTEXT)
+	require.NoError(t, err)
+	_, err = common.WriteToPath(contextNode, []string{"chains", "l1", "rpc_url"}, l1.URL)
+	require.NoError(t, err)
+	require.NoError(t, common.WriteContext(yamlPath, rootNode))
+	err = app.Run([]string{"app", "release", "publish", "--upgrade-by-time", upgradeBy, "--calldata-only"})
+	require.ErrorContains(t, err, "artifact verification failed")
+	_, statErr := os.Stat("release-manifest.json")
+	require.True(t, os.IsNotExist(statErr))
+
+	// A dry run reports the failure and still writes the unverified manifest
+	require.NoError(t, app.Run([]string{"app", "release", "publish", "--upgrade-by-time", upgradeBy, "--dry-run"}))
+	data, err := os.ReadFile("release-manifest.json")
+	require.NoError(t, err)
+	require.Contains(t, string(data), `"verified": false`)
+	require.NoError(t, os.Remove("release-manifest.json"))
+
+	// --skip-verify publishes the reported digest as is
+	require.NoError(t, app.Run([]string{"app", "release", "publish", "--upgrade-by-time", upgradeBy, "--dry-run", "--skip-verify"}))
+	data, err = os.ReadFile("release-manifest.json")
+	require.NoError(t, err)
+	require.Contains(t, string(data), `"verified": false`)
+}
+
+// releaseManagerWithoutReleases is an eth namespace answering every call with zero, so the ReleaseManager reports
+// no releases yet
+type releaseManagerWithoutReleases struct{}
+
+func (releaseManagerWithoutReleases) Call(_ map[string]interface{}, _ string) (hexutil.Bytes, error) {
+	return make([]byte, 32), nil
+}
+
+func TestPublishReleaseCalldataOnlyLeavesContext(t *testing.T) {
+	registry := newFakeOCIRegistry(t)
+	digest := registry.addIndex("linux/amd64", "linux/arm64")
+	setupReleaseProject(t, fmt.Sprintf(`{"0": [{"digest": "%s", "registry": "%s"}]}`, digest, registry.repository()))
+
+	node := rpc.NewServer()
+	require.NoError(t, node.RegisterName("eth", releaseManagerWithoutReleases{}))
+	l1 := httptest.NewServer(node)
+	t.Cleanup(l1.Close)
+	yamlPath, rootNode, contextNode, err := common.LoadContext(devnet.DEVNET_CONTEXT)
+	require.NoError(t, err)
+	_, err = common.WriteToPath(contextNode, []string{"chains", "l1", "rpc_url"}, l1.URL)
+	require.NoError(t, err)
+	require.NoError(t, common.WriteContext(yamlPath, rootNode))
+	contextBefore, err := os.ReadFile(yamlPath)
+	require.NoError(t, err)
+
+	var out bytes.Buffer
+	app := &cli.App{
+		Name:     "test",
+		Writer:   &out,
+		Commands: []*cli.Command{testutils.WithTestConfigAndNoopLogger(ReleaseCommand)},
+	}
+	upgradeBy := fmt.Sprint(time.Now().Add(time.Hour).Unix())
+	require.NoError(t, app.Run([]string{"app", "release", "publish", "--upgrade-by-time", upgradeBy, "--calldata-only"}))
+	require.Contains(t, out.String(), `"data": "0x`)
+
+	// The calls still have to be executed, so neither the context nor the history records the release
+	contextAfter, err := os.ReadFile(yamlPath)
+	require.NoError(t, err)
+	require.Equal(t, string(contextBefore), string(contextAfter))
+	require.NoDirExists(t, filepath.Join(".devkit", "releases"))
+}
+
+func TestPublishReleaseRejectsOlderVersion(t *testing.T) {
+	setupReleaseProject(t, fmt.Sprintf(`{"0": [{"digest": "%s", "registry": "ghcr.io/acme/avs"}]}`, testReleaseDigestA))
+
+	app := &cli.App{
+		Name:     "test",
+		Writer:   &bytes.Buffer{},
+		Commands: []*cli.Command{testutils.WithTestConfigAndNoopLogger(ReleaseCommand)},
+	}
+	upgradeBy := fmt.Sprint(time.Now().Add(time.Hour).Unix())
+	err := app.Run([]string{"app", "release", "publish", "--upgrade-by-time", upgradeBy, "--dry-run", "--version", "0.0.3"})
+	require.ErrorContains(t, err, "version 0.0.3 must be greater than the last published version 0.0.3")
+
+	err = app.Run([]string{"app", "release", "publish", "--upgrade-by-time", upgradeBy, "--dry-run", "--version", "1.0.0", "--bump", "minor"})
+	require.ErrorContains(t, err, "--version and --bump cannot be used together")
+}
+
+func TestPublishReleaseRefusesOtherThanBuiltVersion(t *testing.T) {
+	setupReleaseProject(t, fmt.Sprintf(`{"0": [{"digest": "%s", "registry": "ghcr.io/acme/avs"}]}`, testReleaseDigestA))
+	yamlPath, rootNode, contextNode, err := common.LoadContext(devnet.DEVNET_CONTEXT)
+	require.NoError(t, err)
+	_, err = common.WriteToPath(contextNode, []string{"artifact", "build_version"}, "0.0.4")
+	require.NoError(t, err)
+	require.NoError(t, common.WriteYAML(yamlPath, rootNode))
+
+	app := &cli.App{
+		Name:     "test",
+		Writer:   &bytes.Buffer{},
+		Commands: []*cli.Command{testutils.WithTestConfigAndNoopLogger(ReleaseCommand)},
+	}
+	upgradeBy := fmt.Sprint(time.Now().Add(time.Hour).Unix())
+	err = app.Run([]string{"app", "release", "publish", "--upgrade-by-time", upgradeBy, "--dry-run", "--bump", "minor"})
+	require.ErrorContains(t, err, "the images were built for version 0.0.4 but this release would be 0.1.0")
+}
+
+func TestUpdateContextWithRelease(t *testing.T) {
+	setupReleaseProject(t, "{}",
+		common.ArtifactComponent{Name: "aggregator", Image: "aggregator"},
+		common.ArtifactComponent{Name: "executor", Image: "executor"},
+	)
+
+	require.NoError(t, updateContextWithRelease("4", map[string]string{"aggregator": testReleaseDigestA, "executor": testReleaseDigestB}))
+	cfg, err := common.LoadConfigWithContextConfig(devnet.DEVNET_CONTEXT)
+	require.NoError(t, err)
+	artifact := cfg.Context[devnet.DEVNET_CONTEXT].Artifact
+	require.Equal(t, "4", artifact.Version)
+	require.Equal(t, testReleaseDigestA, artifact.Components[0].Digest)
+	require.Equal(t, testReleaseDigestB, artifact.Components[1].Digest)
+
+	// Without a component digest only the version moves
+	require.NoError(t, updateContextWithRelease("5", map[string]string{"aggregator": ""}))
+	cfg, err = common.LoadConfigWithContextConfig(devnet.DEVNET_CONTEXT)
+	require.NoError(t, err)
+	artifact = cfg.Context[devnet.DEVNET_CONTEXT].Artifact
+	require.Equal(t, "5", artifact.Version)
+	require.Equal(t, testReleaseDigestA, artifact.Components[0].Digest)
+}
+
+func TestUpdateContextWithReleaseSingleComponent(t *testing.T) {
+	setupReleaseProject(t, "{}")
+
+	// Contexts before 0.0.8 record the digest next to their single component
+	yamlPath, rootNode, contextNode, err := common.LoadContext(devnet.DEVNET_CONTEXT)
+	require.NoError(t, err)
+	artifact := common.GetChildByKey(contextNode, "artifact")
+	removeMappingKeys(artifact, "components")
+	common.SetMappingValue(artifact, &yaml.Node{Kind: yaml.ScalarNode, Value: "component"}, &yaml.Node{Kind: yaml.ScalarNode, Value: "avs"})
+	require.NoError(t, common.WriteYAML(yamlPath, rootNode))
+
+	require.NoError(t, updateContextWithRelease("4", map[string]string{"avs": testReleaseDigestA}))
+	cfg, err := common.LoadConfigWithContextConfig(devnet.DEVNET_CONTEXT)
+	require.NoError(t, err)
+	require.Equal(t, testReleaseDigestA, cfg.Context[devnet.DEVNET_CONTEXT].Artifact.Digest)
+	require.Equal(t, []common.ArtifactComponent{{Name: "avs", Image: "avs", Digest: testReleaseDigestA}}, cfg.Context[devnet.DEVNET_CONTEXT].Artifact.ComponentList())
+}
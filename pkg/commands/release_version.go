@@ -0,0 +1,173 @@
+package commands
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Layr-Labs/devkit-cli/pkg/common"
+)
+
+// Version recorded before any release was published
+const initialReleaseVersion = "0.0.0"
+
+// normalizeReleaseVersion turns the context's artifact version into semver.
+// Projects that published before versions were semver recorded a bare release counter N, which maps to 0.0.N.
+func normalizeReleaseVersion(version string) (string, error) {
+	if version == "" {
+		return initialReleaseVersion, nil
+	}
+	if n, err := strconv.ParseUint(version, 10, 64); err == nil {
+		return fmt.Sprintf("0.0.%d", n), nil
+	}
+	if !common.IsSemver(version) {
+		return "", fmt.Errorf("invalid release version %q, expected semver such as 1.4.0", version)
+	}
+	return strings.TrimPrefix(version, "v"), nil
+}
+
+// nextReleaseVersion picks the version of a new release: requested when given, otherwise last bumped by bump
+func nextReleaseVersion(last, requested, bump string) (string, error) {
+	if requested != "" && bump != "" {
+		return "", fmt.Errorf("--version and --bump cannot be used together")
+	}
+	if requested == "" {
+		if bump == "" {
+			bump = "patch"
+		}
+		return common.BumpVersion(last, bump)
+	}
+
+	if !common.IsSemver(requested) {
+		return "", fmt.Errorf("invalid --version %q, expected semver such as 1.4.0", requested)
+	}
+	requested = strings.TrimPrefix(requested, "v")
+	greater, err := common.CompareVersions(requested, last)
+	if err != nil {
+		return "", err
+	}
+	if !greater {
+		return "", fmt.Errorf("version %s must be greater than the last published version %s", requested, last)
+	}
+	return requested, nil
+}
+
+// ReleaseHistoryEntry records a published release. The ReleaseManager only numbers releases,
+// so the history is what ties each onchain release back to its semver version.
+type ReleaseHistoryEntry struct {
+	Time         time.Time                   `json:"time"`
+	Version      string                      `json:"version"`
+	MetadataURI  string                      `json:"metadataUri,omitempty"`
+	OperatorSets []ReleaseHistoryOperatorSet `json:"operatorSets"`
+}
+
+// ReleaseHistoryOperatorSet is the ReleaseManager index a release got in one operator set
+type ReleaseHistoryOperatorSet struct {
+	ID    uint32 `json:"id"`
+	Index uint64 `json:"index"`
+}
+
+// releaseHistoryPath is where the release history of a context is kept, relative to the project root
+func releaseHistoryPath(contextName string) string {
+	return filepath.Join(".devkit", "releases", contextName+".jsonl")
+}
+
+// appendReleaseHistory adds entry to the end of the history at path, creating it if needed
+func appendReleaseHistory(path string, entry ReleaseHistoryEntry) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create release history directory: %w", err)
+	}
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to encode release history entry: %w", err)
+	}
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open release history: %w", err)
+	}
+	defer f.Close()
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("failed to write release history: %w", err)
+	}
+	return nil
+}
+
+// readReleaseHistory returns every entry of the history at path, oldest first. A missing history has no entries.
+func readReleaseHistory(path string) ([]ReleaseHistoryEntry, error) {
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open release history: %w", err)
+	}
+	defer f.Close()
+
+	var entries []ReleaseHistoryEntry
+	scanner := bufio.NewScanner(f)
+	line := 0
+	for scanner.Scan() {
+		line++
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var entry ReleaseHistoryEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return nil, fmt.Errorf("invalid release history entry at %s:%d: %w", path, line, err)
+		}
+		entries = append(entries, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read release history: %w", err)
+	}
+	return entries, nil
+}
+
+// releaseVersionsByIndex maps each operator set's release indexes to the versions published there
+func releaseVersionsByIndex(entries []ReleaseHistoryEntry) map[uint32]map[uint64]string {
+	versions := make(map[uint32]map[uint64]string)
+	for _, entry := range entries {
+		for _, opset := range entry.OperatorSets {
+			if versions[opset.ID] == nil {
+				versions[opset.ID] = make(map[uint64]string)
+			}
+			versions[opset.ID][opset.Index] = entry.Version
+		}
+	}
+	return versions
+}
+
+// latestPublishedVersion returns the highest version among the latest onchain release of each operator set. Operator
+// sets whose latest release is missing from the history are returned in unknown, their version can't be compared.
+func latestPublishedVersion(q *releaseQuery, operatorSetIds []uint32) (latest string, unknown []uint32, err error) {
+	latest = initialReleaseVersion
+	for _, id := range operatorSetIds {
+		total, err := q.total(id)
+		if err != nil {
+			return "", nil, err
+		}
+		if total == 0 {
+			continue
+		}
+		version, ok := q.versions[id][total-1]
+		if !ok {
+			unknown = append(unknown, id)
+			continue
+		}
+		greater, err := common.CompareVersions(version, latest)
+		if err != nil {
+			return "", nil, err
+		}
+		if greater {
+			latest = version
+		}
+	}
+	return latest, unknown, nil
+}
@@ -0,0 +1,90 @@
+package commands
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	releasemanager "github.com/Layr-Labs/eigenlayer-contracts/pkg/bindings/ReleaseManager"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeReleaseVersion(t *testing.T) {
+	for input, expected := range map[string]string{"": "0.0.0", "7": "0.0.7", "1.4.0": "1.4.0", "v2.0.1": "2.0.1"} {
+		version, err := normalizeReleaseVersion(input)
+		require.NoError(t, err, input)
+		require.Equal(t, expected, version, input)
+	}
+
+	_, err := normalizeReleaseVersion("1.4")
+	require.ErrorContains(t, err, `invalid release version "1.4"`)
+}
+
+func TestNextReleaseVersion(t *testing.T) {
+	version, err := nextReleaseVersion("1.4.2", "", "")
+	require.NoError(t, err)
+	require.Equal(t, "1.4.3", version)
+
+	version, err = nextReleaseVersion("1.4.2", "", "major")
+	require.NoError(t, err)
+	require.Equal(t, "2.0.0", version)
+
+	version, err = nextReleaseVersion("1.4.2", "v1.10.0", "")
+	require.NoError(t, err)
+	require.Equal(t, "1.10.0", version)
+
+	_, err = nextReleaseVersion("1.4.2", "1.4.2", "")
+	require.ErrorContains(t, err, "must be greater than the last published version 1.4.2")
+
+	_, err = nextReleaseVersion("1.4.2", "latest", "")
+	require.ErrorContains(t, err, `invalid --version "latest"`)
+
+	_, err = nextReleaseVersion("1.4.2", "", "build")
+	require.ErrorContains(t, err, `invalid version part "build"`)
+}
+
+func TestReleaseHistory(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".devkit", "releases", "devnet.jsonl")
+
+	entries, err := readReleaseHistory(path)
+	require.NoError(t, err)
+	require.Empty(t, entries)
+
+	require.NoError(t, appendReleaseHistory(path, ReleaseHistoryEntry{
+		Time:         time.Unix(1750000000, 0).UTC(),
+		Version:      "1.0.0",
+		MetadataURI:  "https://acme.dev/releases/1.0.0.json",
+		OperatorSets: []ReleaseHistoryOperatorSet{{ID: 0, Index: 0}},
+	}))
+	require.NoError(t, appendReleaseHistory(path, ReleaseHistoryEntry{
+		Version:      "1.1.0",
+		OperatorSets: []ReleaseHistoryOperatorSet{{ID: 0, Index: 1}, {ID: 1, Index: 0}},
+	}))
+
+	entries, err = readReleaseHistory(path)
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	require.Equal(t, "https://acme.dev/releases/1.0.0.json", entries[0].MetadataURI)
+
+	versions := releaseVersionsByIndex(entries)
+	require.Equal(t, "1.0.0", versions[0][0])
+	require.Equal(t, "1.1.0", versions[0][1])
+	require.Equal(t, "1.1.0", versions[1][0])
+}
+
+func TestLatestPublishedVersion(t *testing.T) {
+	q := newTestReleaseQuery()
+
+	latest, unknown, err := latestPublishedVersion(q, []uint32{0, 1, 2})
+	require.NoError(t, err)
+	require.Equal(t, "1.1.0", latest)
+	require.Empty(t, unknown)
+
+	// A release published elsewhere has no known version
+	reader := q.reader.(*fakeReleaseReader)
+	reader.releases[1] = append(reader.releases[1], releasemanager.IReleaseManagerTypesRelease{UpgradeByTime: 3000})
+	latest, unknown, err = latestPublishedVersion(q, []uint32{0, 1})
+	require.NoError(t, err)
+	require.Equal(t, "1.1.0", latest)
+	require.Equal(t, []uint32{1}, unknown)
+}
@@ -0,0 +1,174 @@
+package commands
+
+import (
+	"fmt"
+	"io"
+	"math/big"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/Layr-Labs/devkit-cli/pkg/common"
+	"github.com/Layr-Labs/multichain-go/pkg/chainManager"
+)
+
+// transportChain is a chain from the context that can be read from or transported to
+type transportChain struct {
+	// Names of the context entries that point at this chain (l1 and l2 share a chain on devnet)
+	Names   []string
+	ChainID uint64
+	RPCURL  string
+	Enabled bool
+}
+
+// Name returns the context entry names joined for display
+func (c transportChain) Name() string {
+	return strings.Join(c.Names, ",")
+}
+
+// transportChainsFromContext collects the context's chains keyed by chain ID.
+// Entries sharing a chain ID must share an RPC url and a chain is disabled when any of its entries disables it.
+func transportChainsFromContext(chains map[string]common.ChainConfig) (map[uint64]*transportChain, error) {
+	names := make([]string, 0, len(chains))
+	for name := range chains {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	byID := make(map[uint64]*transportChain)
+	for _, name := range names {
+		cfg := chains[name]
+		if cfg.ChainID <= 0 {
+			return nil, fmt.Errorf("chain '%s' has no chain_id", name)
+		}
+		if cfg.RPCURL == "" {
+			return nil, fmt.Errorf("chain '%s' has no rpc_url", name)
+		}
+
+		id := uint64(cfg.ChainID)
+		existing, ok := byID[id]
+		if !ok {
+			byID[id] = &transportChain{
+				Names:   []string{name},
+				ChainID: id,
+				RPCURL:  cfg.RPCURL,
+				Enabled: cfg.TransportEnabled(),
+			}
+			continue
+		}
+		if existing.RPCURL != cfg.RPCURL {
+			return nil, fmt.Errorf("chains '%s' and '%s' share chain_id %d but use different rpc_url values", existing.Name(), name, id)
+		}
+		existing.Names = append(existing.Names, name)
+		existing.Enabled = existing.Enabled && cfg.TransportEnabled()
+	}
+	return byID, nil
+}
+
+// newTransportChainManager registers every context chain with a chainManager
+func newTransportChainManager(chains map[uint64]*transportChain) (*chainManager.ChainManager, error) {
+	cm := chainManager.NewChainManager()
+	for _, chain := range chains {
+		if err := cm.AddChain(&chainManager.ChainConfig{ChainID: chain.ChainID, RPCUrl: chain.RPCURL}); err != nil {
+			return nil, fmt.Errorf("failed to add chain %s (%d): %w", chain.Name(), chain.ChainID, err)
+		}
+	}
+	return cm, nil
+}
+
+// Outcome of transporting to a single destination
+const (
+	transportStatusTransported = "transported"
+	transportStatusSkipped     = "skipped"
+	transportStatusFailed      = "failed"
+)
+
+// transportDestination is a chain supported by the CrossChainRegistry along with the result of transporting to it
+type transportDestination struct {
+	Name    string
+	ChainID uint64
+	Status  string
+	Reason  string
+	// Transactions that carried the global root, and the outcome of each operator set table
+	GlobalRootTxs []string
+	OperatorSets  []TransportLogOperatorSet
+}
+
+// logEntry converts the destination's outcome into its transport log form
+func (d *transportDestination) logEntry() TransportLogChain {
+	return TransportLogChain{
+		ChainID:       d.ChainID,
+		Name:          d.Name,
+		Status:        d.Status,
+		Error:         d.Reason,
+		GlobalRootTxs: d.GlobalRootTxs,
+		OperatorSets:  d.OperatorSets,
+	}
+}
+
+// resolveTransportDestinations matches the registry's supported chains against the context chains.
+// Chains missing from the context or disabled there are marked skipped.
+func resolveTransportDestinations(supported []*big.Int, chains map[uint64]*transportChain) []*transportDestination {
+	destinations := make([]*transportDestination, 0, len(supported))
+	for _, id := range supported {
+		dest := &transportDestination{ChainID: id.Uint64()}
+		chain, ok := chains[id.Uint64()]
+		switch {
+		case !ok:
+			dest.Status = transportStatusSkipped
+			dest.Reason = "not configured in context"
+		case !chain.Enabled:
+			dest.Name = chain.Name()
+			dest.Status = transportStatusSkipped
+			dest.Reason = "transport disabled in context"
+		default:
+			dest.Name = chain.Name()
+		}
+		destinations = append(destinations, dest)
+	}
+	return destinations
+}
+
+// ignoreAllExcept returns every supported chain ID other than chainID, so a transport call targets a single destination
+func ignoreAllExcept(supported []*big.Int, chainID uint64) []*big.Int {
+	ignore := make([]*big.Int, 0, len(supported))
+	for _, id := range supported {
+		if id.Uint64() != chainID {
+			ignore = append(ignore, id)
+		}
+	}
+	return ignore
+}
+
+// writeTransportResults prints one row per destination
+func writeTransportResults(out io.Writer, destinations []*transportDestination) error {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "CHAIN\tCHAIN ID\tSTATUS\tOPERATOR SETS\tDETAIL")
+	for _, dest := range destinations {
+		name := dest.Name
+		if name == "" {
+			name = "-"
+		}
+		detail := dest.Reason
+		transported := 0
+		var failed []string
+		for _, opset := range dest.OperatorSets {
+			if opset.Status == transportStatusTransported {
+				transported++
+				continue
+			}
+			failed = append(failed, fmt.Sprintf("%s/%d: %s", opset.Avs, opset.ID, opset.Error))
+		}
+		if len(failed) > 0 {
+			detail = strings.Join(failed, "; ")
+		}
+		opsets := fmt.Sprintf("%d", transported)
+		if dest.Status == transportStatusSkipped {
+			opsets = "-"
+		} else if len(failed) > 0 {
+			opsets = fmt.Sprintf("%d (%d failed)", transported, len(failed))
+		}
+		fmt.Fprintf(w, "%s\t%d\t%s\t%s\t%s\n", name, dest.ChainID, dest.Status, opsets, detail)
+	}
+	return w.Flush()
+}
@@ -0,0 +1,87 @@
+package commands
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"github.com/Layr-Labs/devkit-cli/pkg/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransportChainsFromContext(t *testing.T) {
+	disabled := false
+	chains, err := transportChainsFromContext(map[string]common.ChainConfig{
+		"l1":   {ChainID: 31337, RPCURL: "http://localhost:8545"},
+		"l2":   {ChainID: 31337, RPCURL: "http://localhost:8545"},
+		"base": {ChainID: 8453, RPCURL: "http://localhost:9545", Transport: &disabled},
+	})
+	require.NoError(t, err)
+	require.Len(t, chains, 2)
+	require.Equal(t, []string{"l1", "l2"}, chains[31337].Names)
+	require.True(t, chains[31337].Enabled)
+	require.False(t, chains[8453].Enabled)
+
+	_, err = transportChainsFromContext(map[string]common.ChainConfig{
+		"l1": {ChainID: 1, RPCURL: "http://a"},
+		"l2": {ChainID: 1, RPCURL: "http://b"},
+	})
+	require.ErrorContains(t, err, "different rpc_url")
+
+	_, err = transportChainsFromContext(map[string]common.ChainConfig{"l2": {RPCURL: "http://a"}})
+	require.ErrorContains(t, err, "no chain_id")
+}
+
+func TestResolveTransportDestinations(t *testing.T) {
+	chains := map[uint64]*transportChain{
+		31337: {Names: []string{"l1"}, ChainID: 31337, Enabled: true},
+		8453:  {Names: []string{"base"}, ChainID: 8453, Enabled: false},
+	}
+	supported := []*big.Int{big.NewInt(17000), big.NewInt(31337), big.NewInt(8453)}
+
+	dests := resolveTransportDestinations(supported, chains)
+	require.Len(t, dests, 3)
+	require.Equal(t, transportStatusSkipped, dests[0].Status)
+	require.Equal(t, "not configured in context", dests[0].Reason)
+	require.Equal(t, "", dests[1].Status)
+	require.Equal(t, "l1", dests[1].Name)
+	require.Equal(t, transportStatusSkipped, dests[2].Status)
+	require.Equal(t, "transport disabled in context", dests[2].Reason)
+
+	ignore := ignoreAllExcept(supported, 31337)
+	require.Len(t, ignore, 2)
+	require.Equal(t, uint64(17000), ignore[0].Uint64())
+	require.Equal(t, uint64(8453), ignore[1].Uint64())
+}
+
+func TestWriteTransportResults(t *testing.T) {
+	var buf bytes.Buffer
+	err := writeTransportResults(&buf, []*transportDestination{
+		{
+			Name:    "l2",
+			ChainID: 31337,
+			Status:  transportStatusTransported,
+			OperatorSets: []TransportLogOperatorSet{
+				{Avs: "0xavs", ID: 0, Status: transportStatusTransported},
+				{Avs: "0xavs", ID: 1, Status: transportStatusTransported},
+			},
+		},
+		{ChainID: 17000, Status: transportStatusSkipped, Reason: "not configured in context"},
+		{
+			Name:    "base",
+			ChainID: 8453,
+			Status:  transportStatusFailed,
+			OperatorSets: []TransportLogOperatorSet{
+				{Avs: "0xavs", ID: 0, Status: transportStatusTransported},
+				{Avs: "0xavs", ID: 1, Status: transportStatusFailed, Error: "reverted"},
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	out := buf.String()
+	require.Contains(t, out, "CHAIN ID")
+	require.Regexp(t, `l2\s+31337\s+transported\s+2`, out)
+	require.Regexp(t, `-\s+17000\s+skipped\s+-\s+not configured in context`, out)
+	require.Regexp(t, `base\s+8453\s+failed\s+1 \(1 failed\)\s+0xavs/1: reverted`, out)
+}
@@ -0,0 +1,156 @@
+package commands
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/Layr-Labs/devkit-cli/pkg/common/signer"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// TransportLogEntry records a single transport run, one JSON object per line in the transport log
+type TransportLogEntry struct {
+	Time               time.Time           `json:"time"`
+	ReferenceBlock     uint64              `json:"referenceBlock"`
+	ReferenceTimestamp uint32              `json:"referenceTimestamp"`
+	GlobalRoot         string              `json:"globalRoot"`
+	Chains             []TransportLogChain `json:"chains"`
+}
+
+// TransportLogChain is the outcome of a transport run on one destination
+type TransportLogChain struct {
+	ChainID       uint64                    `json:"chainId"`
+	Name          string                    `json:"name,omitempty"`
+	Status        string                    `json:"status"`
+	Error         string                    `json:"error,omitempty"`
+	GlobalRootTxs []string                  `json:"globalRootTxs,omitempty"`
+	OperatorSets  []TransportLogOperatorSet `json:"operatorSets,omitempty"`
+}
+
+// TransportLogOperatorSet is the outcome of transporting one operator set table to a destination
+type TransportLogOperatorSet struct {
+	Avs    string   `json:"avs"`
+	ID     uint32   `json:"id"`
+	Status string   `json:"status"`
+	Error  string   `json:"error,omitempty"`
+	Txs    []string `json:"txs,omitempty"`
+}
+
+// transportLogPath is where the transport history of a context is kept, relative to the project root
+func transportLogPath(contextName string) string {
+	return filepath.Join(".devkit", "transport", contextName+".jsonl")
+}
+
+// appendTransportLog adds entry to the end of the log at path, creating it if needed
+func appendTransportLog(path string, entry TransportLogEntry) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create transport log directory: %w", err)
+	}
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to encode transport log entry: %w", err)
+	}
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open transport log: %w", err)
+	}
+	defer f.Close()
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("failed to write transport log: %w", err)
+	}
+	return nil
+}
+
+// readTransportLog returns every entry of the log at path, oldest first. A missing log has no entries.
+func readTransportLog(path string) ([]TransportLogEntry, error) {
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open transport log: %w", err)
+	}
+	defer f.Close()
+
+	var entries []TransportLogEntry
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 4*1024*1024)
+	line := 0
+	for scanner.Scan() {
+		line++
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var entry TransportLogEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return nil, fmt.Errorf("invalid transport log entry at %s:%d: %w", path, line, err)
+		}
+		entries = append(entries, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read transport log: %w", err)
+	}
+	return entries, nil
+}
+
+// lastTransportedRoots returns, per chain, the global root of the most recent run that reached it
+func lastTransportedRoots(entries []TransportLogEntry) map[uint64]string {
+	roots := make(map[uint64]string)
+	for _, entry := range entries {
+		for _, chain := range entry.Chains {
+			if len(chain.GlobalRootTxs) > 0 || chain.Status == transportStatusTransported {
+				roots[chain.ChainID] = entry.GlobalRoot
+			}
+		}
+	}
+	return roots
+}
+
+// recordingTxSigner remembers the hash of every transaction signed through it so transport
+// results can be tied to the transactions that carried them
+type recordingTxSigner struct {
+	signer.MultichainTxSigner
+
+	mu     sync.Mutex
+	hashes []string
+}
+
+// GetTransactOpts wraps the signer's options so each signed transaction is recorded
+func (r *recordingTxSigner) GetTransactOpts(ctx context.Context, chainID *big.Int) (*bind.TransactOpts, error) {
+	opts, err := r.MultichainTxSigner.GetTransactOpts(ctx, chainID)
+	if err != nil {
+		return nil, err
+	}
+	sign := opts.Signer
+	opts.Signer = func(addr ethcommon.Address, tx *types.Transaction) (*types.Transaction, error) {
+		signed, err := sign(addr, tx)
+		if err != nil {
+			return nil, err
+		}
+		r.mu.Lock()
+		r.hashes = append(r.hashes, signed.Hash().Hex())
+		r.mu.Unlock()
+		return signed, nil
+	}
+	return opts, nil
+}
+
+// take returns the hashes recorded since the last call
+func (r *recordingTxSigner) take() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	hashes := r.hashes
+	r.hashes = nil
+	return hashes
+}
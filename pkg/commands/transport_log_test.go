@@ -0,0 +1,78 @@
+package commands
+
+import (
+	"context"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Layr-Labs/devkit-cli/pkg/common/signer"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransportLogAppendAndRead(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "transport", "devnet.jsonl")
+
+	entries, err := readTransportLog(path)
+	require.NoError(t, err)
+	require.Empty(t, entries)
+
+	first := TransportLogEntry{
+		Time:               time.Unix(1700000000, 0).UTC(),
+		ReferenceBlock:     100,
+		ReferenceTimestamp: 1700000000,
+		GlobalRoot:         "0x01",
+		Chains: []TransportLogChain{
+			{ChainID: 31337, Name: "l1,l2", Status: transportStatusTransported, GlobalRootTxs: []string{"0xaa"}},
+			{ChainID: 8453, Status: transportStatusFailed, Error: "global table root: reverted"},
+		},
+	}
+	second := TransportLogEntry{
+		ReferenceBlock: 200,
+		GlobalRoot:     "0x02",
+		Chains: []TransportLogChain{
+			{ChainID: 8453, Status: transportStatusTransported, GlobalRootTxs: []string{"0xbb"}},
+		},
+	}
+	require.NoError(t, appendTransportLog(path, first))
+	require.NoError(t, appendTransportLog(path, second))
+
+	entries, err = readTransportLog(path)
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	require.Equal(t, first, entries[0])
+	require.Equal(t, uint64(200), entries[1].ReferenceBlock)
+
+	roots := lastTransportedRoots(entries)
+	require.Equal(t, map[uint64]string{31337: "0x01", 8453: "0x02"}, roots)
+
+	// A corrupt line is reported with its position
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0644)
+	require.NoError(t, err)
+	_, err = f.WriteString("{not json\n")
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+	_, err = readTransportLog(path)
+	require.ErrorContains(t, err, "devnet.jsonl:3")
+}
+
+func TestRecordingTxSigner(t *testing.T) {
+	local, err := signer.NewPrivateKeySigner("0xac0974bec39a17e36ba4a6b4d238ff944bacb478cbed5efcae784d7bf4f2ff80")
+	require.NoError(t, err)
+	recorder := &recordingTxSigner{MultichainTxSigner: signer.MultichainTxSigner{Signer: local}}
+
+	opts, err := recorder.GetTransactOpts(context.Background(), big.NewInt(31337))
+	require.NoError(t, err)
+
+	to := ethcommon.HexToAddress("0x1000000000000000000000000000000000000001")
+	tx := types.NewTx(&types.DynamicFeeTx{ChainID: big.NewInt(31337), Gas: 21000, To: &to, GasFeeCap: big.NewInt(1), GasTipCap: big.NewInt(1)})
+	signed, err := opts.Signer(local.Address(), tx)
+	require.NoError(t, err)
+
+	require.Equal(t, []string{signed.Hash().Hex()}, recorder.take())
+	require.Empty(t, recorder.take())
+}
@@ -0,0 +1,404 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"strings"
+
+	"github.com/Layr-Labs/devkit-cli/pkg/common"
+	"github.com/Layr-Labs/eigenlayer-contracts/pkg/bindings/ICrossChainRegistry"
+	"github.com/Layr-Labs/multichain-go/pkg/distribution"
+	"github.com/Layr-Labs/multichain-go/pkg/operatorTableCalculator"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/urfave/cli/v2"
+	merkletree "github.com/wealdtech/go-merkletree/v2"
+	"go.uber.org/zap"
+)
+
+// Curve types as defined by the KeyRegistrar
+const (
+	curveTypeNone  uint8 = 0
+	curveTypeECDSA uint8 = 1
+	curveTypeBN254 uint8 = 2
+)
+
+// ABI of the operator table bytes produced by the CrossChainRegistry:
+// abi.encode(OperatorSet, CurveType, OperatorSetConfig, bytes operatorTableInfo)
+var (
+	operatorTableBytesArgs = mustABIArguments(`[
+		{"type":"tuple","components":[{"name":"avs","type":"address"},{"name":"id","type":"uint32"}]},
+		{"type":"uint8"},
+		{"type":"tuple","components":[{"name":"owner","type":"address"},{"name":"maxStalenessPeriod","type":"uint32"}]},
+		{"type":"bytes"}
+	]`)
+	bn254OperatorSetInfoArgs = mustABIArguments(`[
+		{"type":"tuple","components":[
+			{"name":"operatorInfoTreeRoot","type":"bytes32"},
+			{"name":"numOperators","type":"uint256"},
+			{"name":"aggregatePubkey","type":"tuple","components":[{"name":"X","type":"uint256"},{"name":"Y","type":"uint256"}]},
+			{"name":"totalWeights","type":"uint256[]"}
+		]}
+	]`)
+	ecdsaOperatorInfosArgs = mustABIArguments(`[
+		{"type":"tuple[]","components":[{"name":"pubkey","type":"address"},{"name":"weights","type":"uint256[]"}]}
+	]`)
+	bn254OperatorInfosArgs = mustABIArguments(`[
+		{"type":"tuple[]","components":[
+			{"name":"pubkey","type":"tuple","components":[{"name":"X","type":"uint256"},{"name":"Y","type":"uint256"}]},
+			{"name":"weights","type":"uint256[]"}
+		]}
+	]`)
+	// getOperatorInfos(OperatorSet) on a BN254 table calculator
+	bn254CalculatorABI = mustABI(`[{"type":"function","name":"getOperatorInfos","stateMutability":"view",
+		"inputs":[{"name":"operatorSet","type":"tuple","components":[{"name":"avs","type":"address"},{"name":"id","type":"uint32"}]}],
+		"outputs":[{"type":"tuple[]","components":[
+			{"name":"pubkey","type":"tuple","components":[{"name":"X","type":"uint256"},{"name":"Y","type":"uint256"}]},
+			{"name":"weights","type":"uint256[]"}
+		]}]}]`)
+)
+
+// Go shapes of the decoded table structs, filled through abi.ConvertType
+type (
+	abiOperatorSet struct {
+		Avs ethcommon.Address
+		Id  uint32
+	}
+	abiOperatorSetConfig struct {
+		Owner              ethcommon.Address
+		MaxStalenessPeriod uint32
+	}
+	abiG1Point struct {
+		X *big.Int
+		Y *big.Int
+	}
+	abiBN254OperatorSetInfo struct {
+		OperatorInfoTreeRoot [32]byte
+		NumOperators         *big.Int
+		AggregatePubkey      abiG1Point
+		TotalWeights         []*big.Int
+	}
+	abiECDSAOperatorInfo struct {
+		Pubkey  ethcommon.Address
+		Weights []*big.Int
+	}
+	abiBN254OperatorInfo struct {
+		Pubkey  abiG1Point
+		Weights []*big.Int
+	}
+)
+
+// StakeTablePreview is what a transport at ReferenceBlock would push to destination chains
+type StakeTablePreview struct {
+	ReferenceBlock     uint64                    `json:"referenceBlock"`
+	ReferenceTimestamp uint32                    `json:"referenceTimestamp"`
+	GlobalRoot         string                    `json:"globalRoot"`
+	OperatorSets       []OperatorSetTablePreview `json:"operatorSets"`
+}
+
+// OperatorSetTablePreview is a single leaf of the global stake table tree
+type OperatorSetTablePreview struct {
+	Avs                string   `json:"avs"`
+	ID                 uint32   `json:"id"`
+	Index              uint64   `json:"index"`
+	Leaf               string   `json:"leaf"`
+	Proof              []string `json:"proof"`
+	CurveType          string   `json:"curveType"`
+	Owner              string   `json:"owner"`
+	MaxStalenessPeriod uint32   `json:"maxStalenessPeriod"`
+	TableBytes         string   `json:"tableBytes"`
+	// BN254 tables commit to the operators through a tree root and aggregate key
+	OperatorInfoTreeRoot string                      `json:"operatorInfoTreeRoot,omitempty"`
+	NumOperators         uint64                      `json:"numOperators"`
+	AggregatePubkey      string                      `json:"aggregatePubkey,omitempty"`
+	TotalWeights         []string                    `json:"totalWeights,omitempty"`
+	Operators            []OperatorStakeTablePreview `json:"operators"`
+}
+
+// OperatorStakeTablePreview is an operator's entry in an operator set table
+type OperatorStakeTablePreview struct {
+	Index int `json:"index"`
+	// Address for ECDSA tables, G1 public key for BN254 tables
+	Address string   `json:"address,omitempty"`
+	Pubkey  string   `json:"pubkey,omitempty"`
+	Weights []string `json:"weights"`
+}
+
+// TransportPreview computes the stake table root at a reference block and prints it without signing or sending
+func TransportPreview(cCtx *cli.Context) error {
+	logger := common.LoggerFromContext(cCtx.Context)
+
+	contextName := cCtx.String("context")
+	cfg, err := common.LoadConfigWithContextConfig(contextName)
+	if err != nil {
+		return fmt.Errorf("failed to load configurations: %w", err)
+	}
+	envCtx, ok := cfg.Context[contextName]
+	if !ok {
+		return fmt.Errorf("context '%s' not found in configuration", contextName)
+	}
+	crossChainRegistryAddress := ethcommon.HexToAddress(envCtx.EigenLayer.L1.CrossChainRegistry)
+
+	chains, cm, l1Client, err := loadTransportChains(cfg, contextName, logger)
+	if err != nil {
+		return err
+	}
+	defer closeTransportChains(cm, chains)
+
+	// Pin the reference block, defaulting to the latest finalized block like transport run
+	blockNumber := big.NewInt(int64(rpc.FinalizedBlockNumber))
+	if cCtx.IsSet("block") {
+		blockNumber = new(big.Int).SetUint64(cCtx.Uint64("block"))
+	}
+	block, err := l1Client.RPCClient.BlockByNumber(cCtx.Context, blockNumber)
+	if err != nil {
+		return fmt.Errorf("failed to get reference block: %w", err)
+	}
+
+	tableCalc, err := operatorTableCalculator.NewStakeTableRootCalculator(&operatorTableCalculator.Config{
+		CrossChainRegistryAddress: crossChainRegistryAddress,
+	}, l1Client.RPCClient, zap.NewNop())
+	if err != nil {
+		return fmt.Errorf("failed to create StakeTableRootCalculator: %w", err)
+	}
+	root, tree, dist, err := tableCalc.CalculateStakeTableRoot(cCtx.Context, block.NumberU64())
+	if err != nil {
+		return fmt.Errorf("failed to calculate stake table root: %w", err)
+	}
+
+	preview, err := buildStakeTablePreview(root, tree, dist)
+	if err != nil {
+		return err
+	}
+	preview.ReferenceBlock = block.NumberU64()
+	preview.ReferenceTimestamp = uint32(block.Time())
+
+	// BN254 tables only carry aggregates, read the operator infos from each table calculator
+	registry, err := ICrossChainRegistry.NewICrossChainRegistryCaller(crossChainRegistryAddress, l1Client.RPCClient)
+	if err != nil {
+		return fmt.Errorf("failed to get CrossChainRegistryCaller for %s: %w", crossChainRegistryAddress, err)
+	}
+	for i := range preview.OperatorSets {
+		opset := &preview.OperatorSets[i]
+		if opset.CurveType != "BN254" {
+			continue
+		}
+		operators, err := fetchBN254OperatorInfos(cCtx, l1Client.RPCClient, registry, opset, block.Number())
+		if err != nil {
+			logger.Warn("Could not read BN254 operator infos for %s/%d: %v", opset.Avs, opset.ID, err)
+			continue
+		}
+		opset.Operators = operators
+	}
+
+	if cCtx.Bool("json") {
+		enc := json.NewEncoder(cCtx.App.Writer)
+		enc.SetIndent("", "  ")
+		return enc.Encode(preview)
+	}
+	writeStakeTablePreview(cCtx.App.Writer, preview)
+	return nil
+}
+
+// buildStakeTablePreview decodes every operator set table in dist and attaches its merkle proof
+func buildStakeTablePreview(root [32]byte, tree *merkletree.MerkleTree, dist *distribution.Distribution) (*StakeTablePreview, error) {
+	preview := &StakeTablePreview{
+		GlobalRoot:   hexutil.Encode(root[:]),
+		OperatorSets: []OperatorSetTablePreview{},
+	}
+	// No active generation reservations, the root is zero and there is no tree
+	if tree == nil || dist == nil {
+		return preview, nil
+	}
+
+	for index, opset := range dist.GetOrderedOperatorSets() {
+		tableBytes, ok := dist.GetTableData(opset)
+		if !ok {
+			return nil, fmt.Errorf("operator set %s/%d has no table data", opset.Avs.Hex(), opset.Id)
+		}
+		entry, err := decodeOperatorTableBytes(tableBytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode table for operator set %s/%d: %w", opset.Avs.Hex(), opset.Id, err)
+		}
+		proof, err := tree.GenerateProofWithIndex(uint64(index), 0)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate proof for operator set %s/%d: %w", opset.Avs.Hex(), opset.Id, err)
+		}
+
+		entry.Index = uint64(index)
+		entry.Leaf = hexutil.Encode(crypto.Keccak256(tableBytes))
+		entry.Proof = make([]string, len(proof.Hashes))
+		for i, h := range proof.Hashes {
+			entry.Proof[i] = hexutil.Encode(h)
+		}
+		preview.OperatorSets = append(preview.OperatorSets, *entry)
+	}
+	return preview, nil
+}
+
+// decodeOperatorTableBytes unpacks the operator table of a single operator set
+func decodeOperatorTableBytes(tableBytes []byte) (*OperatorSetTablePreview, error) {
+	values, err := operatorTableBytesArgs.Unpack(tableBytes)
+	if err != nil {
+		return nil, err
+	}
+	opset := *abi.ConvertType(values[0], new(abiOperatorSet)).(*abiOperatorSet)
+	curve := values[1].(uint8)
+	config := *abi.ConvertType(values[2], new(abiOperatorSetConfig)).(*abiOperatorSetConfig)
+	info := values[3].([]byte)
+
+	entry := &OperatorSetTablePreview{
+		Avs:                opset.Avs.Hex(),
+		ID:                 opset.Id,
+		Owner:              config.Owner.Hex(),
+		MaxStalenessPeriod: config.MaxStalenessPeriod,
+		TableBytes:         hexutil.Encode(tableBytes),
+		Operators:          []OperatorStakeTablePreview{},
+	}
+
+	switch curve {
+	case curveTypeBN254:
+		entry.CurveType = "BN254"
+		decoded, err := bn254OperatorSetInfoArgs.Unpack(info)
+		if err != nil {
+			return nil, fmt.Errorf("invalid BN254 operator set info: %w", err)
+		}
+		setInfo := *abi.ConvertType(decoded[0], new(abiBN254OperatorSetInfo)).(*abiBN254OperatorSetInfo)
+		entry.OperatorInfoTreeRoot = hexutil.Encode(setInfo.OperatorInfoTreeRoot[:])
+		entry.NumOperators = setInfo.NumOperators.Uint64()
+		entry.AggregatePubkey = formatG1(setInfo.AggregatePubkey.X, setInfo.AggregatePubkey.Y)
+		entry.TotalWeights = formatWeights(setInfo.TotalWeights)
+	case curveTypeECDSA:
+		entry.CurveType = "ECDSA"
+		decoded, err := ecdsaOperatorInfosArgs.Unpack(info)
+		if err != nil {
+			return nil, fmt.Errorf("invalid ECDSA operator infos: %w", err)
+		}
+		infos := *abi.ConvertType(decoded[0], new([]abiECDSAOperatorInfo)).(*[]abiECDSAOperatorInfo)
+		for i, op := range infos {
+			entry.Operators = append(entry.Operators, OperatorStakeTablePreview{
+				Index:   i,
+				Address: op.Pubkey.Hex(),
+				Weights: formatWeights(op.Weights),
+			})
+		}
+		entry.NumOperators = uint64(len(infos))
+	case curveTypeNone:
+		entry.CurveType = "NONE"
+	default:
+		entry.CurveType = fmt.Sprintf("UNKNOWN(%d)", curve)
+	}
+	return entry, nil
+}
+
+// fetchBN254OperatorInfos reads the per-operator keys and weights from the operator set's table calculator
+func fetchBN254OperatorInfos(cCtx *cli.Context, client *ethclient.Client, registry *ICrossChainRegistry.ICrossChainRegistryCaller, opset *OperatorSetTablePreview, blockNumber *big.Int) ([]OperatorStakeTablePreview, error) {
+	operatorSet := ICrossChainRegistry.OperatorSet{Avs: ethcommon.HexToAddress(opset.Avs), Id: opset.ID}
+	calculator, err := registry.GetOperatorTableCalculator(&bind.CallOpts{Context: cCtx.Context, BlockNumber: blockNumber}, operatorSet)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get operator table calculator: %w", err)
+	}
+
+	input, err := bn254CalculatorABI.Pack("getOperatorInfos", abiOperatorSet{Avs: operatorSet.Avs, Id: operatorSet.Id})
+	if err != nil {
+		return nil, err
+	}
+	output, err := client.CallContract(cCtx.Context, ethereum.CallMsg{To: &calculator, Data: input}, blockNumber)
+	if err != nil {
+		return nil, err
+	}
+	return decodeBN254OperatorInfos(output)
+}
+
+// decodeBN254OperatorInfos unpacks the BN254OperatorInfo[] returned by a table calculator
+func decodeBN254OperatorInfos(output []byte) ([]OperatorStakeTablePreview, error) {
+	decoded, err := bn254OperatorInfosArgs.Unpack(output)
+	if err != nil {
+		return nil, fmt.Errorf("invalid BN254 operator infos: %w", err)
+	}
+	infos := *abi.ConvertType(decoded[0], new([]abiBN254OperatorInfo)).(*[]abiBN254OperatorInfo)
+	operators := make([]OperatorStakeTablePreview, 0, len(infos))
+	for i, op := range infos {
+		operators = append(operators, OperatorStakeTablePreview{
+			Index:   i,
+			Pubkey:  formatG1(op.Pubkey.X, op.Pubkey.Y),
+			Weights: formatWeights(op.Weights),
+		})
+	}
+	return operators, nil
+}
+
+// writeStakeTablePreview prints the preview for humans
+func writeStakeTablePreview(w io.Writer, p *StakeTablePreview) {
+	fmt.Fprintf(w, "Reference block:     %d\n", p.ReferenceBlock)
+	fmt.Fprintf(w, "Reference timestamp: %d\n", p.ReferenceTimestamp)
+	fmt.Fprintf(w, "Global root:         %s\n", p.GlobalRoot)
+	if len(p.OperatorSets) == 0 {
+		fmt.Fprintln(w, "\nNo active generation reservations, nothing would be transported.")
+		return
+	}
+
+	for _, opset := range p.OperatorSets {
+		fmt.Fprintf(w, "\nOperator set %s/%d (index %d, %s)\n", opset.Avs, opset.ID, opset.Index, opset.CurveType)
+		fmt.Fprintf(w, "  owner:                %s\n", opset.Owner)
+		fmt.Fprintf(w, "  max staleness period: %d\n", opset.MaxStalenessPeriod)
+		fmt.Fprintf(w, "  leaf:                 %s\n", opset.Leaf)
+		if opset.OperatorInfoTreeRoot != "" {
+			fmt.Fprintf(w, "  operator info root:   %s\n", opset.OperatorInfoTreeRoot)
+			fmt.Fprintf(w, "  aggregate pubkey:     %s\n", opset.AggregatePubkey)
+			fmt.Fprintf(w, "  total weights:        [%s]\n", strings.Join(opset.TotalWeights, ", "))
+		}
+		fmt.Fprintf(w, "  operators:            %d\n", opset.NumOperators)
+		for _, op := range opset.Operators {
+			key := op.Address
+			if key == "" {
+				key = op.Pubkey
+			}
+			fmt.Fprintf(w, "    [%d] %s weights=[%s]\n", op.Index, key, strings.Join(op.Weights, ", "))
+		}
+		fmt.Fprintln(w, "  proof:")
+		if len(opset.Proof) == 0 {
+			fmt.Fprintln(w, "    (single leaf, the leaf is the root)")
+		}
+		for _, h := range opset.Proof {
+			fmt.Fprintf(w, "    %s\n", h)
+		}
+	}
+}
+
+func formatG1(x, y *big.Int) string {
+	return hexutil.Encode(append(ethcommon.LeftPadBytes(x.Bytes(), 32), ethcommon.LeftPadBytes(y.Bytes(), 32)...))
+}
+
+func formatWeights(weights []*big.Int) []string {
+	out := make([]string, len(weights))
+	for i, w := range weights {
+		out[i] = w.String()
+	}
+	return out
+}
+
+func mustABIArguments(def string) abi.Arguments {
+	var args abi.Arguments
+	if err := json.Unmarshal([]byte(def), &args); err != nil {
+		panic(fmt.Sprintf("invalid ABI arguments: %v", err))
+	}
+	return args
+}
+
+func mustABI(def string) abi.ABI {
+	parsed, err := abi.JSON(strings.NewReader(def))
+	if err != nil {
+		panic(fmt.Sprintf("invalid ABI: %v", err))
+	}
+	return parsed
+}
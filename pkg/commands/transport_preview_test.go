@@ -0,0 +1,134 @@
+package commands
+
+import (
+	"bytes"
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/Layr-Labs/multichain-go/pkg/distribution"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+	merkletree "github.com/wealdtech/go-merkletree/v2"
+	"github.com/wealdtech/go-merkletree/v2/keccak256"
+)
+
+var (
+	previewAVS   = ethcommon.HexToAddress("0x1000000000000000000000000000000000000001")
+	previewOwner = ethcommon.HexToAddress("0x2000000000000000000000000000000000000002")
+)
+
+func packOperatorTable(t *testing.T, id uint32, curve uint8, info []byte) []byte {
+	t.Helper()
+	out, err := operatorTableBytesArgs.Pack(
+		abiOperatorSet{Avs: previewAVS, Id: id},
+		curve,
+		abiOperatorSetConfig{Owner: previewOwner, MaxStalenessPeriod: 3600},
+		info,
+	)
+	require.NoError(t, err)
+	return out
+}
+
+func TestDecodeOperatorTableBytes(t *testing.T) {
+	operator := ethcommon.HexToAddress("0x3000000000000000000000000000000000000003")
+	ecdsaInfo, err := ecdsaOperatorInfosArgs.Pack([]abiECDSAOperatorInfo{
+		{Pubkey: operator, Weights: []*big.Int{big.NewInt(1000)}},
+	})
+	require.NoError(t, err)
+
+	entry, err := decodeOperatorTableBytes(packOperatorTable(t, 0, curveTypeECDSA, ecdsaInfo))
+	require.NoError(t, err)
+	require.Equal(t, "ECDSA", entry.CurveType)
+	require.Equal(t, previewAVS.Hex(), entry.Avs)
+	require.Equal(t, previewOwner.Hex(), entry.Owner)
+	require.Equal(t, uint32(3600), entry.MaxStalenessPeriod)
+	require.Equal(t, uint64(1), entry.NumOperators)
+	require.Equal(t, operator.Hex(), entry.Operators[0].Address)
+	require.Equal(t, []string{"1000"}, entry.Operators[0].Weights)
+
+	treeRoot := crypto.Keccak256Hash([]byte("operators"))
+	bn254Info, err := bn254OperatorSetInfoArgs.Pack(abiBN254OperatorSetInfo{
+		OperatorInfoTreeRoot: treeRoot,
+		NumOperators:         big.NewInt(2),
+		AggregatePubkey:      abiG1Point{X: big.NewInt(1), Y: big.NewInt(2)},
+		TotalWeights:         []*big.Int{big.NewInt(500), big.NewInt(7)},
+	})
+	require.NoError(t, err)
+
+	entry, err = decodeOperatorTableBytes(packOperatorTable(t, 1, curveTypeBN254, bn254Info))
+	require.NoError(t, err)
+	require.Equal(t, "BN254", entry.CurveType)
+	require.Equal(t, treeRoot.Hex(), entry.OperatorInfoTreeRoot)
+	require.Equal(t, uint64(2), entry.NumOperators)
+	require.Equal(t, []string{"500", "7"}, entry.TotalWeights)
+	require.Len(t, hexutil.MustDecode(entry.AggregatePubkey), 64)
+
+	_, err = decodeOperatorTableBytes([]byte{0x01})
+	require.Error(t, err)
+}
+
+func TestDecodeBN254OperatorInfos(t *testing.T) {
+	out, err := bn254OperatorInfosArgs.Pack([]abiBN254OperatorInfo{
+		{Pubkey: abiG1Point{X: big.NewInt(3), Y: big.NewInt(4)}, Weights: []*big.Int{big.NewInt(9)}},
+	})
+	require.NoError(t, err)
+
+	operators, err := decodeBN254OperatorInfos(out)
+	require.NoError(t, err)
+	require.Len(t, operators, 1)
+	require.Equal(t, formatG1(big.NewInt(3), big.NewInt(4)), operators[0].Pubkey)
+	require.Equal(t, []string{"9"}, operators[0].Weights)
+}
+
+func TestBuildStakeTablePreview(t *testing.T) {
+	ecdsaInfo, err := ecdsaOperatorInfosArgs.Pack([]abiECDSAOperatorInfo{})
+	require.NoError(t, err)
+	tables := [][]byte{
+		packOperatorTable(t, 0, curveTypeECDSA, ecdsaInfo),
+		packOperatorTable(t, 1, curveTypeECDSA, ecdsaInfo),
+	}
+	opsets := []distribution.OperatorSet{{Avs: previewAVS, Id: 0}, {Avs: previewAVS, Id: 1}}
+	dist := distribution.NewDistributionWithOperatorSets(opsets)
+	for i, opset := range opsets {
+		require.NoError(t, dist.SetTableData(opset, tables[i]))
+	}
+	tree, err := merkletree.NewTree(merkletree.WithData(tables), merkletree.WithHashType(keccak256.New()))
+	require.NoError(t, err)
+	root := [32]byte(tree.Root())
+
+	preview, err := buildStakeTablePreview(root, tree, dist)
+	require.NoError(t, err)
+	require.Equal(t, hexutil.Encode(root[:]), preview.GlobalRoot)
+	require.Len(t, preview.OperatorSets, 2)
+	for i, opset := range preview.OperatorSets {
+		require.Equal(t, uint64(i), opset.Index)
+		require.Equal(t, uint32(i), opset.ID)
+		require.Equal(t, hexutil.Encode(crypto.Keccak256(tables[i])), opset.Leaf)
+
+		// The proof must lead from the leaf to the global root
+		hashes := make([][]byte, len(opset.Proof))
+		for j, h := range opset.Proof {
+			hashes[j] = hexutil.MustDecode(h)
+		}
+		ok, err := merkletree.VerifyProofUsing(tables[i], false, &merkletree.Proof{Hashes: hashes, Index: uint64(i)}, [][]byte{root[:]}, keccak256.New())
+		require.NoError(t, err)
+		require.True(t, ok)
+	}
+
+	var buf bytes.Buffer
+	writeStakeTablePreview(&buf, preview)
+	require.Contains(t, buf.String(), "Global root:         "+preview.GlobalRoot)
+	require.Contains(t, buf.String(), "Operator set "+previewAVS.Hex()+"/1 (index 1, ECDSA)")
+
+	raw, err := json.Marshal(preview)
+	require.NoError(t, err)
+	require.Contains(t, string(raw), `"globalRoot":"`+preview.GlobalRoot+`"`)
+
+	// Without reservations the root is zero and nothing is transported
+	empty, err := buildStakeTablePreview([32]byte{}, nil, distribution.NewDistribution())
+	require.NoError(t, err)
+	require.Empty(t, empty.OperatorSets)
+}
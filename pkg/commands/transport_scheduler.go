@@ -0,0 +1,393 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"math/rand"
+	"net"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/Layr-Labs/devkit-cli/pkg/common"
+	"github.com/Layr-Labs/devkit-cli/pkg/common/iface"
+	"github.com/Layr-Labs/multichain-go/pkg/operatorTableCalculator"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/robfig/cron/v3"
+	"go.uber.org/zap"
+)
+
+// TransportSchedulerOptions tune how the transport daemon runs, retries and reports
+type TransportSchedulerOptions struct {
+	// Retries after a failed run, each waiting twice as long as the previous one up to MaxRetryBackoff
+	MaxRetries      int
+	RetryBackoff    time.Duration
+	MaxRetryBackoff time.Duration
+	// Random delay of up to Jitter before each run
+	Jitter time.Duration
+	// How long shutdown waits for an in-flight run before cancelling it
+	ShutdownTimeout time.Duration
+	// Address for the JSON health and Prometheus metrics endpoints, disabled when empty
+	HealthAddr string
+	// How often to check the stake table root for changes, disabled when zero
+	WatchInterval time.Duration
+}
+
+// DefaultTransportSchedulerOptions are used when the scheduler is started without flags
+var DefaultTransportSchedulerOptions = TransportSchedulerOptions{
+	MaxRetries:      3,
+	RetryBackoff:    30 * time.Second,
+	MaxRetryBackoff: 10 * time.Minute,
+	ShutdownTimeout: 2 * time.Minute,
+}
+
+// TransportSchedulerStatus is served by the health endpoint
+type TransportSchedulerStatus struct {
+	Running       bool       `json:"running"`
+	LastRun       *time.Time `json:"lastRun,omitempty"`
+	LastSuccess   *time.Time `json:"lastSuccess,omitempty"`
+	LastError     string     `json:"lastError,omitempty"`
+	LastErrorAt   *time.Time `json:"lastErrorAt,omitempty"`
+	NextRun       *time.Time `json:"nextRun,omitempty"`
+	Runs          uint64     `json:"runs"`
+	Successes     uint64     `json:"successes"`
+	Failures      uint64     `json:"failures"`
+	Retries       uint64     `json:"retries"`
+	Skipped       uint64     `json:"skipped"`
+	StakeTriggers uint64     `json:"stakeTriggers"`
+	LastStakeRoot string     `json:"lastStakeRoot,omitempty"`
+}
+
+// TransportScheduler runs transports on a cron schedule and on stake changes, never overlapping runs
+type TransportScheduler struct {
+	opts   TransportSchedulerOptions
+	cron   *cron.Cron
+	run    func(ctx context.Context) error
+	probe  func(ctx context.Context) ([32]byte, error)
+	logger iface.Logger
+
+	running atomic.Bool
+	wg      sync.WaitGroup
+	mu      sync.Mutex
+	status  TransportSchedulerStatus
+
+	// Attempts run on runCtx so shutdown can let them finish, waits between attempts use stopCtx
+	stopCtx   context.Context
+	runCtx    context.Context
+	cancelRun context.CancelFunc
+}
+
+// NewTransportScheduler validates cronExpr and prepares a scheduler calling run for every transport
+func NewTransportScheduler(cronExpr string, parser cron.Parser, run func(ctx context.Context) error, opts TransportSchedulerOptions, logger iface.Logger) (*TransportScheduler, error) {
+	if _, err := parser.Parse(cronExpr); err != nil {
+		return nil, fmt.Errorf("invalid cron expression: %w", err)
+	}
+	s := &TransportScheduler{
+		opts:    opts,
+		cron:    cron.New(cron.WithParser(parser)),
+		run:     run,
+		logger:  logger,
+		stopCtx: context.Background(),
+		runCtx:  context.Background(),
+	}
+	if _, err := s.cron.AddFunc(cronExpr, func() { s.Trigger("schedule") }); err != nil {
+		return nil, fmt.Errorf("failed to add transport function to scheduler: %w", err)
+	}
+	return s, nil
+}
+
+// WatchStake makes the scheduler poll probe every WatchInterval and transport whenever the root changes
+func (s *TransportScheduler) WatchStake(probe func(ctx context.Context) ([32]byte, error), initial [32]byte) {
+	s.probe = probe
+	if initial != ([32]byte{}) {
+		s.status.LastStakeRoot = hexutil.Encode(initial[:])
+	}
+}
+
+// Run starts the scheduler and blocks until ctx is done, then waits for the in-flight run to finish
+func (s *TransportScheduler) Run(ctx context.Context) error {
+	s.stopCtx = ctx
+	s.runCtx, s.cancelRun = context.WithCancel(context.WithoutCancel(ctx))
+	defer s.cancelRun()
+
+	var server *http.Server
+	if s.opts.HealthAddr != "" {
+		listener, err := net.Listen("tcp", s.opts.HealthAddr)
+		if err != nil {
+			return fmt.Errorf("failed to listen on %s: %w", s.opts.HealthAddr, err)
+		}
+		server = &http.Server{Handler: s.Handler(), ReadHeaderTimeout: 5 * time.Second}
+		go func() {
+			if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				s.logger.Error("Transport health endpoint failed: %v", err)
+			}
+		}()
+		s.logger.Info("Transport health endpoint listening on http://%s", listener.Addr())
+	}
+
+	s.cron.Start()
+	s.logger.Info("Transport scheduler started.")
+	if next := s.nextRun(); next != nil {
+		s.logger.Info("Next scheduled transport at: %s", next.Format(time.RFC3339))
+	}
+
+	if s.probe != nil && s.opts.WatchInterval > 0 {
+		go s.watchStake(ctx)
+	}
+
+	// If the Context closes, stop the scheduler
+	<-ctx.Done()
+	s.cron.Stop()
+	if server != nil {
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		_ = server.Shutdown(shutdownCtx)
+		cancel()
+	}
+
+	// Let an in-flight run finish, cancelling it once the grace period is over
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+	if s.running.Load() {
+		s.logger.Info("Waiting up to %s for the running transport to finish...", s.opts.ShutdownTimeout)
+	}
+	select {
+	case <-done:
+	case <-time.After(s.opts.ShutdownTimeout):
+		s.logger.Warn("Transport did not finish within %s, cancelling it", s.opts.ShutdownTimeout)
+		s.cancelRun()
+		<-done
+	}
+	s.logger.Info("Transport scheduler stopped.")
+	return nil
+}
+
+// Trigger starts a transport unless one is already running
+func (s *TransportScheduler) Trigger(reason string) {
+	if s.stopCtx.Err() != nil {
+		return
+	}
+	if !s.running.CompareAndSwap(false, true) {
+		s.mu.Lock()
+		s.status.Skipped++
+		s.mu.Unlock()
+		s.logger.Warn("Skipping %s transport, previous run still in progress", reason)
+		return
+	}
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		defer s.running.Store(false)
+		s.execute(reason)
+	}()
+}
+
+// execute runs a transport with jitter and exponential retries
+func (s *TransportScheduler) execute(reason string) {
+	if s.opts.Jitter > 0 {
+		if !s.wait(time.Duration(rand.Int63n(int64(s.opts.Jitter)))) {
+			return
+		}
+	}
+
+	backoff := s.opts.RetryBackoff
+	for attempt := 0; ; attempt++ {
+		now := time.Now()
+		s.mu.Lock()
+		s.status.Runs++
+		s.status.LastRun = &now
+		if attempt > 0 {
+			s.status.Retries++
+		}
+		s.mu.Unlock()
+
+		s.logger.Info("Starting %s transport (attempt %d of %d)", reason, attempt+1, s.opts.MaxRetries+1)
+		err := s.run(s.runCtx)
+
+		now = time.Now()
+		s.mu.Lock()
+		if err == nil {
+			s.status.Successes++
+			s.status.LastSuccess = &now
+		} else {
+			s.status.Failures++
+			s.status.LastError = err.Error()
+			s.status.LastErrorAt = &now
+		}
+		s.mu.Unlock()
+
+		if err == nil {
+			s.logger.Info("Scheduled transport succeeded")
+			return
+		}
+		s.logger.Error("Scheduled transport failed: %v", err)
+		if attempt >= s.opts.MaxRetries {
+			return
+		}
+
+		s.logger.Info("Retrying transport in %s", backoff)
+		if !s.wait(backoff) {
+			return
+		}
+		backoff *= 2
+		if s.opts.MaxRetryBackoff > 0 && backoff > s.opts.MaxRetryBackoff {
+			backoff = s.opts.MaxRetryBackoff
+		}
+	}
+}
+
+// wait sleeps for d, returning false if the scheduler is stopping
+func (s *TransportScheduler) wait(d time.Duration) bool {
+	select {
+	case <-s.stopCtx.Done():
+		return false
+	case <-time.After(d):
+		return true
+	}
+}
+
+// watchStake triggers a transport whenever the calculated stake table root changes
+func (s *TransportScheduler) watchStake(ctx context.Context) {
+	ticker := time.NewTicker(s.opts.WatchInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		root, err := s.probe(ctx)
+		if err != nil {
+			s.logger.Warn("Failed to check stake table root: %v", err)
+			continue
+		}
+		current := hexutil.Encode(root[:])
+
+		s.mu.Lock()
+		previous := s.status.LastStakeRoot
+		s.status.LastStakeRoot = current
+		changed := previous != "" && previous != current
+		if changed {
+			s.status.StakeTriggers++
+		}
+		s.mu.Unlock()
+
+		if changed {
+			s.logger.Info("Stake table root changed from %s to %s", previous, current)
+			s.Trigger("stake change")
+		}
+	}
+}
+
+// Status returns a snapshot of the scheduler state
+func (s *TransportScheduler) Status() TransportSchedulerStatus {
+	s.mu.Lock()
+	status := s.status
+	s.mu.Unlock()
+	status.Running = s.running.Load()
+	status.NextRun = s.nextRun()
+	return status
+}
+
+// nextRun is when the cron schedule fires next, computed from the schedule itself before cron has started
+func (s *TransportScheduler) nextRun() *time.Time {
+	entries := s.cron.Entries()
+	if len(entries) == 0 {
+		return nil
+	}
+	next := entries[0].Next
+	if next.IsZero() {
+		next = entries[0].Schedule.Next(time.Now())
+	}
+	return &next
+}
+
+// Handler serves the status as JSON on /health and as Prometheus metrics on /metrics
+func (s *TransportScheduler) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(s.Status())
+	})
+	mux.HandleFunc("/metrics", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+		writeTransportMetrics(w, s.Status())
+	})
+	return mux
+}
+
+// writeTransportMetrics renders status in the Prometheus text exposition format
+func writeTransportMetrics(w io.Writer, status TransportSchedulerStatus) {
+	metric := func(name, kind, help string, value float64) {
+		fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n%s %g\n", name, help, name, kind, name, value)
+	}
+	timestamp := func(t *time.Time) float64 {
+		if t == nil {
+			return 0
+		}
+		return float64(t.Unix())
+	}
+	running := 0.0
+	if status.Running {
+		running = 1
+	}
+
+	fmt.Fprintf(w, "# HELP devkit_transport_runs_total Transport attempts by result.\n# TYPE devkit_transport_runs_total counter\n")
+	fmt.Fprintf(w, "devkit_transport_runs_total{result=\"success\"} %d\n", status.Successes)
+	fmt.Fprintf(w, "devkit_transport_runs_total{result=\"failure\"} %d\n", status.Failures)
+	metric("devkit_transport_retries_total", "counter", "Transport attempts that were retries of a failed run.", float64(status.Retries))
+	metric("devkit_transport_skipped_total", "counter", "Transports skipped because a run was in progress.", float64(status.Skipped))
+	metric("devkit_transport_stake_triggers_total", "counter", "Transports triggered by a stake table root change.", float64(status.StakeTriggers))
+	metric("devkit_transport_running", "gauge", "Whether a transport is in progress.", running)
+	metric("devkit_transport_last_success_timestamp_seconds", "gauge", "Unix time of the last successful transport.", timestamp(status.LastSuccess))
+	metric("devkit_transport_last_failure_timestamp_seconds", "gauge", "Unix time of the last failed transport.", timestamp(status.LastErrorAt))
+	metric("devkit_transport_next_run_timestamp_seconds", "gauge", "Unix time of the next scheduled transport.", timestamp(status.NextRun))
+}
+
+// newStakeRootProbe connects to the context's L1 once and returns a probe that calculates the stake table root
+// at the latest finalized block on every call, along with a function that closes the connections
+func newStakeRootProbe(ctx context.Context, contextName string) (func(ctx context.Context) ([32]byte, error), func(), error) {
+	logger := common.LoggerFromContext(ctx)
+	cfg, err := common.LoadConfigWithContextConfig(contextName)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load configurations: %w", err)
+	}
+	envCtx, ok := cfg.Context[contextName]
+	if !ok {
+		return nil, nil, fmt.Errorf("context '%s' not found in configuration", contextName)
+	}
+	chains, cm, l1Client, err := loadTransportChains(cfg, contextName, logger)
+	if err != nil {
+		return nil, nil, err
+	}
+	closeChains := func() { closeTransportChains(cm, chains) }
+
+	tableCalc, err := operatorTableCalculator.NewStakeTableRootCalculator(&operatorTableCalculator.Config{
+		CrossChainRegistryAddress: ethcommon.HexToAddress(envCtx.EigenLayer.L1.CrossChainRegistry),
+	}, l1Client.RPCClient, zap.NewNop())
+	if err != nil {
+		closeChains()
+		return nil, nil, fmt.Errorf("failed to create StakeTableRootCalculator: %w", err)
+	}
+
+	probe := func(ctx context.Context) ([32]byte, error) {
+		block, err := l1Client.RPCClient.BlockByNumber(ctx, big.NewInt(int64(rpc.FinalizedBlockNumber)))
+		if err != nil {
+			return [32]byte{}, err
+		}
+		root, _, _, err := tableCalc.CalculateStakeTableRoot(ctx, block.NumberU64())
+		return root, err
+	}
+	return probe, closeChains, nil
+}
@@ -0,0 +1,194 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Layr-Labs/devkit-cli/pkg/common/logger"
+	"github.com/robfig/cron/v3"
+	"github.com/stretchr/testify/require"
+)
+
+// A schedule that never fires during a test, runs are started with Trigger
+const idleCronExpr = "0 0 1 1 *"
+
+func newTestScheduler(t *testing.T, run func(ctx context.Context) error, opts TransportSchedulerOptions) *TransportScheduler {
+	t.Helper()
+	parser := cron.NewParser(cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow)
+	s, err := NewTransportScheduler(idleCronExpr, parser, run, opts, logger.NewNoopLogger())
+	require.NoError(t, err)
+	return s
+}
+
+func TestTransportSchedulerSkipsWhileRunning(t *testing.T) {
+	release := make(chan struct{})
+	var runs atomic.Int32
+	s := newTestScheduler(t, func(context.Context) error {
+		runs.Add(1)
+		<-release
+		return nil
+	}, TransportSchedulerOptions{})
+
+	s.Trigger("test")
+	require.Eventually(t, func() bool { return s.Status().Running }, time.Second, 5*time.Millisecond)
+	s.Trigger("test")
+	close(release)
+	s.wg.Wait()
+
+	status := s.Status()
+	require.Equal(t, int32(1), runs.Load())
+	require.Equal(t, uint64(1), status.Skipped)
+	require.Equal(t, uint64(1), status.Successes)
+	require.False(t, status.Running)
+}
+
+func TestTransportSchedulerRetriesWithBackoff(t *testing.T) {
+	var attempts atomic.Int32
+	s := newTestScheduler(t, func(context.Context) error {
+		if attempts.Add(1) < 3 {
+			return errors.New("rpc unavailable")
+		}
+		return nil
+	}, TransportSchedulerOptions{MaxRetries: 3, RetryBackoff: 5 * time.Millisecond, MaxRetryBackoff: 8 * time.Millisecond})
+
+	s.Trigger("test")
+	s.wg.Wait()
+
+	status := s.Status()
+	require.Equal(t, uint64(3), status.Runs)
+	require.Equal(t, uint64(2), status.Failures)
+	require.Equal(t, uint64(2), status.Retries)
+	require.Equal(t, uint64(1), status.Successes)
+	require.Equal(t, "rpc unavailable", status.LastError)
+	require.NotNil(t, status.LastSuccess)
+
+	// Once retries are exhausted the run is given up
+	failing := newTestScheduler(t, func(context.Context) error {
+		return errors.New("reverted")
+	}, TransportSchedulerOptions{MaxRetries: 1, RetryBackoff: time.Millisecond})
+	failing.Trigger("test")
+	failing.wg.Wait()
+	require.Equal(t, uint64(2), failing.Status().Failures)
+	require.Nil(t, failing.Status().LastSuccess)
+}
+
+func TestTransportSchedulerGracefulShutdown(t *testing.T) {
+	started := make(chan struct{})
+	var cancelled atomic.Bool
+	s := newTestScheduler(t, func(ctx context.Context) error {
+		close(started)
+		select {
+		case <-ctx.Done():
+			cancelled.Store(true)
+		case <-time.After(100 * time.Millisecond):
+		}
+		return nil
+	}, TransportSchedulerOptions{ShutdownTimeout: time.Second})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- s.Run(ctx) }()
+
+	require.Eventually(t, func() bool { return s.cron.Entries()[0].Next.After(time.Now()) }, time.Second, 5*time.Millisecond)
+	s.Trigger("test")
+	<-started
+	cancel()
+
+	require.NoError(t, <-done)
+	require.False(t, cancelled.Load(), "in-flight run should finish within the shutdown timeout")
+	require.Equal(t, uint64(1), s.Status().Successes)
+
+	// Triggers after shutdown are ignored
+	s.Trigger("test")
+	require.False(t, s.Status().Running)
+}
+
+func TestTransportSchedulerShutdownTimeoutCancelsRun(t *testing.T) {
+	started := make(chan struct{})
+	s := newTestScheduler(t, func(ctx context.Context) error {
+		close(started)
+		<-ctx.Done()
+		return ctx.Err()
+	}, TransportSchedulerOptions{ShutdownTimeout: 20 * time.Millisecond})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- s.Run(ctx) }()
+	require.Eventually(t, func() bool { return s.cron.Entries()[0].Next.After(time.Now()) }, time.Second, 5*time.Millisecond)
+
+	s.Trigger("test")
+	<-started
+	cancel()
+
+	select {
+	case err := <-done:
+		require.NoError(t, err)
+	case <-time.After(2 * time.Second):
+		t.Fatal("scheduler did not stop after the shutdown timeout")
+	}
+	require.Equal(t, uint64(1), s.Status().Failures)
+}
+
+func TestTransportSchedulerWatchStake(t *testing.T) {
+	var runs atomic.Int32
+	s := newTestScheduler(t, func(context.Context) error {
+		runs.Add(1)
+		return nil
+	}, TransportSchedulerOptions{WatchInterval: 5 * time.Millisecond})
+
+	var probes atomic.Int32
+	s.WatchStake(func(context.Context) ([32]byte, error) {
+		// The root changes once, on the third probe
+		if probes.Add(1) >= 3 {
+			return [32]byte{2}, nil
+		}
+		return [32]byte{1}, nil
+	}, [32]byte{1})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- s.Run(ctx) }()
+
+	require.Eventually(t, func() bool { return runs.Load() == 1 && probes.Load() > 5 }, 2*time.Second, 5*time.Millisecond)
+	cancel()
+	require.NoError(t, <-done)
+
+	require.Equal(t, int32(1), runs.Load())
+	require.Equal(t, uint64(1), s.Status().StakeTriggers)
+	require.Equal(t, "0x0200000000000000000000000000000000000000000000000000000000000000", s.Status().LastStakeRoot)
+}
+
+func TestTransportSchedulerHandler(t *testing.T) {
+	s := newTestScheduler(t, func(context.Context) error { return errors.New("boom") }, TransportSchedulerOptions{})
+	s.Trigger("test")
+	s.wg.Wait()
+
+	server := httptest.NewServer(s.Handler())
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/health")
+	require.NoError(t, err)
+	var status TransportSchedulerStatus
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&status))
+	resp.Body.Close()
+	require.Equal(t, "boom", status.LastError)
+	require.Equal(t, uint64(1), status.Failures)
+	require.NotNil(t, status.NextRun)
+
+	resp, err = http.Get(server.URL + "/metrics")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	raw, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	body := string(raw)
+	require.Contains(t, body, `devkit_transport_runs_total{result="failure"} 1`)
+	require.Contains(t, body, "# TYPE devkit_transport_running gauge")
+	require.Contains(t, body, "devkit_transport_last_success_timestamp_seconds 0")
+}
@@ -0,0 +1,322 @@
+package commands
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/Layr-Labs/devkit-cli/pkg/common"
+	"github.com/Layr-Labs/eigenlayer-contracts/pkg/bindings/ICrossChainRegistry"
+	"github.com/Layr-Labs/eigenlayer-contracts/pkg/bindings/IOperatorTableUpdater"
+	"github.com/Layr-Labs/multichain-go/pkg/chainManager"
+	"github.com/Layr-Labs/multichain-go/pkg/operatorTableCalculator"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/urfave/cli/v2"
+	"go.uber.org/zap"
+)
+
+// Views used to read the operator set tables held by the destination certificate verifiers
+var (
+	operatorTableUpdaterViewsABI = mustABI(`[{"type":"function","name":"getCertificateVerifier","stateMutability":"view",
+		"inputs":[{"name":"curveType","type":"uint8"}],"outputs":[{"type":"address"}]}]`)
+	certificateVerifierViewsABI = mustABI(`[
+		{"type":"function","name":"latestReferenceTimestamp","stateMutability":"view",
+			"inputs":[{"name":"operatorSet","type":"tuple","components":[{"name":"avs","type":"address"},{"name":"id","type":"uint32"}]}],
+			"outputs":[{"type":"uint32"}]},
+		{"type":"function","name":"getOperatorSetInfo","stateMutability":"view",
+			"inputs":[
+				{"name":"operatorSet","type":"tuple","components":[{"name":"avs","type":"address"},{"name":"id","type":"uint32"}]},
+				{"name":"referenceTimestamp","type":"uint32"}],
+			"outputs":[{"type":"tuple","components":[
+				{"name":"operatorInfoTreeRoot","type":"bytes32"},
+				{"name":"numOperators","type":"uint256"},
+				{"name":"aggregatePubkey","type":"tuple","components":[{"name":"X","type":"uint256"},{"name":"Y","type":"uint256"}]},
+				{"name":"totalWeights","type":"uint256[]"}]}]},
+		{"type":"function","name":"getOperatorInfos","stateMutability":"view",
+			"inputs":[
+				{"name":"operatorSet","type":"tuple","components":[{"name":"avs","type":"address"},{"name":"id","type":"uint32"}]},
+				{"name":"referenceTimestamp","type":"uint32"}],
+			"outputs":[{"type":"tuple[]","components":[{"name":"pubkey","type":"address"},{"name":"weights","type":"uint256[]"}]}]}
+	]`)
+)
+
+// transportDiscrepancy is a single mismatch between the expected and onchain stake tables
+type transportDiscrepancy struct {
+	Chain       string
+	ChainID     uint64
+	OperatorSet string
+	Check       string
+	Expected    string
+	Actual      string
+}
+
+// VerifyActiveStakeTableRoots checks the global root and every operator set table on every destination
+// against the context, the transport log and the tables recalculated on L1, reporting all discrepancies
+func VerifyActiveStakeTableRoots(cCtx *cli.Context) error {
+	logger := common.LoggerFromContext(cCtx.Context)
+
+	contextName := cCtx.String("context")
+	cfg, err := common.LoadConfigWithContextConfig(contextName)
+	if err != nil {
+		return fmt.Errorf("failed to load configurations: %w", err)
+	}
+	envCtx, ok := cfg.Context[contextName]
+	if !ok {
+		return fmt.Errorf("context '%s' not found in configuration", contextName)
+	}
+	crossChainRegistryAddress := ethcommon.HexToAddress(envCtx.EigenLayer.L1.CrossChainRegistry)
+
+	// Expected roots come from the context, falling back to the transport log
+	expectedRoots, err := readActiveStakeRoots(contextName)
+	if err != nil {
+		return err
+	}
+	entries, err := readTransportLog(transportLogPath(contextName))
+	if err != nil {
+		return err
+	}
+	for chainID, rootHex := range lastTransportedRoots(entries) {
+		if _, ok := expectedRoots[chainID]; ok {
+			continue
+		}
+		if b, err := hexutil.Decode(rootHex); err == nil && len(b) == 32 {
+			expectedRoots[chainID] = [32]byte(b)
+		}
+	}
+
+	chains, cm, l1Client, err := loadTransportChains(cfg, contextName, logger)
+	if err != nil {
+		return err
+	}
+	defer closeTransportChains(cm, chains)
+	registry, err := ICrossChainRegistry.NewICrossChainRegistryCaller(crossChainRegistryAddress, l1Client.RPCClient)
+	if err != nil {
+		return fmt.Errorf("failed to get CrossChainRegistryCaller for %s: %w", crossChainRegistryAddress, err)
+	}
+	chainIds, addresses, err := registry.GetSupportedChains(&bind.CallOpts{Context: cCtx.Context})
+	if err != nil {
+		return fmt.Errorf("failed to get supported chains: %w", err)
+	}
+	tableCalc, err := operatorTableCalculator.NewStakeTableRootCalculator(&operatorTableCalculator.Config{
+		CrossChainRegistryAddress: crossChainRegistryAddress,
+	}, l1Client.RPCClient, zap.NewNop())
+	if err != nil {
+		return fmt.Errorf("failed to create StakeTableRootCalculator: %w", err)
+	}
+
+	// Recalculated tables per reference block, destinations usually share one
+	previews := make(map[uint64]*StakeTablePreview)
+	var discrepancies []transportDiscrepancy
+	checkedChains, checkedOpsets := 0, 0
+
+	for i, id := range chainIds {
+		chain, ok := chains[id.Uint64()]
+		if !ok || !chain.Enabled {
+			continue
+		}
+		checkedChains++
+		report := func(opset, check, expected, actual string) {
+			discrepancies = append(discrepancies, transportDiscrepancy{
+				Chain: chain.Name(), ChainID: chain.ChainID, OperatorSet: opset, Check: check, Expected: expected, Actual: actual,
+			})
+		}
+
+		client, err := cm.GetChainForId(chain.ChainID)
+		if err != nil {
+			report("-", "connection", "reachable", err.Error())
+			continue
+		}
+		updater, err := IOperatorTableUpdater.NewIOperatorTableUpdater(addresses[i], client.RPCClient)
+		if err != nil {
+			report("-", "connection", "reachable", err.Error())
+			continue
+		}
+		callOpts := &bind.CallOpts{Context: cCtx.Context}
+
+		// Global root against what was transported
+		actualRoot, err := updater.GetCurrentGlobalTableRoot(callOpts)
+		if err != nil {
+			report("-", "global root", "readable", err.Error())
+			continue
+		}
+		if expected, ok := expectedRoots[chain.ChainID]; !ok {
+			report("-", "global root", "recorded root", hexutil.Encode(actualRoot[:]))
+		} else if expected != actualRoot {
+			report("-", "global root", hexutil.Encode(expected[:]), hexutil.Encode(actualRoot[:]))
+		}
+
+		// Recalculate the tables on L1 at the block the destination references
+		refTimestamp, err := updater.GetLatestReferenceTimestamp(callOpts)
+		if err != nil {
+			report("-", "reference timestamp", "readable", err.Error())
+			continue
+		}
+		refBlock, err := updater.GetLatestReferenceBlockNumber(callOpts)
+		if err != nil {
+			report("-", "reference block", "readable", err.Error())
+			continue
+		}
+		preview, ok := previews[uint64(refBlock)]
+		if !ok {
+			root, tree, dist, err := tableCalc.CalculateStakeTableRoot(cCtx.Context, uint64(refBlock))
+			if err != nil {
+				report("-", "recalculated root", fmt.Sprintf("tables at block %d", refBlock), err.Error())
+				continue
+			}
+			preview, err = buildStakeTablePreview(root, tree, dist)
+			if err != nil {
+				report("-", "recalculated root", fmt.Sprintf("tables at block %d", refBlock), err.Error())
+				continue
+			}
+			previews[uint64(refBlock)] = preview
+		}
+		if preview.GlobalRoot != hexutil.Encode(actualRoot[:]) {
+			report("-", "recalculated root", preview.GlobalRoot, hexutil.Encode(actualRoot[:]))
+		}
+
+		// Every operator set table held by the destination's certificate verifiers
+		for j := range preview.OperatorSets {
+			expected := &preview.OperatorSets[j]
+			opsetName := fmt.Sprintf("%s/%d", expected.Avs, expected.ID)
+			checkedOpsets++
+
+			actual, timestamp, err := readOnchainOperatorSetTable(cCtx, client, addresses[i], expected, refTimestamp)
+			if err != nil {
+				report(opsetName, "operator table", "readable", err.Error())
+				continue
+			}
+			if timestamp != refTimestamp {
+				report(opsetName, "reference timestamp", fmt.Sprintf("%d", refTimestamp), fmt.Sprintf("%d", timestamp))
+				continue
+			}
+			for _, diff := range compareOperatorSetTables(expected, actual) {
+				report(opsetName, diff[0], diff[1], diff[2])
+			}
+		}
+	}
+
+	if checkedChains == 0 {
+		return fmt.Errorf("no enabled context chains are supported by the cross-chain registry")
+	}
+	if len(discrepancies) > 0 {
+		if err := writeTransportDiscrepancies(cCtx.App.Writer, discrepancies); err != nil {
+			return err
+		}
+		return fmt.Errorf("found %d discrepancies between the expected and onchain stake tables", len(discrepancies))
+	}
+
+	logger.Info("Stake tables match onchain state on %d chains (%d operator set tables checked).", checkedChains, checkedOpsets)
+	return nil
+}
+
+// readOnchainOperatorSetTable reads an operator set's table from the destination certificate verifier for its curve
+func readOnchainOperatorSetTable(cCtx *cli.Context, client *chainManager.Chain, updaterAddress ethcommon.Address, expected *OperatorSetTablePreview, refTimestamp uint32) (*OperatorSetTablePreview, uint32, error) {
+	var curve uint8
+	switch expected.CurveType {
+	case "BN254":
+		curve = curveTypeBN254
+	case "ECDSA":
+		curve = curveTypeECDSA
+	default:
+		return nil, 0, fmt.Errorf("unsupported curve type %s", expected.CurveType)
+	}
+	opts := &bind.CallOpts{Context: cCtx.Context}
+	opset := abiOperatorSet{Avs: ethcommon.HexToAddress(expected.Avs), Id: expected.ID}
+
+	out, err := callView(client.RPCClient, updaterAddress, operatorTableUpdaterViewsABI, opts, "getCertificateVerifier", curve)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get certificate verifier: %w", err)
+	}
+	verifier := out[0].(ethcommon.Address)
+
+	out, err = callView(client.RPCClient, verifier, certificateVerifierViewsABI, opts, "latestReferenceTimestamp", opset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get latest reference timestamp: %w", err)
+	}
+	timestamp := out[0].(uint32)
+
+	actual := &OperatorSetTablePreview{Avs: expected.Avs, ID: expected.ID, CurveType: expected.CurveType}
+	if curve == curveTypeBN254 {
+		out, err = callView(client.RPCClient, verifier, certificateVerifierViewsABI, opts, "getOperatorSetInfo", opset, refTimestamp)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to get operator set info: %w", err)
+		}
+		info := *abi.ConvertType(out[0], new(abiBN254OperatorSetInfo)).(*abiBN254OperatorSetInfo)
+		actual.OperatorInfoTreeRoot = hexutil.Encode(info.OperatorInfoTreeRoot[:])
+		actual.NumOperators = info.NumOperators.Uint64()
+		actual.AggregatePubkey = formatG1(info.AggregatePubkey.X, info.AggregatePubkey.Y)
+		actual.TotalWeights = formatWeights(info.TotalWeights)
+		return actual, timestamp, nil
+	}
+
+	out, err = callView(client.RPCClient, verifier, certificateVerifierViewsABI, opts, "getOperatorInfos", opset, refTimestamp)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get operator infos: %w", err)
+	}
+	infos := *abi.ConvertType(out[0], new([]abiECDSAOperatorInfo)).(*[]abiECDSAOperatorInfo)
+	for k, op := range infos {
+		actual.Operators = append(actual.Operators, OperatorStakeTablePreview{
+			Index:   k,
+			Address: op.Pubkey.Hex(),
+			Weights: formatWeights(op.Weights),
+		})
+	}
+	actual.NumOperators = uint64(len(infos))
+	return actual, timestamp, nil
+}
+
+// compareOperatorSetTables returns a (check, expected, actual) triple for every field that differs
+func compareOperatorSetTables(expected, actual *OperatorSetTablePreview) [][3]string {
+	var diffs [][3]string
+	add := func(check, want, got string) {
+		if want != got {
+			diffs = append(diffs, [3]string{check, want, got})
+		}
+	}
+
+	add("operator count", fmt.Sprintf("%d", expected.NumOperators), fmt.Sprintf("%d", actual.NumOperators))
+	if expected.CurveType == "BN254" {
+		add("operator info root", expected.OperatorInfoTreeRoot, actual.OperatorInfoTreeRoot)
+		add("aggregate pubkey", expected.AggregatePubkey, actual.AggregatePubkey)
+		add("total weights", strings.Join(expected.TotalWeights, ","), strings.Join(actual.TotalWeights, ","))
+		return diffs
+	}
+
+	for k := 0; k < len(expected.Operators) || k < len(actual.Operators); k++ {
+		want, got := "-", "-"
+		if k < len(expected.Operators) {
+			want = fmt.Sprintf("%s [%s]", expected.Operators[k].Address, strings.Join(expected.Operators[k].Weights, ","))
+		}
+		if k < len(actual.Operators) {
+			got = fmt.Sprintf("%s [%s]", actual.Operators[k].Address, strings.Join(actual.Operators[k].Weights, ","))
+		}
+		add(fmt.Sprintf("operator %d", k), want, got)
+	}
+	return diffs
+}
+
+// writeTransportDiscrepancies prints every discrepancy as a table row
+func writeTransportDiscrepancies(out io.Writer, discrepancies []transportDiscrepancy) error {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "CHAIN\tCHAIN ID\tOPERATOR SET\tCHECK\tEXPECTED\tACTUAL")
+	for _, d := range discrepancies {
+		fmt.Fprintf(w, "%s\t%d\t%s\t%s\t%s\t%s\n", d.Chain, d.ChainID, d.OperatorSet, d.Check, d.Expected, d.Actual)
+	}
+	return w.Flush()
+}
+
+// callView calls a view method on address through a minimal ABI
+func callView(client *ethclient.Client, address ethcommon.Address, contractABI abi.ABI, opts *bind.CallOpts, method string, args ...interface{}) ([]interface{}, error) {
+	var out []interface{}
+	contract := bind.NewBoundContract(address, contractABI, client, nil, nil)
+	if err := contract.Call(opts, &out, method, args...); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
@@ -0,0 +1,59 @@
+package commands
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompareOperatorSetTables(t *testing.T) {
+	bn254 := &OperatorSetTablePreview{
+		CurveType:            "BN254",
+		NumOperators:         2,
+		OperatorInfoTreeRoot: "0x01",
+		AggregatePubkey:      "0xaa",
+		TotalWeights:         []string{"10"},
+	}
+	require.Empty(t, compareOperatorSetTables(bn254, bn254))
+
+	stale := *bn254
+	stale.OperatorInfoTreeRoot = "0x02"
+	stale.TotalWeights = []string{"9"}
+	diffs := compareOperatorSetTables(bn254, &stale)
+	require.Equal(t, [][3]string{
+		{"operator info root", "0x01", "0x02"},
+		{"total weights", "10", "9"},
+	}, diffs)
+
+	ecdsa := &OperatorSetTablePreview{
+		CurveType:    "ECDSA",
+		NumOperators: 1,
+		Operators:    []OperatorStakeTablePreview{{Address: "0xop1", Weights: []string{"5"}}},
+	}
+	grown := &OperatorSetTablePreview{
+		CurveType:    "ECDSA",
+		NumOperators: 2,
+		Operators: []OperatorStakeTablePreview{
+			{Address: "0xop1", Weights: []string{"5"}},
+			{Address: "0xop2", Weights: []string{"1"}},
+		},
+	}
+	diffs = compareOperatorSetTables(ecdsa, grown)
+	require.Equal(t, [][3]string{
+		{"operator count", "1", "2"},
+		{"operator 1", "-", "0xop2 [1]"},
+	}, diffs)
+}
+
+func TestWriteTransportDiscrepancies(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, writeTransportDiscrepancies(&buf, []transportDiscrepancy{
+		{Chain: "l1,l2", ChainID: 31337, OperatorSet: "-", Check: "global root", Expected: "0x01", Actual: "0x02"},
+		{Chain: "base", ChainID: 8453, OperatorSet: "0xavs/0", Check: "reference timestamp", Expected: "20", Actual: "10"},
+	}))
+	out := buf.String()
+	require.Contains(t, out, "OPERATOR SET")
+	require.Regexp(t, `l1,l2\s+31337\s+-\s+global root\s+0x01\s+0x02`, out)
+	require.Regexp(t, `base\s+8453\s+0xavs/0\s+reference timestamp\s+20\s+10`, out)
+}
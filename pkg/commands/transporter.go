@@ -1,26 +1,25 @@
 package commands
 
 import (
+	"context"
 	"fmt"
-	"log"
+	"io"
 	"math/big"
 	"strconv"
 	"time"
 
-	"github.com/Layr-Labs/crypto-libs/pkg/bn254"
 	"github.com/Layr-Labs/devkit-cli/pkg/common"
 	"github.com/Layr-Labs/devkit-cli/pkg/common/devnet"
+	"github.com/Layr-Labs/devkit-cli/pkg/common/iface"
+	"github.com/Layr-Labs/devkit-cli/pkg/common/signer"
 	"github.com/Layr-Labs/eigenlayer-contracts/pkg/bindings/ICrossChainRegistry"
-	"github.com/Layr-Labs/eigenlayer-contracts/pkg/bindings/IOperatorTableUpdater"
 	"github.com/urfave/cli/v2"
 	"gopkg.in/yaml.v3"
 
-	"github.com/Layr-Labs/multichain-go/pkg/blsSigner"
 	"github.com/Layr-Labs/multichain-go/pkg/chainManager"
 	"github.com/Layr-Labs/multichain-go/pkg/logger"
 	"github.com/Layr-Labs/multichain-go/pkg/operatorTableCalculator"
 	"github.com/Layr-Labs/multichain-go/pkg/transport"
-	"github.com/Layr-Labs/multichain-go/pkg/txSigner"
 
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	ethcommon "github.com/ethereum/go-ethereum/common"
@@ -30,6 +29,13 @@ import (
 	"github.com/robfig/cron/v3"
 )
 
+// transportContextFlag selects the context whose chains, EigenLayer addresses and transport log are used
+var transportContextFlag = &cli.StringFlag{
+	Name:  "context",
+	Usage: "Context to read the chains and EigenLayer addresses from",
+	Value: devnet.DEVNET_CONTEXT,
+}
+
 var TransportCommand = &cli.Command{
 	Name:  "transport",
 	Usage: "Transport Stake Root to L1",
@@ -37,34 +43,84 @@ var TransportCommand = &cli.Command{
 		{
 			Name:   "run",
 			Usage:  "Immediately transport stake root to L1",
-			Flags:  append([]cli.Flag{}, common.GlobalFlags...),
+			Flags:  append([]cli.Flag{transportContextFlag}, common.GlobalFlags...),
 			Action: Transport,
 		},
+		{
+			Name:  "preview",
+			Usage: "Show the stake tables that would be transported without signing or sending anything",
+			Flags: append([]cli.Flag{
+				transportContextFlag,
+				&cli.BoolFlag{
+					Name:  "json",
+					Usage: "Output the preview as JSON",
+				},
+				&cli.Uint64Flag{
+					Name:  "block",
+					Usage: "Reference block to calculate the stake tables at (defaults to the latest finalized block)",
+				},
+			}, common.GlobalFlags...),
+			Action: TransportPreview,
+		},
 		{
 			Name:   "verify",
-			Usage:  "Verify that the context active_stake_roots match onchain state",
-			Flags:  append([]cli.Flag{}, common.GlobalFlags...),
+			Usage:  "Verify the global root and every operator set table on each destination match onchain state",
+			Flags:  append([]cli.Flag{transportContextFlag}, common.GlobalFlags...),
 			Action: VerifyActiveStakeTableRoots,
 		},
 		{
 			Name:  "schedule",
-			Usage: "Schedule transport stake root to L1",
+			Usage: "Run a daemon that transports stake roots on a schedule and on stake changes",
 			Flags: append([]cli.Flag{
+				transportContextFlag,
 				&cli.StringFlag{
 					Name:  "cron-expr",
 					Usage: "Specify a custom schedule to override config schedule",
 					Value: "",
 				},
+				&cli.IntFlag{
+					Name:  "max-retries",
+					Usage: "Retries after a failed transport, with exponential backoff",
+					Value: DefaultTransportSchedulerOptions.MaxRetries,
+				},
+				&cli.DurationFlag{
+					Name:  "retry-backoff",
+					Usage: "Wait before the first retry, doubled for each further retry",
+					Value: DefaultTransportSchedulerOptions.RetryBackoff,
+				},
+				&cli.DurationFlag{
+					Name:  "max-retry-backoff",
+					Usage: "Upper bound for the wait between retries",
+					Value: DefaultTransportSchedulerOptions.MaxRetryBackoff,
+				},
+				&cli.DurationFlag{
+					Name:  "jitter",
+					Usage: "Random delay of up to this duration before each transport",
+				},
+				&cli.DurationFlag{
+					Name:  "shutdown-timeout",
+					Usage: "How long to wait for a running transport on shutdown before cancelling it",
+					Value: DefaultTransportSchedulerOptions.ShutdownTimeout,
+				},
+				&cli.StringFlag{
+					Name:  "health-addr",
+					Usage: "Serve /health (JSON) and /metrics (Prometheus) on this address, e.g. 127.0.0.1:9464",
+				},
+				&cli.DurationFlag{
+					Name:  "watch-stake",
+					Usage: "Check the stake table root at this interval and transport when it changes (e.g. 1m)",
+				},
 			}, common.GlobalFlags...),
 			Action: func(cCtx *cli.Context) error {
 				// Extract context
-				cfg, err := common.LoadConfigWithContextConfig(devnet.DEVNET_CONTEXT)
+				contextName := cCtx.String("context")
+				cfg, err := common.LoadConfigWithContextConfig(contextName)
 				if err != nil {
 					return fmt.Errorf("failed to load configurations for whitelist chain id in cross registry: %w", err)
 				}
-				envCtx, ok := cfg.Context[devnet.DEVNET_CONTEXT]
+				envCtx, ok := cfg.Context[contextName]
 				if !ok {
-					return fmt.Errorf("context '%s' not found in configuration", devnet.DEVNET_CONTEXT)
+					return fmt.Errorf("context '%s' not found in configuration", contextName)
 				}
 
 				// Extract cron-expr from flag or context
@@ -73,20 +129,31 @@ var TransportCommand = &cli.Command{
 					schedule = envCtx.Transporter.Schedule
 				}
 
-				// Invoke ScheduleTransport with configured schedule
-				err = ScheduleTransport(cCtx, schedule)
+				// Run the daemon until interrupted
+				err = ScheduleTransportWithOptions(cCtx, contextName, schedule, TransportSchedulerOptions{
+					MaxRetries:      cCtx.Int("max-retries"),
+					RetryBackoff:    cCtx.Duration("retry-backoff"),
+					MaxRetryBackoff: cCtx.Duration("max-retry-backoff"),
+					Jitter:          cCtx.Duration("jitter"),
+					ShutdownTimeout: cCtx.Duration("shutdown-timeout"),
+					HealthAddr:      cCtx.String("health-addr"),
+					WatchInterval:   cCtx.Duration("watch-stake"),
+				})
 				if err != nil {
 					return fmt.Errorf("ScheduleTransport failed: %v", err)
 				}
-
-				// Keep process alive
-				select {}
+				return nil
 			},
 		},
 	},
 }
 
 func Transport(cCtx *cli.Context) error {
+	return runTransport(cCtx.Context, cCtx.App.Writer, cCtx.String("context"))
+}
+
+// runTransport calculates the stake table root on the context's L1 and transports it to every destination, writing a summary to out
+func runTransport(ctx context.Context, out io.Writer, contextName string) error {
 	// Get a raw zap logger to pass to operatorTableCalculator and transport
 	rawLogger, err := logger.NewLogger(&logger.LoggerConfig{Debug: true})
 	if err != nil {
@@ -94,89 +161,68 @@ func Transport(cCtx *cli.Context) error {
 	}
 
 	// Get logger
-	logger := common.LoggerFromContext(cCtx.Context)
+	logger := common.LoggerFromContext(ctx)
 
 	// Construct and collate all roots
 	roots := make(map[uint64][32]byte)
 
 	// Extract context
-	cfg, err := common.LoadConfigWithContextConfig(devnet.DEVNET_CONTEXT)
+	cfg, err := common.LoadConfigWithContextConfig(contextName)
 	if err != nil {
 		return fmt.Errorf("failed to load configurations for whitelist chain id in cross registry: %w", err)
 	}
-	envCtx, ok := cfg.Context[devnet.DEVNET_CONTEXT]
+	envCtx, ok := cfg.Context[contextName]
 	if !ok {
-		return fmt.Errorf("context '%s' not found in configuration", devnet.DEVNET_CONTEXT)
+		return fmt.Errorf("context '%s' not found in configuration", contextName)
 	}
 	// Get the values from env/config
 	crossChainRegistryAddress := ethcommon.HexToAddress(envCtx.EigenLayer.L1.CrossChainRegistry)
-	rpcUrl, err := devnet.GetDevnetRPCUrlDefault(cfg, devnet.L1)
-	if err != nil {
-		rpcUrl = "http://localhost:8545"
-	}
-	chainId, err := devnet.GetDevnetChainIdOrDefault(cfg, devnet.L1, logger)
-	if err != nil {
-		chainId = common.DefaultAnvilChainId
-	}
-
-	cm := chainManager.NewChainManager()
 
-	holeskyConfig := &chainManager.ChainConfig{
-		ChainID: uint64(chainId),
-		RPCUrl:  rpcUrl,
-	}
-	if err := cm.AddChain(holeskyConfig); err != nil {
-		return fmt.Errorf("Failed to add chain: %v", err)
-	}
-	holeskyClient, err := cm.GetChainForId(holeskyConfig.ChainID)
+	// Register every context chain so the transport can reach each destination
+	chains, cm, l1Client, err := loadTransportChains(cfg, contextName, logger)
 	if err != nil {
-		return fmt.Errorf("Failed to get chain for ID %d: %v", holeskyConfig.ChainID, err)
+		return err
 	}
+	defer closeTransportChains(cm, chains)
 
-	txSign, err := txSigner.NewPrivateKeySigner(envCtx.Transporter.PrivateKey)
+	txSign, err := envCtx.Transporter.TxSigner(ctx)
 	if err != nil {
-		return fmt.Errorf("Failed to create private key signer: %v", err)
+		return fmt.Errorf("Failed to create transporter signer: %v", err)
 	}
+	defer txSign.Close()
 
 	tableCalc, err := operatorTableCalculator.NewStakeTableRootCalculator(&operatorTableCalculator.Config{
 		CrossChainRegistryAddress: crossChainRegistryAddress,
-	}, holeskyClient.RPCClient, rawLogger)
+	}, l1Client.RPCClient, rawLogger)
 	if err != nil {
 		return fmt.Errorf("Failed to create StakeTableRootCalculator: %v", err)
 	}
 
-	block, err := holeskyClient.RPCClient.BlockByNumber(cCtx.Context, big.NewInt(int64(rpc.FinalizedBlockNumber)))
+	block, err := l1Client.RPCClient.BlockByNumber(ctx, big.NewInt(int64(rpc.FinalizedBlockNumber)))
 	if err != nil {
 		return fmt.Errorf("Failed to get block by number: %v", err)
 	}
 
-	root, tree, dist, err := tableCalc.CalculateStakeTableRoot(cCtx.Context, block.NumberU64())
+	root, tree, dist, err := tableCalc.CalculateStakeTableRoot(ctx, block.NumberU64())
 	if err != nil {
 		return fmt.Errorf("Failed to calculate stake table root: %v", err)
 	}
 
-	scheme := bn254.NewScheme()
-	genericPk, err := scheme.NewPrivateKeyFromHexString(envCtx.Transporter.BlsPrivateKey)
+	blsSign, err := envCtx.Transporter.BLSSigner()
 	if err != nil {
-		return fmt.Errorf("Failed to create BLS private key: %v", err)
-	}
-	pk, err := bn254.NewPrivateKeyFromBytes(genericPk.Bytes())
-	if err != nil {
-		return fmt.Errorf("Failed to convert BLS private key: %v", err)
+		return fmt.Errorf("Failed to create transporter BLS signer: %v", err)
 	}
 
-	inMemSigner, err := blsSigner.NewInMemoryBLSSigner(pk)
-	if err != nil {
-		return fmt.Errorf("Failed to create in-memory BLS signer: %v", err)
-	}
+	// Record the hash of every transaction the transport signs for the transport log
+	txRecorder := &recordingTxSigner{MultichainTxSigner: signer.MultichainTxSigner{Signer: txSign}}
 
 	stakeTransport, err := transport.NewTransport(
 		&transport.TransportConfig{
 			L1CrossChainRegistryAddress: crossChainRegistryAddress,
 		},
-		holeskyClient.RPCClient,
-		inMemSigner,
-		txSign,
+		l1Client.RPCClient,
+		&signer.MultichainBLSSigner{Ctx: ctx, Signer: blsSign},
+		txRecorder,
 		cm,
 		rawLogger,
 	)
@@ -184,61 +230,203 @@ func Transport(cCtx *cli.Context) error {
 		return fmt.Errorf("Failed to create transport: %v", err)
 	}
 
+	// Match the registry's destinations against the context chains
+	ccRegistryCaller, err := ICrossChainRegistry.NewICrossChainRegistryCaller(crossChainRegistryAddress, l1Client.RPCClient)
+	if err != nil {
+		return fmt.Errorf("Failed to get CrossChainRegistryCaller for %s: %v", crossChainRegistryAddress, err)
+	}
+	supported, _, err := ccRegistryCaller.GetSupportedChains(&bind.CallOpts{Context: ctx})
+	if err != nil {
+		return fmt.Errorf("failed to get supported chains: %w", err)
+	}
+	if len(supported) == 0 {
+		return fmt.Errorf("no supported chains found in cross-chain registry")
+	}
+	destinations := resolveTransportDestinations(supported, chains)
+
 	referenceTimestamp := uint32(block.Time())
 
-	err = stakeTransport.SignAndTransportGlobalTableRoot(
-		root,
-		referenceTimestamp,
-		block.NumberU64(),
-		[]*big.Int{new(big.Int).SetUint64(17000)},
-	)
-	if err != nil {
-		return fmt.Errorf("Failed to sign and transport global table root: %v", err)
+	// Append the outcome of this run to the transport log however it ends
+	logEntry := TransportLogEntry{
+		Time:               time.Now().UTC(),
+		ReferenceBlock:     block.NumberU64(),
+		ReferenceTimestamp: referenceTimestamp,
+		GlobalRoot:         hexutil.Encode(root[:]),
 	}
+	defer func() {
+		for _, dest := range destinations {
+			logEntry.Chains = append(logEntry.Chains, dest.logEntry())
+		}
+		if err := appendTransportLog(transportLogPath(contextName), logEntry); err != nil {
+			logger.Warn("Failed to append to transport log: %v", err)
+		}
+	}()
 
-	// Collect the provided roots
-	roots[holeskyConfig.ChainID] = root
+	// Transport the global root to each destination independently so one failing chain does not block the others
+	for _, dest := range destinations {
+		if dest.Status == transportStatusSkipped {
+			logger.Info("Skipping transport to chain %d: %s", dest.ChainID, dest.Reason)
+			continue
+		}
+		txRecorder.take()
+		err = stakeTransport.SignAndTransportGlobalTableRoot(
+			root,
+			referenceTimestamp,
+			block.NumberU64(),
+			ignoreAllExcept(supported, dest.ChainID),
+		)
+		dest.GlobalRootTxs = txRecorder.take()
+		if err != nil {
+			dest.Status = transportStatusFailed
+			dest.Reason = fmt.Sprintf("global table root: %v", err)
+			logger.Error("Failed to sign and transport global table root to chain %d: %v", dest.ChainID, err)
+			continue
+		}
+		dest.Status = transportStatusTransported
+
+		// Collect the provided roots
+		roots[dest.ChainID] = root
+	}
 
 	// Write the roots to context (each time we process one)
-	err = WriteStakeTableRootsToContext(roots)
-	if err != nil {
-		return fmt.Errorf("failed to write active_stake_roots: %w", err)
+	if len(roots) > 0 {
+		err = WriteStakeTableRootsToContext(contextName, roots)
+		if err != nil {
+			return fmt.Errorf("failed to write active_stake_roots: %w", err)
+		}
+	}
+	if len(roots) == 0 {
+		_ = writeTransportResults(out, destinations)
+		return fmt.Errorf("global table root was not transported to any destination")
 	}
 
 	// Sleep before transporting AVSStakeTable
 	logger.Info("Successfully signed and transported global table root, sleeping for 25 seconds")
-	time.Sleep(25 * time.Second)
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(25 * time.Second):
+	}
 
 	// Fetch OperatorSets for AVSStakeTable transport
-	opsets := dist.GetOperatorSets()
+	opsets := dist.GetOrderedOperatorSets()
 	if len(opsets) == 0 {
 		return fmt.Errorf("No operator sets found, skipping AVS stake table transport")
 	}
-	for _, opset := range opsets {
-		err = stakeTransport.SignAndTransportAvsStakeTable(
-			referenceTimestamp,
-			block.NumberU64(),
-			opset,
-			root,
-			tree,
-			dist,
-			[]*big.Int{new(big.Int).SetUint64(17000)},
-		)
-		if err != nil {
-			return fmt.Errorf("Failed to sign and transport AVS stake table for opset %v: %v", opset, err)
+	for _, dest := range destinations {
+		if dest.Status != transportStatusTransported {
+			continue
+		}
+		for _, opset := range opsets {
+			result := TransportLogOperatorSet{Avs: opset.Avs.Hex(), ID: opset.Id, Status: transportStatusTransported}
+			txRecorder.take()
+			err = stakeTransport.SignAndTransportAvsStakeTable(
+				referenceTimestamp,
+				block.NumberU64(),
+				opset,
+				root,
+				tree,
+				dist,
+				ignoreAllExcept(supported, dest.ChainID),
+			)
+			result.Txs = txRecorder.take()
+			if err != nil {
+				result.Status = transportStatusFailed
+				result.Error = err.Error()
+				dest.Status = transportStatusFailed
+				logger.Error("Failed to sign and transport AVS stake table for opset %v to chain %d: %v", opset, dest.ChainID, err)
+			} else {
+				// log success
+				logger.Info("Successfully signed and transported AVS stake table for opset %v to chain %d", opset, dest.ChainID)
+			}
+			dest.OperatorSets = append(dest.OperatorSets, result)
 		}
+	}
 
-		// log success
-		logger.Info("Successfully signed and transported AVS stake table for opset %v", opset)
+	// Report the outcome for every destination
+	if err := writeTransportResults(out, destinations); err != nil {
+		return fmt.Errorf("failed to write transport results: %w", err)
+	}
+	failed := 0
+	for _, dest := range destinations {
+		if dest.Status == transportStatusFailed {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("transport failed for %d of %d destinations", failed, len(destinations))
 	}
 
 	return nil
 }
 
-// Record StakeTableRoots in the context for later retrieval
-func WriteStakeTableRootsToContext(roots map[uint64][32]byte) error {
+// loadTransportChains registers the chains of the named context in a chainManager and returns the L1 chain.
+// On devnet the L1 falls back to the default devnet RPC and chain ID, other contexts must define it.
+// The connections stay open until closeTransportChains is called.
+func loadTransportChains(cfg *common.ConfigWithContextConfig, contextName string, logger iface.Logger) (map[uint64]*transportChain, *chainManager.ChainManager, *chainManager.Chain, error) {
+	envCtx, ok := cfg.Context[contextName]
+	if !ok {
+		return nil, nil, nil, fmt.Errorf("context '%s' not found in configuration", contextName)
+	}
+
+	var rpcUrl string
+	var chainId int
+	if contextName == devnet.DEVNET_CONTEXT {
+		var err error
+		rpcUrl, err = devnet.GetDevnetRPCUrlDefault(cfg, devnet.L1)
+		if err != nil {
+			rpcUrl = "http://localhost:8545"
+		}
+		chainId, err = devnet.GetDevnetChainIdOrDefault(cfg, devnet.L1, logger)
+		if err != nil {
+			chainId = common.DefaultAnvilChainId
+		}
+	} else {
+		l1, ok := envCtx.Chains[devnet.L1]
+		if !ok || l1.RPCURL == "" || l1.ChainID == 0 {
+			return nil, nil, nil, fmt.Errorf("chain '%s' with rpc_url and chain_id is required in context '%s'", devnet.L1, contextName)
+		}
+		rpcUrl, chainId = l1.RPCURL, l1.ChainID
+	}
+
+	chains, err := transportChainsFromContext(envCtx.Chains)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if _, ok := chains[uint64(chainId)]; !ok {
+		chains[uint64(chainId)] = &transportChain{
+			Names:   []string{devnet.L1},
+			ChainID: uint64(chainId),
+			RPCURL:  rpcUrl,
+			Enabled: true,
+		}
+	}
+
+	cm, err := newTransportChainManager(chains)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	l1Client, err := cm.GetChainForId(uint64(chainId))
+	if err != nil {
+		closeTransportChains(cm, chains)
+		return nil, nil, nil, fmt.Errorf("Failed to get chain for ID %d: %v", chainId, err)
+	}
+	return chains, cm, l1Client, nil
+}
+
+// closeTransportChains closes the RPC connection of every chain registered by loadTransportChains
+func closeTransportChains(cm *chainManager.ChainManager, chains map[uint64]*transportChain) {
+	for id := range chains {
+		if chain, err := cm.GetChainForId(id); err == nil {
+			chain.RPCClient.Close()
+		}
+	}
+}
+
+// Record StakeTableRoots in the named context for later retrieval
+func WriteStakeTableRootsToContext(contextName string, roots map[uint64][32]byte) error {
 	// Load and navigate context to arrive at context.transporter.active_stake_roots
-	yamlPath, rootNode, contextNode, err := common.LoadContext("devnet") // @TODO: use selected context name
+	yamlPath, rootNode, contextNode, err := common.LoadContext(contextName)
 	if err != nil {
 		return err
 	}
@@ -322,7 +510,7 @@ func WriteStakeTableRootsToContext(roots map[uint64][32]byte) error {
 	}
 
 	// Write the context back to disk
-	err = common.WriteYAML(yamlPath, rootNode)
+	err = common.WriteContext(yamlPath, rootNode)
 	if err != nil {
 		return fmt.Errorf("failed to write updated context to disk: %w", err)
 	}
@@ -330,124 +518,29 @@ func WriteStakeTableRootsToContext(roots map[uint64][32]byte) error {
 	return nil
 }
 
-// Get all stake table roots from appropriate OperatorTableUpdaters
-func GetOnchainStakeTableRoots(cCtx *cli.Context) (map[uint64][32]byte, error) {
-	// Get logger
-	logger := common.LoggerFromContext(cCtx.Context)
-
-	// Discover and collate all roots
-	roots := make(map[uint64][32]byte)
-
-	// Extract context
-	cfg, err := common.LoadConfigWithContextConfig(devnet.DEVNET_CONTEXT)
-	if err != nil {
-		return nil, fmt.Errorf("failed to load configurations for whitelist chain id in cross registry: %w", err)
-	}
-	envCtx, ok := cfg.Context[devnet.DEVNET_CONTEXT]
-	if !ok {
-		return nil, fmt.Errorf("context '%s' not found in configuration", devnet.DEVNET_CONTEXT)
-	}
-
-	// Get the values from env/config
-	crossChainRegistryAddress := ethcommon.HexToAddress(envCtx.EigenLayer.L1.CrossChainRegistry)
-	rpcUrl, err := devnet.GetDevnetRPCUrlDefault(cfg, devnet.L1)
-	if err != nil {
-		rpcUrl = "http://localhost:8545"
-	}
-	chainId, err := devnet.GetDevnetChainIdOrDefault(cfg, devnet.L1, logger)
-	if err != nil {
-		chainId = common.DefaultAnvilChainId
-	}
-
-	// Get a new chainManager
-	cm := chainManager.NewChainManager()
-
-	// Configure L1 chain
-	holeskyConfig := &chainManager.ChainConfig{
-		ChainID: uint64(chainId),
-		RPCUrl:  rpcUrl,
-	}
-	if err := cm.AddChain(holeskyConfig); err != nil {
-		return nil, fmt.Errorf("Failed to add chain: %v", err)
-	}
-	holeskyClient, err := cm.GetChainForId(holeskyConfig.ChainID)
-	if err != nil {
-		return nil, fmt.Errorf("Failed to get chain for ID %d: %v", holeskyConfig.ChainID, err)
-	}
-
-	// Construct registry caller
-	ccRegistryCaller, err := ICrossChainRegistry.NewICrossChainRegistryCaller(crossChainRegistryAddress, holeskyClient.RPCClient)
-	if err != nil {
-		return nil, fmt.Errorf("Failed to get CrossChainRegistryCaller for %s: %v", crossChainRegistryAddress, err)
-	}
-
-	// Get chains from contract
-	chainIds, addresses, err := ccRegistryCaller.GetSupportedChains(&bind.CallOpts{})
-	if err != nil {
-		return nil, fmt.Errorf("failed to get supported chains: %w", err)
-	}
-	if len(chainIds) == 0 {
-		return nil, fmt.Errorf("no supported chains found in cross-chain registry")
-	}
-
-	// Iterate and collect all roots for all chainIds
-	for i, chainId := range chainIds {
-		// Ignore 17000 from chainIds
-		if chainId.Uint64() == 17000 {
-			continue
-		}
-
-		// Use provided OperatorTableUpdaterTransactor address
-		addr := addresses[i]
-		chain, err := cm.GetChainForId(chainId.Uint64())
-		if err != nil {
-			return nil, fmt.Errorf("failed to get chain for ID %d: %w", chainId, err)
-		}
-
-		// Get the OperatorTableUpdaterTransactor at the provided chains address
-		transactor, err := IOperatorTableUpdater.NewIOperatorTableUpdater(addr, chain.RPCClient)
-		if err != nil {
-			return nil, fmt.Errorf("failed to bind NewIOperatorTableUpdaterTransactor: %w", err)
-		}
-
-		// Collect the current root from provided chainId
-		root, err := transactor.GetCurrentGlobalTableRoot(&bind.CallOpts{})
-		if err != nil {
-			return nil, fmt.Errorf("failed to get stake root: %w", err)
-		}
-
-		// Collect the provided root
-		roots[chainId.Uint64()] = root
-	}
-
-	return roots, nil
-}
-
-// Verify the context stored ActiveStakeRoots match onchain state
-func VerifyActiveStakeTableRoots(cCtx *cli.Context) error {
-	// Get logger
-	logger := common.LoggerFromContext(cCtx.Context)
-
-	// Read expected roots from context
-	_, _, contextNode, err := common.LoadContext("devnet") // @TODO: make dynamic
+// Read the context stored ActiveStakeRoots keyed by chain ID
+func readActiveStakeRoots(contextName string) (map[uint64][32]byte, error) {
+	_, _, contextNode, err := common.LoadContext(contextName)
 	if err != nil {
-		return fmt.Errorf("failed to load context YAML: %w", err)
+		return nil, fmt.Errorf("failed to load context YAML: %w", err)
 	}
 
+	expectedMap := make(map[uint64][32]byte)
 	transporterNode := common.GetChildByKey(contextNode, "transporter")
 	if transporterNode == nil {
-		return fmt.Errorf("missing 'transporter' section in context")
+		return nil, fmt.Errorf("missing 'transporter' section in context")
 	}
-
 	activeRootsNode := common.GetChildByKey(transporterNode, "active_stake_roots")
-	if activeRootsNode == nil || activeRootsNode.Kind != yaml.SequenceNode {
-		return fmt.Errorf("'active_stake_roots' is missing or not a list")
+	if activeRootsNode == nil {
+		return expectedMap, nil
+	}
+	if activeRootsNode.Kind != yaml.SequenceNode {
+		return nil, fmt.Errorf("'active_stake_roots' is not a list")
 	}
 
-	expectedMap := make(map[uint64][32]byte)
 	for _, entry := range activeRootsNode.Content {
 		if entry.Kind != yaml.MappingNode {
-			return fmt.Errorf("malformed entry in 'active_stake_roots'; expected map")
+			return nil, fmt.Errorf("malformed entry in 'active_stake_roots'; expected map")
 		}
 
 		var chainID uint64
@@ -462,17 +555,17 @@ func VerifyActiveStakeTableRoots(cCtx *cli.Context) error {
 			case "chain_id":
 				cid, err := strconv.ParseUint(val, 10, 64)
 				if err != nil {
-					return fmt.Errorf("invalid chain_id: %w", err)
+					return nil, fmt.Errorf("invalid chain_id: %w", err)
 				}
 				chainID = cid
 				foundCID = true
 			case "stake_root":
 				b, err := hexutil.Decode(val)
 				if err != nil {
-					return fmt.Errorf("invalid stake_root hex: %w", err)
+					return nil, fmt.Errorf("invalid stake_root hex: %w", err)
 				}
 				if len(b) != 32 {
-					return fmt.Errorf("stake_root must be 32 bytes, got %d", len(b))
+					return nil, fmt.Errorf("stake_root must be 32 bytes, got %d", len(b))
 				}
 				copy(rootBytes[:], b)
 				foundRoot = true
@@ -480,72 +573,63 @@ func VerifyActiveStakeTableRoots(cCtx *cli.Context) error {
 		}
 
 		if !foundCID || !foundRoot {
-			return fmt.Errorf("entry missing required fields 'chain_id' or 'stake_root'")
+			return nil, fmt.Errorf("entry missing required fields 'chain_id' or 'stake_root'")
 		}
 
 		expectedMap[chainID] = rootBytes
 	}
+	return expectedMap, nil
+}
+
+// Schedule transport for the named context using the default parser, options and transportFunc
+func ScheduleTransport(cCtx *cli.Context, contextName string, cronExpr string) error {
+	return ScheduleTransportWithOptions(cCtx, contextName, cronExpr, DefaultTransportSchedulerOptions)
+}
 
-	// Fetch actual roots
-	actualMap, err := GetOnchainStakeTableRoots(cCtx)
+// Schedule transport for the named context using the default parser and the given daemon options
+func ScheduleTransportWithOptions(cCtx *cli.Context, contextName string, cronExpr string, opts TransportSchedulerOptions) error {
+	logger := common.LoggerFromContext(cCtx.Context)
+	parser := cron.NewParser(cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow)
+	out := cCtx.App.Writer
+
+	scheduler, err := NewTransportScheduler(cronExpr, parser, func(ctx context.Context) error {
+		return runTransport(ctx, out, contextName)
+	}, opts, logger)
 	if err != nil {
-		return fmt.Errorf("failed to get onchain roots: %w", err)
+		return err
 	}
 
-	// Compare expectations to actual (use actual as map source to allow user to move chainId if req)
-	for id, actual := range actualMap {
-		expected, ok := expectedMap[id]
-		if !ok {
-			return fmt.Errorf("missing onchain root for chainId %d", id)
+	// Seed the stake watcher with the last transported root so a change made while stopped triggers a run
+	if opts.WatchInterval > 0 {
+		probe, closeProbe, err := newStakeRootProbe(cCtx.Context, contextName)
+		if err != nil {
+			return err
 		}
-		if expected != actual {
-			return fmt.Errorf("root mismatch for chainId %d:\nexpected: %x\ngot:      %x", id, expected, actual)
+		defer closeProbe()
+
+		var initial [32]byte
+		if entries, err := readTransportLog(transportLogPath(contextName)); err == nil && len(entries) > 0 {
+			if b, err := hexutil.Decode(entries[len(entries)-1].GlobalRoot); err == nil && len(b) == 32 {
+				initial = [32]byte(b)
+			}
 		}
+		scheduler.WatchStake(probe, initial)
 	}
 
-	logger.Info("Root matches onchain state.")
-	return nil
-}
-
-// Schedule transport using the default parser and transportFunc
-func ScheduleTransport(cCtx *cli.Context, cronExpr string) error {
-	// Validate cron expression
-	parser := cron.NewParser(cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow)
-
-	// Run the scheduler with transport func
-	return ScheduleTransportWithParserAndFunc(cCtx, cronExpr, parser, func() {
-		if err := Transport(cCtx); err != nil {
-			log.Printf("Scheduled transport failed: %v", err)
-		}
-	})
+	// Stop on SIGINT/SIGTERM, letting a transport in progress finish within the shutdown timeout
+	return scheduler.Run(common.WithShutdown(cCtx.Context))
 }
 
 // Schedule transport using custom parser and transportFunc
 func ScheduleTransportWithParserAndFunc(cCtx *cli.Context, cronExpr string, parser cron.Parser, transportFunc func()) error {
-	// Validate cron expression
-	c := cron.New(cron.WithParser(parser))
-	_, err := parser.Parse(cronExpr)
-	if err != nil {
-		return fmt.Errorf("invalid cron expression: %w", err)
-	}
-
-	// Call Transport() against cronExpr
-	_, err = c.AddFunc(cronExpr, transportFunc)
+	opts := DefaultTransportSchedulerOptions
+	opts.MaxRetries = 0
+	scheduler, err := NewTransportScheduler(cronExpr, parser, func(context.Context) error {
+		transportFunc()
+		return nil
+	}, opts, common.LoggerFromContext(cCtx.Context))
 	if err != nil {
-		return fmt.Errorf("failed to add transport function to scheduler: %w", err)
-	}
-
-	// Start the scheduled runner
-	c.Start()
-	log.Println("Transport scheduler started.")
-	entries := c.Entries()
-	if len(entries) > 0 {
-		log.Printf("Next scheduled transport at: %s", entries[0].Next.Format(time.RFC3339))
+		return err
 	}
-
-	// If the Context closes, stop the scheduler
-	<-cCtx.Context.Done()
-	c.Stop()
-	log.Println("Transport scheduler stopped.")
-	return nil
+	return scheduler.Run(cCtx.Context)
 }
@@ -5,6 +5,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/Layr-Labs/devkit-cli/pkg/common/devnet"
 	"github.com/robfig/cron/v3"
 	"github.com/stretchr/testify/require"
 	"github.com/urfave/cli/v2"
@@ -16,7 +17,7 @@ func TestScheduleTransport_InvalidCron(t *testing.T) {
 	cCtx.Context = context.Background()
 
 	// attempt to schedule with invalid cron expression
-	err := ScheduleTransport(cCtx, "invalid-cron")
+	err := ScheduleTransport(cCtx, devnet.DEVNET_CONTEXT, "invalid-cron")
 	require.Error(t, err)
 	require.Contains(t, err.Error(), "invalid cron expression")
 }
@@ -0,0 +1,171 @@
+package common
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// Transaction Builder version stamped into Safe batch files
+const safeTxBuilderVersion = "1.16.5"
+
+// PreparedCall is a contract call that was built but not sent, for proposing through a multisig
+type PreparedCall struct {
+	Description string         `json:"description"`
+	ChainID     string         `json:"chainId"`
+	To          common.Address `json:"to"`
+	Value       string         `json:"value"`
+	Data        string         `json:"data"`
+}
+
+// CallRecorder collects the calls of privileged operations instead of signing and sending them
+type CallRecorder struct {
+	mu    sync.Mutex
+	from  common.Address
+	calls []PreparedCall
+}
+
+// NewCallRecorder creates a recorder, from is the account (usually a Safe) that will execute the calls
+func NewCallRecorder(from common.Address) *CallRecorder {
+	return &CallRecorder{from: from}
+}
+
+// From returns the account the recorded calls will be executed from
+func (r *CallRecorder) From() common.Address {
+	return r.from
+}
+
+// Record appends a call to the recorder
+func (r *CallRecorder) Record(description string, chainID *big.Int, to common.Address, value *big.Int, data []byte) {
+	if value == nil {
+		value = new(big.Int)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls = append(r.calls, PreparedCall{
+		Description: description,
+		ChainID:     chainID.String(),
+		To:          to,
+		Value:       value.String(),
+		Data:        hexutil.Encode(data),
+	})
+}
+
+// Calls returns the recorded calls in the order they were made
+func (r *CallRecorder) Calls() []PreparedCall {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]PreparedCall(nil), r.calls...)
+}
+
+// WriteCalldata writes the recorded calls as an indented JSON array
+func (r *CallRecorder) WriteCalldata(w io.Writer) error {
+	calls := r.Calls()
+	if calls == nil {
+		calls = []PreparedCall{}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(calls)
+}
+
+// SafeBatch is the batch file format imported by the Safe{Wallet} Transaction Builder
+type SafeBatch struct {
+	Version      string        `json:"version"`
+	ChainID      string        `json:"chainId"`
+	CreatedAt    int64         `json:"createdAt"`
+	Meta         SafeBatchMeta `json:"meta"`
+	Transactions []SafeBatchTx `json:"transactions"`
+}
+
+// SafeBatchMeta describes a Safe batch file
+type SafeBatchMeta struct {
+	Name                    string `json:"name"`
+	Description             string `json:"description"`
+	TxBuilderVersion        string `json:"txBuilderVersion"`
+	CreatedFromSafeAddress  string `json:"createdFromSafeAddress"`
+	CreatedFromOwnerAddress string `json:"createdFromOwnerAddress"`
+}
+
+// SafeBatchTx is a single call in a Safe batch file
+type SafeBatchTx struct {
+	To                   string            `json:"to"`
+	Value                string            `json:"value"`
+	Data                 string            `json:"data"`
+	ContractMethod       interface{}       `json:"contractMethod"`
+	ContractInputsValues map[string]string `json:"contractInputsValues"`
+}
+
+// SafeBatch converts the recorded calls into a Safe Transaction Builder batch, all calls must target one chain
+func (r *CallRecorder) SafeBatch(name string) (*SafeBatch, error) {
+	calls := r.Calls()
+	if len(calls) == 0 {
+		return nil, fmt.Errorf("no calls were recorded")
+	}
+
+	batch := &SafeBatch{
+		Version:   "1.0",
+		ChainID:   calls[0].ChainID,
+		CreatedAt: time.Now().UnixMilli(),
+		Meta: SafeBatchMeta{
+			Name:             name,
+			TxBuilderVersion: safeTxBuilderVersion,
+		},
+	}
+	if r.from != (common.Address{}) {
+		batch.Meta.CreatedFromSafeAddress = r.from.Hex()
+	}
+
+	descriptions := make([]string, 0, len(calls))
+	for _, call := range calls {
+		if call.ChainID != batch.ChainID {
+			return nil, fmt.Errorf("recorded calls target multiple chains (%s and %s), a Safe batch must target one chain", batch.ChainID, call.ChainID)
+		}
+		descriptions = append(descriptions, call.Description)
+		batch.Transactions = append(batch.Transactions, SafeBatchTx{
+			To:    call.To.Hex(),
+			Value: call.Value,
+			Data:  call.Data,
+		})
+	}
+	batch.Meta.Description = strings.Join(descriptions, "; ")
+	return batch, nil
+}
+
+// WriteSafeBatch writes the recorded calls to path as a Safe Transaction Builder batch
+func (r *CallRecorder) WriteSafeBatch(path, name string) error {
+	batch, err := r.SafeBatch(name)
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(batch, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal Safe batch: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write Safe batch: %w", err)
+	}
+	return nil
+}
+
+type callRecorderContextKey struct{}
+
+// WithCallRecorder stores the call recorder in the context
+func WithCallRecorder(ctx context.Context, recorder *CallRecorder) context.Context {
+	return context.WithValue(ctx, callRecorderContextKey{}, recorder)
+}
+
+// CallRecorderFromContext retrieves the call recorder from the context, or nil when calls should be sent
+func CallRecorderFromContext(ctx context.Context) *CallRecorder {
+	recorder, _ := ctx.Value(callRecorderContextKey{}).(*CallRecorder)
+	return recorder
+}
@@ -0,0 +1,100 @@
+package common
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Layr-Labs/devkit-cli/pkg/common/logger"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCallRecorderCalldata(t *testing.T) {
+	recorder := NewCallRecorder(common.Address{})
+	to := common.HexToAddress("0x1000000000000000000000000000000000000001")
+	recorder.Record("UpdateAVSMetadataURI", big.NewInt(17000), to, nil, []byte{0xde, 0xad})
+
+	var buf bytes.Buffer
+	require.NoError(t, recorder.WriteCalldata(&buf))
+
+	var calls []PreparedCall
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &calls))
+	require.Len(t, calls, 1)
+	require.Equal(t, "UpdateAVSMetadataURI", calls[0].Description)
+	require.Equal(t, "17000", calls[0].ChainID)
+	require.Equal(t, to, calls[0].To)
+	require.Equal(t, "0", calls[0].Value)
+	require.Equal(t, "0xdead", calls[0].Data)
+}
+
+func TestCallRecorderSafeBatch(t *testing.T) {
+	safe := common.HexToAddress("0x2000000000000000000000000000000000000002")
+	recorder := NewCallRecorder(safe)
+
+	_, err := recorder.SafeBatch("empty")
+	require.Error(t, err)
+
+	to := common.HexToAddress("0x1000000000000000000000000000000000000001")
+	recorder.Record("SetAVSRegistrar", big.NewInt(1), to, big.NewInt(0), []byte{0x01})
+	recorder.Record("CreateOperatorSets", big.NewInt(1), to, big.NewInt(5), []byte{0x02})
+
+	path := filepath.Join(t.TempDir(), "batch.json")
+	require.NoError(t, recorder.WriteSafeBatch(path, "AVS setup"))
+
+	raw, err := os.ReadFile(path)
+	require.NoError(t, err)
+	var batch SafeBatch
+	require.NoError(t, json.Unmarshal(raw, &batch))
+	require.Equal(t, "1.0", batch.Version)
+	require.Equal(t, "1", batch.ChainID)
+	require.Equal(t, "AVS setup", batch.Meta.Name)
+	require.Equal(t, safe.Hex(), batch.Meta.CreatedFromSafeAddress)
+	require.Len(t, batch.Transactions, 2)
+	require.Equal(t, "5", batch.Transactions[1].Value)
+	require.Equal(t, "0x02", batch.Transactions[1].Data)
+
+	// Batches cannot mix chains
+	recorder.Record("PublishRelease", big.NewInt(2), to, nil, nil)
+	_, err = recorder.SafeBatch("mixed")
+	require.ErrorContains(t, err, "multiple chains")
+}
+
+func TestContractCallerRecordsInsteadOfSending(t *testing.T) {
+	recorder := NewCallRecorder(common.Address{})
+	cc := &ContractCaller{chainID: big.NewInt(17000), logger: logger.NewNoopLogger()}
+
+	// Without a key or recorder there is nothing to sign with
+	_, err := cc.buildTxOpts()
+	require.ErrorContains(t, err, "no private key configured")
+
+	cc.SetCallRecorder(recorder)
+	opts, err := cc.buildTxOpts()
+	require.NoError(t, err)
+	require.True(t, opts.NoSend)
+
+	to := common.HexToAddress("0x1000000000000000000000000000000000000001")
+	err = cc.SendAndWaitForTransaction(context.Background(), "UpdateAVSMetadataURI", func() (*types.Transaction, error) {
+		return opts.Signer(opts.From, types.NewTx(&types.LegacyTx{To: &to, Data: []byte{0xab}}))
+	})
+	require.NoError(t, err)
+
+	calls := recorder.Calls()
+	require.Len(t, calls, 1)
+	require.Equal(t, to, calls[0].To)
+	require.Equal(t, "0xab", calls[0].Data)
+	require.Equal(t, "17000", calls[0].ChainID)
+}
+
+func TestCallRecorderContext(t *testing.T) {
+	require.Nil(t, CallRecorderFromContext(context.Background()))
+
+	recorder := NewCallRecorder(common.Address{})
+	ctx := WithCallRecorder(context.Background(), recorder)
+	require.Same(t, recorder, CallRecorderFromContext(ctx))
+}
@@ -16,6 +16,19 @@ const DefaultConfigWithContextConfigPath = "config"
 
 type ConfigBlock struct {
 	Project ProjectConfig `json:"project" yaml:"project"`
+	Build   BuildConfig   `json:"build,omitempty" yaml:"build,omitempty"`
+}
+
+// BuildConfig declares the components the build script produces and the files each one is built from
+type BuildConfig struct {
+	Components []BuildComponentConfig `json:"components,omitempty" yaml:"components,omitempty"`
+}
+
+// BuildComponentConfig is a component and the globs of its inputs, relative to the project root.
+// A ** segment matches any number of directories.
+type BuildComponentConfig struct {
+	Name   string   `json:"name" yaml:"name"`
+	Inputs []string `json:"inputs" yaml:"inputs"`
 }
 
 type ProjectConfig struct {
@@ -39,6 +52,7 @@ type OperatorSpec struct {
 	ECDSAKey            string               `json:"ecdsa_key" yaml:"ecdsa_key"`
 	BlsKeystorePath     string               `json:"bls_keystore_path" yaml:"bls_keystore_path"`
 	BlsKeystorePassword string               `json:"bls_keystore_password" yaml:"bls_keystore_password"`
+	Signer              *SignerConfig        `json:"signer,omitempty" yaml:"signer,omitempty"`
 	Stake               string               `json:"stake,omitempty" yaml:"stake,omitempty"`
 	Allocations         []OperatorAllocation `json:"allocations,omitempty" yaml:"allocations,omitempty"`
 }
@@ -72,10 +86,11 @@ type StakerDeposits struct {
 }
 
 type AvsConfig struct {
-	Address          string `json:"address" yaml:"address"`
-	MetadataUri      string `json:"metadata_url" yaml:"metadata_url"`
-	AVSPrivateKey    string `json:"avs_private_key" yaml:"avs_private_key"`
-	RegistrarAddress string `json:"registrar_address" yaml:"registrar_address"`
+	Address          string        `json:"address" yaml:"address"`
+	MetadataUri      string        `json:"metadata_url" yaml:"metadata_url"`
+	AVSPrivateKey    string        `json:"avs_private_key" yaml:"avs_private_key"`
+	RegistrarAddress string        `json:"registrar_address" yaml:"registrar_address"`
+	Signer           *SignerConfig `json:"signer,omitempty" yaml:"signer,omitempty"`
 }
 
 type EigenLayerConfig struct {
@@ -99,9 +114,28 @@ type EigenLayerL2Config struct {
 }
 
 type ChainConfig struct {
-	ChainID int         `json:"chain_id" yaml:"chain_id"`
-	RPCURL  string      `json:"rpc_url" yaml:"rpc_url"`
-	Fork    *ForkConfig `json:"fork" yaml:"fork"`
+	ChainID      int         `json:"chain_id" yaml:"chain_id"`
+	RPCURL       string      `json:"rpc_url" yaml:"rpc_url"`
+	Fork         *ForkConfig `json:"fork" yaml:"fork"`
+	Transactions *TxConfig   `json:"transactions,omitempty" yaml:"transactions,omitempty"`
+	// Transport toggles stake table transport to this chain, enabled when unset
+	Transport *bool `json:"transport,omitempty" yaml:"transport,omitempty"`
+}
+
+// TransportEnabled reports whether stake tables should be transported to this chain
+func (c ChainConfig) TransportEnabled() bool {
+	return c.Transport == nil || *c.Transport
+}
+
+// TxConfig tunes how transactions sent to a chain are priced, replaced and confirmed
+type TxConfig struct {
+	MaxFeePerGasGwei         float64 `json:"max_fee_per_gas_gwei,omitempty" yaml:"max_fee_per_gas_gwei,omitempty"`
+	MaxPriorityFeePerGasGwei float64 `json:"max_priority_fee_per_gas_gwei,omitempty" yaml:"max_priority_fee_per_gas_gwei,omitempty"`
+	Confirmations            uint64  `json:"confirmations,omitempty" yaml:"confirmations,omitempty"`
+	ResubmitAfter            string  `json:"resubmit_after,omitempty" yaml:"resubmit_after,omitempty"`
+	MaxResubmits             int     `json:"max_resubmits,omitempty" yaml:"max_resubmits,omitempty"`
+	MaxRetries               int     `json:"max_retries,omitempty" yaml:"max_retries,omitempty"`
+	TraceReverts             bool    `json:"trace_reverts,omitempty" yaml:"trace_reverts,omitempty"`
 }
 
 type DeployedContract struct {
@@ -121,7 +155,9 @@ type Config struct {
 }
 
 type ContextConfig struct {
-	Version string             `json:"version" yaml:"version"`
+	Version string `json:"version" yaml:"version"`
+	// Context this one inherits its settings from
+	Extends string             `json:"extends,omitempty" yaml:"extends,omitempty"`
 	Context ChainContextConfig `json:"context" yaml:"context"`
 }
 
@@ -150,15 +186,64 @@ type Transporter struct {
 	PrivateKey       string           `json:"private_key" yaml:"private_key"`
 	BlsPrivateKey    string           `json:"bls_private_key" yaml:"bls_private_key"`
 	ActiveStakeRoots []StakeRootEntry `json:"active_stake_roots,omitempty" yaml:"active_stake_roots,omitempty"`
+	Signer           *SignerConfig    `json:"signer,omitempty" yaml:"signer,omitempty"`
+}
+
+// SignerConfig selects where a role keeps its keys, when absent the role's raw keys are used
+type SignerConfig struct {
+	// One of private_key (default), keystore or web3signer
+	Type                string `json:"type" yaml:"type"`
+	KeystorePath        string `json:"keystore_path,omitempty" yaml:"keystore_path,omitempty"`
+	KeystorePassword    string `json:"keystore_password,omitempty" yaml:"keystore_password,omitempty"`
+	URL                 string `json:"url,omitempty" yaml:"url,omitempty"`
+	Address             string `json:"address,omitempty" yaml:"address,omitempty"`
+	BlsKeystorePath     string `json:"bls_keystore_path,omitempty" yaml:"bls_keystore_path,omitempty"`
+	BlsKeystorePassword string `json:"bls_keystore_password,omitempty" yaml:"bls_keystore_password,omitempty"`
+	BlsPublicKey        string `json:"bls_public_key,omitempty" yaml:"bls_public_key,omitempty"`
 }
 
 // ArtifactConfig defines the structure for release artifacts
 type ArtifactConfig struct {
-	ArtifactId string `json:"artifactId" yaml:"artifactId"`
-	Component  string `json:"component" yaml:"component"`
-	Digest     string `json:"digest" yaml:"digest"`
-	Registry   string `json:"registry" yaml:"registry"`
-	Version    string `json:"version" yaml:"version"`
+	Registry   string              `json:"registry" yaml:"registry"`
+	Version    string              `json:"version" yaml:"version"`
+	Components []ArtifactComponent `json:"components,omitempty" yaml:"components,omitempty"`
+	// Version the components were last built for, release publish must publish this version
+	BuildVersion string `json:"build_version,omitempty" yaml:"build_version,omitempty"`
+
+	// Single component fields of contexts before 0.0.8, read through ComponentList
+	ArtifactId string `json:"artifactId,omitempty" yaml:"artifactId,omitempty"`
+	Component  string `json:"component,omitempty" yaml:"component,omitempty"`
+	Digest     string `json:"digest,omitempty" yaml:"digest,omitempty"`
+}
+
+// ArtifactComponent is one image produced by the build, such as the aggregator, the executor or a performer
+type ArtifactComponent struct {
+	Name string `json:"name" yaml:"name"`
+	// Local image built for the component and its image ID
+	Image      string `json:"image" yaml:"image"`
+	ArtifactId string `json:"artifact_id" yaml:"artifact_id"`
+	// Registry digest of the last released image
+	Digest string `json:"digest" yaml:"digest"`
+	// Registry to release to, defaults to the artifact registry
+	Registry string `json:"registry,omitempty" yaml:"registry,omitempty"`
+	// Operator sets the component is released to, defaults to the mapping returned by the release script
+	OperatorSets []uint32 `json:"operator_sets,omitempty" yaml:"operator_sets,omitempty"`
+	Platforms    []string `json:"platforms,omitempty" yaml:"platforms,omitempty"`
+	// sha256 of the binaries in the image by path, as reported by the build script
+	Hashes map[string]string `json:"hashes,omitempty" yaml:"hashes,omitempty"`
+}
+
+// ComponentList returns the artifact's components, reading the single component of an older context as a list of one
+func (a *ArtifactConfig) ComponentList() []ArtifactComponent {
+	if len(a.Components) > 0 || a.Component == "" {
+		return a.Components
+	}
+	return []ArtifactComponent{{
+		Name:       a.Component,
+		Image:      a.Component,
+		ArtifactId: a.ArtifactId,
+		Digest:     a.Digest,
+	}}
 }
 
 type ChainContextConfig struct {
@@ -184,7 +269,7 @@ func LoadBaseConfig() (map[string]interface{}, error) {
 		return nil, fmt.Errorf("read base config: %w", err)
 	}
 	var cfg map[string]interface{}
-	if err := yaml.Unmarshal(data, &cfg); err != nil {
+	if err := unmarshalExpanded(data, &cfg); err != nil {
 		return nil, fmt.Errorf("parse base config: %w", err)
 	}
 	return cfg, nil
@@ -195,13 +280,12 @@ func LoadContextConfig(ctxName string) (map[string]interface{}, error) {
 	if ctxName == "" {
 		ctxName = "devnet"
 	}
-	path := filepath.Join(DefaultConfigWithContextConfigPath, "contexts", ctxName+".yaml")
-	data, err := os.ReadFile(path)
+	_, rootNode, _, err := LoadContext(ctxName)
 	if err != nil {
 		return nil, fmt.Errorf("read context %q: %w", ctxName, err)
 	}
 	var ctx map[string]interface{}
-	if err := yaml.Unmarshal(data, &ctx); err != nil {
+	if err := ExpandEnvNode(rootNode).Decode(&ctx); err != nil {
 		return nil, fmt.Errorf("parse context %q: %w", ctxName, err)
 	}
 	return ctx, nil
@@ -214,7 +298,7 @@ func LoadBaseConfigYaml() (*Config, error) {
 		return nil, fmt.Errorf("read config: %w", err)
 	}
 	var cfg *Config
-	if err := yaml.Unmarshal(data, &cfg); err != nil {
+	if err := unmarshalExpanded(data, &cfg); err != nil {
 		return nil, fmt.Errorf("parse config: %w", err)
 	}
 	return cfg, nil
@@ -234,13 +318,12 @@ func LoadConfigWithContextConfig(ctxName string) (*ConfigWithContextConfig, erro
 	}
 
 	var cfg ConfigWithContextConfig
-	if err := yaml.Unmarshal(data, &cfg); err != nil {
+	if err := unmarshalExpanded(data, &cfg); err != nil {
 		return nil, fmt.Errorf("failed to parse base config: %w", err)
 	}
 
-	// Load requested context file
-	contextFile := filepath.Join(DefaultConfigWithContextConfigPath, "contexts", ctxName+".yaml")
-	ctxData, err := os.ReadFile(contextFile)
+	// Load requested context, merged with the contexts it extends and its local overlay
+	contextFile, rootNode, _, err := LoadContext(ctxName)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read context %q file: %w", ctxName, err)
 	}
@@ -250,7 +333,7 @@ func LoadConfigWithContextConfig(ctxName string) (*ConfigWithContextConfig, erro
 		Context ChainContextConfig `yaml:"context"`
 	}
 
-	if err := yaml.Unmarshal(ctxData, &wrapper); err != nil {
+	if err := ExpandEnvNode(rootNode).Decode(&wrapper); err != nil {
 		return nil, fmt.Errorf("failed to parse context file %q: %w", contextFile, err)
 	}
 
@@ -261,16 +344,19 @@ func LoadConfigWithContextConfig(ctxName string) (*ConfigWithContextConfig, erro
 	return &cfg, nil
 }
 
+// LoadContext loads a context merged with the contexts it extends and its local overlay. Write the returned
+// root back with WriteContext so changes land in the right file.
 func LoadContext(context string) (string, *yaml.Node, *yaml.Node, error) {
 	// Set path for context yaml
 	contextDir := filepath.Join("config", "contexts")
 	yamlPath := path.Join(contextDir, fmt.Sprintf("%s.%s", context, "yaml"))
 
-	// Load YAML as *yaml.Node
-	rootNode, err := LoadYAML(yamlPath)
+	// Load YAML as *yaml.Node, merging every layer of the context
+	layers, _, err := loadContextLayers(contextDir, context)
 	if err != nil {
 		return yamlPath, nil, nil, err
 	}
+	rootNode := mergeContextLayers(layers)
 
 	// YAML is parsed into a DocumentNode:
 	//   - rootNode.Content[0] is the top-level MappingNode
@@ -295,7 +381,7 @@ func LoadRawContext(context string) ([]byte, error) {
 	}
 
 	var ctxMap map[string]interface{}
-	if err := contextNode.Decode(&ctxMap); err != nil {
+	if err := ExpandEnvNode(contextNode).Decode(&ctxMap); err != nil {
 		return nil, fmt.Errorf("decode context node: %w", err)
 	}
 
@@ -0,0 +1,313 @@
+package common
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// LocalContextSuffix marks a context overlay that is kept out of version control and merged on top of its context
+const LocalContextSuffix = ".local.yaml"
+
+// IsLocalContextFile reports whether a file in config/contexts is a local overlay rather than a context
+func IsLocalContextFile(name string) bool {
+	return strings.HasSuffix(name, LocalContextSuffix)
+}
+
+// contextLayer is one file contributing to a context
+type contextLayer struct {
+	path string
+	doc  *yaml.Node
+}
+
+// loadContextLayers reads the files a context is merged from, in merge order: the contexts it extends
+// starting with the furthest, the context itself and its local overlay when present
+func loadContextLayers(dir, context string) (layers []contextLayer, own int, err error) {
+	seen := map[string]bool{}
+	for name := context; name != ""; {
+		if seen[name] {
+			return nil, 0, fmt.Errorf("context %q extends itself through %q", context, name)
+		}
+		seen[name] = true
+
+		path := filepath.Join(dir, name+".yaml")
+		doc, err := LoadYAML(path)
+		if err != nil {
+			return nil, 0, err
+		}
+		if len(doc.Content) == 0 {
+			return nil, 0, fmt.Errorf("empty YAML root node in %s", path)
+		}
+		layers = append([]contextLayer{{path: path, doc: doc}}, layers...)
+
+		name = ""
+		if extends := GetChildByKey(doc.Content[0], "extends"); extends != nil {
+			name = extends.Value
+		}
+	}
+	own = len(layers) - 1
+
+	localPath := filepath.Join(dir, context+LocalContextSuffix)
+	if _, err := os.Stat(localPath); err == nil {
+		doc, err := LoadYAML(localPath)
+		if err != nil {
+			return nil, 0, err
+		}
+		if len(doc.Content) > 0 {
+			layers = append(layers, contextLayer{path: localPath, doc: doc})
+		}
+	}
+	return layers, own, nil
+}
+
+// mergeContextLayers deep-merges the layers into a single document, without the extends key
+func mergeContextLayers(layers []contextLayer) *yaml.Node {
+	if len(layers) == 1 {
+		return layers[0].doc
+	}
+	merged := CloneNode(layers[0].doc)
+	for _, layer := range layers[1:] {
+		DeepMerge(merged.Content[0], layer.doc.Content[0])
+	}
+	removeKey(merged.Content[0], "extends")
+	return merged
+}
+
+// WriteContext writes a context document returned by LoadContext back to its files. A context without
+// layers is written as is. Otherwise each changed value goes to the local overlay when it already sets it,
+// and to the context file otherwise, so inherited values are overridden rather than changed in the base context.
+func WriteContext(yamlPath string, rootNode *yaml.Node) error {
+	dir := filepath.Dir(yamlPath)
+	context := strings.TrimSuffix(filepath.Base(yamlPath), ".yaml")
+	layers, own, err := loadContextLayers(dir, context)
+	if err != nil {
+		return err
+	}
+	if len(layers) == 1 {
+		return WriteYAML(yamlPath, rootNode)
+	}
+
+	var local *yaml.Node
+	if own < len(layers)-1 {
+		local = layers[len(layers)-1].doc.Content[0]
+	}
+	original := mergeContextLayers(layers)
+	var inherited *yaml.Node
+	if own > 0 {
+		inherited = mergeContextLayers(layers[:own]).Content[0]
+	}
+	before := make([]*yaml.Node, len(layers))
+	for i, layer := range layers[own:] {
+		before[own+i] = CloneNode(layer.doc)
+	}
+	applyContextChanges(original.Content[0], rootNode.Content[0], inherited, layers[own].doc.Content[0], local, nil)
+
+	// Only the layers that changed are written, so the others keep their formatting
+	for i, layer := range layers[own:] {
+		if nodesEqual(before[own+i], layer.doc) {
+			continue
+		}
+		if err := WriteYAML(layer.path, layer.doc); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// applyContextChanges writes the differences between the original and updated merged mappings into the
+// context's own layer, or into its local overlay for values the overlay sets. Keys removed from the merged
+// view that the extended contexts still set are overridden with null in the own layer.
+func applyContextChanges(original, updated, inherited, own, local *yaml.Node, path []string) {
+	for i := 0; i+1 < len(updated.Content); i += 2 {
+		key, value := updated.Content[i].Value, updated.Content[i+1]
+		keyPath := append(append([]string{}, path...), key)
+
+		previous := GetChildByKey(original, key)
+		if previous != nil && previous.Kind == yaml.MappingNode && value.Kind == yaml.MappingNode {
+			applyContextChanges(previous, value, inherited, own, local, keyPath)
+			continue
+		}
+		if previous != nil && nodesEqual(previous, value) {
+			continue
+		}
+
+		target := own
+		if local != nil && lookupPath(local, keyPath) != nil {
+			target = local
+		}
+		setPath(target, keyPath, CloneNode(value))
+	}
+
+	// Keys removed from the merged view are removed from the layers that can be written
+	for i := 0; i+1 < len(original.Content); i += 2 {
+		key := original.Content[i].Value
+		if GetChildByKey(updated, key) != nil {
+			continue
+		}
+		keyPath := append(append([]string{}, path...), key)
+		removePath(own, keyPath)
+		if local != nil {
+			removePath(local, keyPath)
+		}
+		if lookupPath(inherited, keyPath) != nil {
+			setPath(own, keyPath, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!null", Value: "null"})
+		}
+	}
+}
+
+// nodesEqual compares the content of two nodes, ignoring style, comments and positions
+func nodesEqual(a, b *yaml.Node) bool {
+	if a.Kind != b.Kind || a.Value != b.Value || a.ShortTag() != b.ShortTag() || len(a.Content) != len(b.Content) {
+		return false
+	}
+	for i := range a.Content {
+		if !nodesEqual(a.Content[i], b.Content[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func lookupPath(node *yaml.Node, path []string) *yaml.Node {
+	for _, key := range path {
+		if node == nil || node.Kind != yaml.MappingNode {
+			return nil
+		}
+		node = GetChildByKey(node, key)
+	}
+	return node
+}
+
+// setPath sets the value at path in a mapping, creating the mappings leading to it
+func setPath(node *yaml.Node, path []string, value *yaml.Node) {
+	for _, key := range path[:len(path)-1] {
+		child := GetChildByKey(node, key)
+		if child == nil || child.Kind != yaml.MappingNode {
+			child = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+			SetMappingValue(node, &yaml.Node{Kind: yaml.ScalarNode, Value: key}, child)
+		}
+		node = child
+	}
+	SetMappingValue(node, &yaml.Node{Kind: yaml.ScalarNode, Value: path[len(path)-1]}, value)
+}
+
+func removePath(node *yaml.Node, path []string) {
+	if parent := lookupPath(node, path[:len(path)-1]); parent != nil && parent.Kind == yaml.MappingNode {
+		removeKey(parent, path[len(path)-1])
+	}
+}
+
+func removeKey(node *yaml.Node, key string) {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			node.Content = append(node.Content[:i], node.Content[i+2:]...)
+			return
+		}
+	}
+}
+
+// ContextView is a context merged from its layers like LoadContext, remembering the file each node comes from so
+// findings in the merged view can be reported where the value is set
+type ContextView struct {
+	// Doc is the merged document
+	Doc *yaml.Node
+	// Files the context is merged from, in merge order
+	Files []string
+	// File of the context itself
+	Path    string
+	origins map[*yaml.Node]string
+}
+
+// LoadContextView merges the layers of context from dir, see LoadContext
+func LoadContextView(dir, context string) (*ContextView, error) {
+	layers, own, err := loadContextLayers(dir, context)
+	if err != nil {
+		return nil, err
+	}
+	v := &ContextView{Path: layers[own].path, origins: make(map[*yaml.Node]string)}
+	for _, layer := range layers {
+		v.Files = append(v.Files, layer.path)
+	}
+	v.Doc = v.clone(layers[0].doc, layers[0].path)
+	for _, layer := range layers[1:] {
+		v.merge(v.Doc.Content[0], layer.doc.Content[0], layer.path)
+	}
+	if len(layers) > 1 {
+		removeKey(v.Doc.Content[0], "extends")
+	}
+	return v, nil
+}
+
+// Layered reports whether the context is merged from more than one file
+func (v *ContextView) Layered() bool {
+	return len(v.Files) > 1
+}
+
+// File returns the file node of the merged view comes from, the context's own file when unknown
+func (v *ContextView) File(node *yaml.Node) string {
+	if file, ok := v.origins[node]; ok {
+		return file
+	}
+	return v.Path
+}
+
+// ExpandEnv returns a copy of the merged view with environment references expanded, see ExpandEnvNode. Its nodes
+// keep the file they come from.
+func (v *ContextView) ExpandEnv() *yaml.Node {
+	expanded := v.cloneTracked(v.Doc)
+	expandEnvInPlace(expanded)
+	return expanded
+}
+
+// clone copies node, recording file as the origin of every copied node
+func (v *ContextView) clone(node *yaml.Node, file string) *yaml.Node {
+	c := CloneNode(node)
+	v.record(c, file)
+	return c
+}
+
+func (v *ContextView) record(node *yaml.Node, file string) {
+	v.origins[node] = file
+	for _, child := range node.Content {
+		v.record(child, file)
+	}
+}
+
+// cloneTracked copies node, each copy keeping the origin of the node it copies
+func (v *ContextView) cloneTracked(node *yaml.Node) *yaml.Node {
+	c := *node
+	if file, ok := v.origins[node]; ok {
+		v.origins[&c] = file
+	}
+	if node.Content != nil {
+		c.Content = make([]*yaml.Node, len(node.Content))
+		for i, child := range node.Content {
+			c.Content[i] = v.cloneTracked(child)
+		}
+	}
+	return &c
+}
+
+// merge deep-merges src from file into dst like DeepMerge
+func (v *ContextView) merge(dst, src *yaml.Node, file string) {
+	for i := 0; i+1 < len(src.Content); i += 2 {
+		key, value := src.Content[i], src.Content[i+1]
+		j := 0
+		for ; j+1 < len(dst.Content); j += 2 {
+			if dst.Content[j].Value == key.Value {
+				break
+			}
+		}
+		switch {
+		case j+1 >= len(dst.Content):
+			dst.Content = append(dst.Content, v.clone(key, file), v.clone(value, file))
+		case dst.Content[j+1].Kind == yaml.MappingNode && value.Kind == yaml.MappingNode:
+			v.merge(dst.Content[j+1], value, file)
+		default:
+			dst.Content[j+1] = v.clone(value, file)
+		}
+	}
+}
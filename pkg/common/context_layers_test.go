@@ -0,0 +1,188 @@
+package common
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeContextFiles writes files into config/contexts of a temp project and moves into it
+func writeContextFiles(t *testing.T, files map[string]string) string {
+	t.Helper()
+	tmp := t.TempDir()
+	dir := filepath.Join(tmp, "config", "contexts")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	orig, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		if err := os.Chdir(orig); err != nil {
+			t.Fatal(err)
+		}
+	})
+	if err := os.Chdir(tmp); err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+const testnetContext = `version: 0.0.8
+context:
+  name: testnet
+  chains:
+    l1:
+      chain_id: 17000
+      rpc_url: "https://holesky.example"
+  avs:
+    address: "0x70997970C51812dc3A010C7d01b50e0d17dc79C8"
+    metadata_url: "https://example.com/metadata.json"
+`
+
+func TestLoadContextMergesLayers(t *testing.T) {
+	writeContextFiles(t, map[string]string{
+		"testnet.yaml":       testnetContext,
+		"staging.yaml":       "version: 0.0.8\nextends: testnet\ncontext:\n  name: staging\n  avs:\n    metadata_url: \"https://staging.example.com/metadata.json\"\n",
+		"staging.local.yaml": "context:\n  chains:\n    l1:\n      rpc_url: \"http://localhost:8545\"\n",
+	})
+
+	_, rootNode, contextNode, err := LoadContext("staging")
+	if err != nil {
+		t.Fatalf("LoadContext failed: %v", err)
+	}
+	if GetChildByKey(rootNode.Content[0], "extends") != nil {
+		t.Errorf("merged context should not keep extends")
+	}
+
+	var ctx ChainContextConfig
+	if err := contextNode.Decode(&ctx); err != nil {
+		t.Fatal(err)
+	}
+	if ctx.Name != "staging" {
+		t.Errorf("expected name from the context, got %q", ctx.Name)
+	}
+	if ctx.Chains["l1"].ChainID != 17000 {
+		t.Errorf("expected chain_id inherited from testnet, got %d", ctx.Chains["l1"].ChainID)
+	}
+	if ctx.Chains["l1"].RPCURL != "http://localhost:8545" {
+		t.Errorf("expected rpc_url from the local overlay, got %q", ctx.Chains["l1"].RPCURL)
+	}
+	if ctx.Avs.Address != "0x70997970C51812dc3A010C7d01b50e0d17dc79C8" || ctx.Avs.MetadataUri != "https://staging.example.com/metadata.json" {
+		t.Errorf("expected avs merged key by key, got %+v", ctx.Avs)
+	}
+
+	cfgPath := filepath.Join("config", BaseConfig)
+	if err := os.WriteFile(cfgPath, []byte("version: 0.0.2\nconfig:\n  project:\n    name: test\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := LoadConfigWithContextConfig("staging")
+	if err != nil {
+		t.Fatalf("LoadConfigWithContextConfig failed: %v", err)
+	}
+	if cfg.Context["staging"].Chains["l1"].ChainID != 17000 {
+		t.Errorf("expected the merged context, got %+v", cfg.Context["staging"])
+	}
+}
+
+func TestWriteContextWritesToLayers(t *testing.T) {
+	dir := writeContextFiles(t, map[string]string{
+		"testnet.yaml":       testnetContext,
+		"staging.yaml":       "version: 0.0.8\nextends: testnet\ncontext:\n  name: staging\n",
+		"staging.local.yaml": "context:\n  chains:\n    l1:\n      rpc_url: \"http://localhost:8545\"\n",
+	})
+
+	yamlPath, rootNode, contextNode, err := LoadContext("staging")
+	if err != nil {
+		t.Fatalf("LoadContext failed: %v", err)
+	}
+	l1 := GetChildByKey(GetChildByKey(contextNode, "chains"), "l1")
+	GetChildByKey(l1, "rpc_url").Value = "http://localhost:9545"
+	GetChildByKey(l1, "chain_id").Value = "17001"
+	GetChildByKey(contextNode, "name").Value = "staging-2"
+	if err := WriteContext(yamlPath, rootNode); err != nil {
+		t.Fatalf("WriteContext failed: %v", err)
+	}
+
+	read := func(name string) string {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(data)
+	}
+	if base := read("testnet.yaml"); base != testnetContext {
+		t.Errorf("the extended context must not change, got:\n%s", base)
+	}
+	staging := read("staging.yaml")
+	for _, want := range []string{"extends: testnet", "name: staging-2", "chain_id: 17001"} {
+		if !strings.Contains(staging, want) {
+			t.Errorf("expected %q in staging.yaml, got:\n%s", want, staging)
+		}
+	}
+	if strings.Contains(staging, "rpc_url") {
+		t.Errorf("rpc_url is set by the local overlay and should be written there, got:\n%s", staging)
+	}
+	if local := read("staging.local.yaml"); !strings.Contains(local, "http://localhost:9545") {
+		t.Errorf("expected the new rpc_url in the local overlay, got:\n%s", local)
+	}
+}
+
+func TestWriteContextOverridesRemovedInheritedKeys(t *testing.T) {
+	dir := writeContextFiles(t, map[string]string{
+		"testnet.yaml": testnetContext,
+		"staging.yaml": "version: 0.0.8\nextends: testnet\ncontext:\n  name: staging\n",
+	})
+
+	yamlPath, rootNode, contextNode, err := LoadContext("staging")
+	if err != nil {
+		t.Fatalf("LoadContext failed: %v", err)
+	}
+	removeKey(GetChildByKey(contextNode, "avs"), "metadata_url")
+	if err := WriteContext(yamlPath, rootNode); err != nil {
+		t.Fatalf("WriteContext failed: %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "testnet.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != testnetContext {
+		t.Errorf("the extended context must not change, got:\n%s", data)
+	}
+
+	// The key is still set by testnet, so staging has to override it for the removal to stick
+	_, _, contextNode, err = LoadContext("staging")
+	if err != nil {
+		t.Fatalf("LoadContext failed: %v", err)
+	}
+	var ctx ChainContextConfig
+	if err := contextNode.Decode(&ctx); err != nil {
+		t.Fatal(err)
+	}
+	if ctx.Avs.MetadataUri != "" {
+		t.Errorf("expected metadata_url to stay removed, got %q", ctx.Avs.MetadataUri)
+	}
+	if ctx.Avs.Address != "0x70997970C51812dc3A010C7d01b50e0d17dc79C8" {
+		t.Errorf("expected the other avs keys to be inherited, got %+v", ctx.Avs)
+	}
+}
+
+func TestLoadContextRejectsExtendsCycle(t *testing.T) {
+	writeContextFiles(t, map[string]string{
+		"a.yaml": "version: 0.0.8\nextends: b\ncontext:\n  name: a\n",
+		"b.yaml": "version: 0.0.8\nextends: a\ncontext:\n  name: b\n",
+	})
+
+	if _, _, _, err := LoadContext("a"); err == nil || !strings.Contains(err.Error(), "extends itself") {
+		t.Errorf("expected a cycle error, got %v", err)
+	}
+}
@@ -3,7 +3,6 @@ package common
 import (
 	"bytes"
 	"context"
-	"crypto/ecdsa"
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
@@ -13,6 +12,7 @@ import (
 	"github.com/Layr-Labs/crypto-libs/pkg/bn254"
 	"github.com/Layr-Labs/devkit-cli/pkg/common/contracts"
 	"github.com/Layr-Labs/devkit-cli/pkg/common/iface"
+	"github.com/Layr-Labs/devkit-cli/pkg/common/signer"
 	allocationmanager "github.com/Layr-Labs/eigenlayer-contracts/pkg/bindings/AllocationManager"
 	crosschainregistry "github.com/Layr-Labs/eigenlayer-contracts/pkg/bindings/CrossChainRegistry"
 	"github.com/Layr-Labs/eigenlayer-contracts/pkg/bindings/DelegationManager"
@@ -30,7 +30,7 @@ import (
 type ContractCaller struct {
 	registry               *contracts.ContractRegistry
 	ethclient              *ethclient.Client
-	privateKey             *ecdsa.PrivateKey
+	signer                 signer.TxSigner
 	chainID                *big.Int
 	logger                 iface.Logger
 	allocationManagerAddr  common.Address
@@ -39,27 +39,41 @@ type ContractCaller struct {
 	keyRegistrarAddr       common.Address
 	crossChainRegistryAddr common.Address
 	releaseManagerAddr     common.Address
+	txManager              *TxManager
+	recorder               *CallRecorder
 }
 
-func NewContractCaller(privateKeyHex string, chainID *big.Int, client *ethclient.Client, allocationManagerAddr, delegationManagerAddr, strategyManagerAddr, keyRegistrarAddr common.Address, crossChainRegistryAddr common.Address, releaseManagerAddr common.Address, logger iface.Logger) (*ContractCaller, error) {
-	privateKey, err := crypto.HexToECDSA(strings.TrimPrefix(privateKeyHex, "0x"))
-	if err != nil {
-		return nil, fmt.Errorf("invalid private key: %w", err)
-	}
-
+// NewContractCaller creates a caller for the EigenLayer core contracts. Transactions are signed by the signer
+// described by signerCfg, or privateKeyHex when it is nil. Both may be empty for callers that only read or record
+// calldata (see SetCallRecorder). Close releases the signer.
+func NewContractCaller(ctx context.Context, privateKeyHex string, signerCfg *SignerConfig, chainID *big.Int, client *ethclient.Client, allocationManagerAddr, delegationManagerAddr, strategyManagerAddr, keyRegistrarAddr common.Address, crossChainRegistryAddr common.Address, releaseManagerAddr common.Address, txConfig *TxConfig, logger iface.Logger) (*ContractCaller, error) {
 	// Build contract registry with core EigenLayer contracts
 	builder := contracts.NewRegistryBuilder(client)
-	builder, err = builder.AddEigenLayerCore(allocationManagerAddr, delegationManagerAddr, strategyManagerAddr, keyRegistrarAddr, crossChainRegistryAddr, releaseManagerAddr)
+	builder, err := builder.AddEigenLayerCore(allocationManagerAddr, delegationManagerAddr, strategyManagerAddr, keyRegistrarAddr, crossChainRegistryAddr, releaseManagerAddr)
 	if err != nil {
 		return nil, fmt.Errorf("failed to add EigenLayer core contracts: %w", err)
 	}
 
 	registry := builder.Build()
 
+	// A nil txConfig uses the defaults, see TxConfig.TxManagerConfig
+	txManagerCfg, err := txConfig.TxManagerConfig()
+	if err != nil {
+		return nil, fmt.Errorf("invalid transactions config: %w", err)
+	}
+
+	var txSigner signer.TxSigner
+	if privateKeyHex != "" || !isRawKeySigner(signerCfg) {
+		txSigner, err = NewTxSigner(ctx, signerCfg, privateKeyHex)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create signer: %w", err)
+		}
+	}
+
 	return &ContractCaller{
 		registry:               registry,
 		ethclient:              client,
-		privateKey:             privateKey,
+		signer:                 txSigner,
 		chainID:                chainID,
 		logger:                 logger,
 		allocationManagerAddr:  allocationManagerAddr,
@@ -68,15 +82,48 @@ func NewContractCaller(privateKeyHex string, chainID *big.Int, client *ethclient
 		keyRegistrarAddr:       keyRegistrarAddr,
 		crossChainRegistryAddr: crossChainRegistryAddr,
 		releaseManagerAddr:     releaseManagerAddr,
+		txManager:              NewTxManager(client, chainID, txManagerCfg, logger),
 	}, nil
 }
 
-func (cc *ContractCaller) buildTxOpts() (*bind.TransactOpts, error) {
-	opts, err := bind.NewKeyedTransactorWithChainID(cc.privateKey, cc.chainID)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create transactor: %w", err)
+// Close releases the caller's signer
+func (cc *ContractCaller) Close() {
+	if cc.signer != nil {
+		cc.signer.Close()
 	}
-	return opts, nil
+}
+
+// SetCallRecorder switches the caller to calldata-only mode, where transactions are recorded instead of sent
+func (cc *ContractCaller) SetCallRecorder(recorder *CallRecorder) {
+	cc.recorder = recorder
+}
+
+// buildTxOpts returns options that only build and estimate the tx, sending is left to the TxManager
+func (cc *ContractCaller) buildTxOpts() (*bind.TransactOpts, error) {
+	if cc.recorder != nil {
+		// Build offline: the sender is usually a multisig, so estimating gas or fetching a nonce is meaningless
+		return &bind.TransactOpts{
+			From:     cc.recorder.From(),
+			Nonce:    new(big.Int),
+			GasPrice: new(big.Int),
+			GasLimit: 1,
+			NoSend:   true,
+			Signer: func(_ common.Address, tx *types.Transaction) (*types.Transaction, error) {
+				return tx, nil
+			},
+		}, nil
+	}
+	if cc.signer == nil {
+		return nil, fmt.Errorf("no private key configured, use --calldata-only or --safe-batch to prepare the calls for a multisig")
+	}
+	// Leave the tx unsigned, it is signed once by the TxManager after the nonce and fees are set
+	return &bind.TransactOpts{
+		From:   cc.signer.Address(),
+		NoSend: true,
+		Signer: func(_ common.Address, tx *types.Transaction) (*types.Transaction, error) {
+			return tx, nil
+		},
+	}, nil
 }
 
 func (cc *ContractCaller) SendAndWaitForTransaction(
@@ -87,18 +134,29 @@ func (cc *ContractCaller) SendAndWaitForTransaction(
 
 	tx, err := fn()
 	if err != nil {
+		// Gas estimation reverts carry the revert data in the RPC error
+		err = decodeRevertFromError(err)
 		cc.logger.Error("%s failed during execution: %v", txDescription, err)
 		return fmt.Errorf("%s execution: %w", txDescription, err)
 	}
 
-	receipt, err := bind.WaitMined(ctx, cc.ethclient, tx)
+	if cc.recorder != nil {
+		cc.recorder.Record(txDescription, cc.chainID, *tx.To(), tx.Value(), tx.Data())
+		cc.logger.Info("Recorded %s call to %s (not sent)", txDescription, tx.To().Hex())
+		return nil
+	}
+
+	// Re-sign with a managed nonce and dynamic fees, then send and wait for confirmation
+	mined, receipt, err := cc.txManager.SendAndWait(ctx, cc.signer, tx)
 	if err != nil {
-		cc.logger.Error("Waiting for %s transaction (hash: %s) failed: %v", txDescription, tx.Hash().Hex(), err)
-		return fmt.Errorf("waiting for %s transaction (hash: %s): %w", txDescription, tx.Hash().Hex(), err)
+		cc.logger.Error("Sending %s transaction failed: %v", txDescription, err)
+		return fmt.Errorf("sending %s transaction: %w", txDescription, err)
 	}
+	tx = mined
 	if receipt.Status == 0 {
-		cc.logger.Error("%s transaction (hash: %s) reverted", txDescription, tx.Hash().Hex())
-		return fmt.Errorf("%s transaction (hash: %s) reverted", txDescription, tx.Hash().Hex())
+		revertErr := cc.diagnoseRevert(ctx, tx, receipt)
+		cc.logger.Error("%s %v", txDescription, revertErr)
+		return fmt.Errorf("%s %w", txDescription, revertErr)
 	}
 	return nil
 }
@@ -571,11 +629,8 @@ func (cc *ContractCaller) CreateGenerationReservation(ctx context.Context, opSet
 
 func (cc *ContractCaller) WhitelistChainIdInCrossRegistry(ctx context.Context, operatorTableUpdater common.Address, chainId uint64) error {
 	var (
-		err      error
-		nonce    uint64
-		gasPrice *big.Int
-		signedTx *types.Transaction
-		receipt  *types.Receipt
+		err     error
+		receipt *types.Receipt
 	)
 
 	chainIds := []*big.Int{big.NewInt(int64(chainId))}
@@ -587,51 +642,21 @@ func (cc *ContractCaller) WhitelistChainIdInCrossRegistry(ctx context.Context, o
 
 	// Fund the cross chain registry owner with 1 ETH if needed
 	anvilKey := "0xac0974bec39a17e36ba4a6b4d238ff944bacb478cbed5efcae784d7bf4f2ff80"
-	privateKey, err := crypto.HexToECDSA(strings.TrimPrefix(anvilKey, "0x"))
+	anvilSigner, err := signer.NewPrivateKeySigner(anvilKey)
 	if err != nil {
 		return fmt.Errorf("failed to parse anvil private key: %w", err)
 	}
 
-	// Get the nonce for the sender
-	fromAddress := crypto.PubkeyToAddress(privateKey.PublicKey)
-	nonce, err = cc.ethclient.PendingNonceAt(ctx, fromAddress)
-	if err != nil {
-		return fmt.Errorf("failed to get nonce: %w", err)
-	}
-
-	// Get gas price
-	gasPrice, err = cc.ethclient.SuggestGasPrice(ctx)
-	if err != nil {
-		return fmt.Errorf("failed to get gas price: %w", err)
-	}
-
-	// Create the transaction
-	tx := types.NewTransaction(
-		nonce,
-		ownerCrossChainRegistry,
-		big.NewInt(1000000000000000000), // 1 ETH in wei
-		21000,                           // Standard ETH transfer gas limit
-		gasPrice,
-		nil,
-	)
-
-	// Sign the transaction
-	signedTx, err = types.SignTx(tx, types.NewEIP155Signer(cc.chainID), privateKey)
-	if err != nil {
-		return fmt.Errorf("failed to sign transaction: %w", err)
-	}
-
-	// Send the transaction
-	if err = cc.ethclient.SendTransaction(ctx, signedTx); err != nil {
-		return fmt.Errorf("failed to send transaction: %w", err)
-	}
-
-	// Wait for transaction to be mined
-	receipt, err = bind.WaitMined(ctx, cc.ethclient, signedTx)
+	// Send 1 ETH to the owner so it can pay for the impersonated call
+	fundTx := types.NewTx(&types.DynamicFeeTx{
+		To:    &ownerCrossChainRegistry,
+		Value: big.NewInt(1000000000000000000), // 1 ETH in wei
+		Gas:   21000,                           // Standard ETH transfer gas limit
+	})
+	_, receipt, err = cc.txManager.SendAndWait(ctx, anvilSigner, fundTx)
 	if err != nil {
-		return fmt.Errorf("failed to wait for transaction: %w", err)
+		return fmt.Errorf("failed to fund cross chain registry owner: %w", err)
 	}
-
 	if receipt.Status == 0 {
 		return fmt.Errorf("transaction failed")
 	}
@@ -646,8 +671,8 @@ func (cc *ContractCaller) WhitelistChainIdInCrossRegistry(ctx context.Context, o
 		}
 	}()
 
-	// Get gas price
-	gasPrice, err = cc.ethclient.SuggestGasPrice(ctx)
+	// Get fee fields for the impersonated call
+	txFields, err := cc.txManager.FeeFields(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to get gas price: %w", err)
 	}
@@ -666,14 +691,12 @@ func (cc *ContractCaller) WhitelistChainIdInCrossRegistry(ctx context.Context, o
 
 	// Send addChainIDsToWhitelist transaction from impersonated account using RPC
 	var txHash common.Hash
-	err = rpcClient.Call(&txHash, "eth_sendTransaction", map[string]interface{}{
-		"from":     ownerCrossChainRegistry.Hex(),
-		"to":       cc.crossChainRegistryAddr.Hex(),
-		"gas":      "0x30d40", // 200000 in hex
-		"gasPrice": fmt.Sprintf("0x%x", gasPrice),
-		"value":    "0x0",
-		"data":     fmt.Sprintf("0x%x", addChainIDsToWhitelistData),
-	})
+	txFields["from"] = ownerCrossChainRegistry.Hex()
+	txFields["to"] = cc.crossChainRegistryAddr.Hex()
+	txFields["gas"] = "0x30d40" // 200000 in hex
+	txFields["value"] = "0x0"
+	txFields["data"] = fmt.Sprintf("0x%x", addChainIDsToWhitelistData)
+	err = rpcClient.Call(&txHash, "eth_sendTransaction", txFields)
 	if err != nil {
 		cc.logger.Error("failed to send addChainIDsToWhitelist transaction: %w", err)
 		return fmt.Errorf("failed to send addChainIDsToWhitelist transaction: %w", err)
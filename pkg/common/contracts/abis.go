@@ -0,0 +1,65 @@
+package contracts
+
+import (
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+
+	// EigenLayer contract bindings
+	allocationmanager "github.com/Layr-Labs/eigenlayer-contracts/pkg/bindings/AllocationManager"
+	crosschainregistry "github.com/Layr-Labs/eigenlayer-contracts/pkg/bindings/CrossChainRegistry"
+	delegationmanager "github.com/Layr-Labs/eigenlayer-contracts/pkg/bindings/DelegationManager"
+	"github.com/Layr-Labs/eigenlayer-contracts/pkg/bindings/IOperatorTableUpdater"
+	keyregistrar "github.com/Layr-Labs/eigenlayer-contracts/pkg/bindings/KeyRegistrar"
+	releasemanager "github.com/Layr-Labs/eigenlayer-contracts/pkg/bindings/ReleaseManager"
+	strategymanager "github.com/Layr-Labs/eigenlayer-contracts/pkg/bindings/StrategyManager"
+)
+
+// OperatorTableUpdaterContract identifies the L2 OperatorTableUpdater, which is only used for ABI lookups
+const OperatorTableUpdaterContract ContractType = "OperatorTableUpdater"
+
+// bindingMetaData maps each core contract type to the metadata shipped with its binding
+var bindingMetaData = map[ContractType]*bind.MetaData{
+	AllocationManagerContract:    allocationmanager.AllocationManagerMetaData,
+	DelegationManagerContract:    delegationmanager.DelegationManagerMetaData,
+	StrategyManagerContract:      strategymanager.StrategyManagerMetaData,
+	KeyRegistrarContract:         keyregistrar.KeyRegistrarMetaData,
+	CrossChainRegistryContract:   crosschainregistry.CrossChainRegistryMetaData,
+	ReleaseManagerContract:       releasemanager.ReleaseManagerMetaData,
+	OperatorTableUpdaterContract: IOperatorTableUpdater.IOperatorTableUpdaterMetaData,
+}
+
+// GetBindingABI returns the parsed ABI of a core contract from its Go binding
+func GetBindingABI(contractType ContractType) (*abi.ABI, error) {
+	if contractType == ERC20Contract {
+		parsed, err := GetERC20ABI()
+		if err != nil {
+			return nil, err
+		}
+		return &parsed, nil
+	}
+
+	meta, ok := bindingMetaData[contractType]
+	if !ok {
+		return nil, fmt.Errorf("no binding ABI for contract type: %s", contractType)
+	}
+	parsed, err := meta.GetAbi()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s ABI: %w", contractType, err)
+	}
+	return parsed, nil
+}
+
+// BindingContractTypes returns the contract types that have a binding ABI available
+func BindingContractTypes() []ContractType {
+	return []ContractType{
+		AllocationManagerContract,
+		DelegationManagerContract,
+		StrategyManagerContract,
+		KeyRegistrarContract,
+		CrossChainRegistryContract,
+		ReleaseManagerContract,
+		OperatorTableUpdaterContract,
+	}
+}
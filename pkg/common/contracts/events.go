@@ -0,0 +1,256 @@
+package contracts
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// ErrUnknownEvent is returned when a log cannot be matched to a watched contract event
+var ErrUnknownEvent = errors.New("unknown event")
+
+// DecodedEvent is a log decoded against the ABI of the contract that emitted it
+type DecodedEvent struct {
+	Chain       string                 `json:"chain,omitempty"`
+	Contract    string                 `json:"contract"`
+	Address     string                 `json:"address"`
+	Event       string                 `json:"event"`
+	BlockNumber uint64                 `json:"blockNumber"`
+	TxHash      string                 `json:"txHash"`
+	LogIndex    uint                   `json:"logIndex"`
+	Args        map[string]interface{} `json:"args"`
+	Indexed     []string               `json:"indexed,omitempty"`
+}
+
+// EventFilter narrows decoded events by contract, event name and indexed argument values
+type EventFilter struct {
+	Contracts []string
+	Events    []string
+	Indexed   map[string]string
+}
+
+// watchedContract pairs a contract name with the ABI used to decode its logs
+type watchedContract struct {
+	name string
+	abi  *abi.ABI
+}
+
+// EventDecoder decodes logs for a set of known contract addresses
+type EventDecoder struct {
+	contracts map[common.Address]watchedContract
+}
+
+// NewEventDecoder creates an empty event decoder
+func NewEventDecoder() *EventDecoder {
+	return &EventDecoder{
+		contracts: make(map[common.Address]watchedContract),
+	}
+}
+
+// AddContract registers a contract address and the ABI its logs are decoded with
+func (d *EventDecoder) AddContract(name string, address common.Address, contractABI *abi.ABI) {
+	d.contracts[address] = watchedContract{name: name, abi: contractABI}
+}
+
+// Addresses returns the registered contract addresses, optionally restricted to the given contract names
+func (d *EventDecoder) Addresses(names ...string) []common.Address {
+	addresses := make([]common.Address, 0, len(d.contracts))
+	for addr, c := range d.contracts {
+		if len(names) > 0 && !containsFold(names, c.name) {
+			continue
+		}
+		addresses = append(addresses, addr)
+	}
+	sort.Slice(addresses, func(i, j int) bool {
+		return addresses[i].Hex() < addresses[j].Hex()
+	})
+	return addresses
+}
+
+// ContractNames returns the names of all registered contracts
+func (d *EventDecoder) ContractNames() []string {
+	names := make([]string, 0, len(d.contracts))
+	for _, c := range d.contracts {
+		names = append(names, c.name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Decode decodes a single log, returning ErrUnknownEvent for unregistered addresses or topics
+func (d *EventDecoder) Decode(log types.Log) (*DecodedEvent, error) {
+	c, ok := d.contracts[log.Address]
+	if !ok || len(log.Topics) == 0 {
+		return nil, ErrUnknownEvent
+	}
+	event, err := c.abi.EventByID(log.Topics[0])
+	if err != nil {
+		return nil, ErrUnknownEvent
+	}
+
+	args := make(map[string]interface{})
+	if len(log.Data) > 0 {
+		if err := c.abi.UnpackIntoMap(args, event.Name, log.Data); err != nil {
+			return nil, fmt.Errorf("failed to unpack %s.%s: %w", c.name, event.Name, err)
+		}
+	}
+
+	var indexed abi.Arguments
+	var indexedNames []string
+	for _, input := range event.Inputs {
+		if input.Indexed {
+			indexed = append(indexed, input)
+			indexedNames = append(indexedNames, input.Name)
+		}
+	}
+	if len(indexed) > 0 {
+		if err := abi.ParseTopicsIntoMap(args, indexed, log.Topics[1:]); err != nil {
+			return nil, fmt.Errorf("failed to parse topics for %s.%s: %w", c.name, event.Name, err)
+		}
+	}
+
+	normalized := make(map[string]interface{}, len(args))
+	for k, v := range args {
+		normalized[k] = NormalizeABIValue(v)
+	}
+
+	return &DecodedEvent{
+		Contract:    c.name,
+		Address:     log.Address.Hex(),
+		Event:       event.Name,
+		BlockNumber: log.BlockNumber,
+		TxHash:      log.TxHash.Hex(),
+		LogIndex:    log.Index,
+		Args:        normalized,
+		Indexed:     indexedNames,
+	}, nil
+}
+
+// Matches reports whether the decoded event passes the filter
+func (f EventFilter) Matches(ev *DecodedEvent) bool {
+	if len(f.Contracts) > 0 && !containsFold(f.Contracts, ev.Contract) {
+		return false
+	}
+	if len(f.Events) > 0 && !containsFold(f.Events, ev.Event) {
+		return false
+	}
+	for key, want := range f.Indexed {
+		if !containsFold(ev.Indexed, key) {
+			return false
+		}
+		got, ok := lookupFold(ev.Args, key)
+		if !ok || !strings.EqualFold(fmt.Sprint(got), want) {
+			return false
+		}
+	}
+	return true
+}
+
+// ParseIndexedFilters parses key=value pairs used to filter on indexed event arguments
+func ParseIndexedFilters(pairs []string) (map[string]string, error) {
+	filters := make(map[string]string, len(pairs))
+	for _, pair := range pairs {
+		kv := strings.SplitN(pair, "=", 2)
+		if len(kv) != 2 || kv[0] == "" {
+			return nil, fmt.Errorf("invalid filter %q, expected name=value", pair)
+		}
+		filters[kv[0]] = kv[1]
+	}
+	return filters, nil
+}
+
+// String renders the event as a single human-readable line
+func (ev *DecodedEvent) String() string {
+	keys := make([]string, 0, len(ev.Args))
+	for k := range ev.Args {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	parts := make([]string, 0, len(keys))
+	for _, k := range keys {
+		parts = append(parts, fmt.Sprintf("%s=%v", k, ev.Args[k]))
+	}
+	return fmt.Sprintf("[block %d] %s.%s(%s) tx=%s log=%d",
+		ev.BlockNumber, ev.Contract, ev.Event, strings.Join(parts, ", "), ev.TxHash, ev.LogIndex)
+}
+
+// NormalizeABIValue converts ABI-decoded values into printable, JSON-friendly values
+func NormalizeABIValue(v interface{}) interface{} {
+	switch val := v.(type) {
+	case nil:
+		return nil
+	case common.Address:
+		return val.Hex()
+	case common.Hash:
+		return val.Hex()
+	case *big.Int:
+		if val == nil {
+			return nil
+		}
+		return val.String()
+	case []byte:
+		return hexutil.Encode(val)
+	case string, bool:
+		return val
+	}
+
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Ptr:
+		if rv.IsNil() {
+			return nil
+		}
+		return NormalizeABIValue(rv.Elem().Interface())
+	case reflect.Array:
+		if rv.Type().Elem().Kind() == reflect.Uint8 {
+			b := make([]byte, rv.Len())
+			reflect.Copy(reflect.ValueOf(b), rv)
+			return hexutil.Encode(b)
+		}
+		fallthrough
+	case reflect.Slice:
+		out := make([]interface{}, rv.Len())
+		for i := 0; i < rv.Len(); i++ {
+			out[i] = NormalizeABIValue(rv.Index(i).Interface())
+		}
+		return out
+	case reflect.Struct:
+		out := make(map[string]interface{}, rv.NumField())
+		for i := 0; i < rv.NumField(); i++ {
+			field := rv.Type().Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			out[field.Name] = NormalizeABIValue(rv.Field(i).Interface())
+		}
+		return out
+	}
+	return v
+}
+
+func containsFold(list []string, s string) bool {
+	for _, item := range list {
+		if strings.EqualFold(item, s) {
+			return true
+		}
+	}
+	return false
+}
+
+func lookupFold(m map[string]interface{}, key string) (interface{}, bool) {
+	for k, v := range m {
+		if strings.EqualFold(k, key) {
+			return v, true
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,118 @@
+package contracts
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+)
+
+const testEventABI = `[
+	{
+		"type": "event",
+		"name": "OperatorRegistered",
+		"anonymous": false,
+		"inputs": [
+			{"name": "operator", "type": "address", "indexed": true},
+			{"name": "operatorSetId", "type": "uint32", "indexed": true},
+			{"name": "weight", "type": "uint256", "indexed": false}
+		]
+	}
+]`
+
+func buildTestLog(t *testing.T, parsed abi.ABI, addr, operator common.Address, opSetId uint32, weight int64) types.Log {
+	event := parsed.Events["OperatorRegistered"]
+	data, err := event.Inputs.NonIndexed().Pack(big.NewInt(weight))
+	require.NoError(t, err)
+
+	return types.Log{
+		Address: addr,
+		Topics: []common.Hash{
+			event.ID,
+			common.BytesToHash(operator.Bytes()),
+			common.BigToHash(big.NewInt(int64(opSetId))),
+		},
+		Data:        data,
+		BlockNumber: 42,
+		TxHash:      common.HexToHash("0x01"),
+		Index:       3,
+	}
+}
+
+func TestEventDecoder_Decode(t *testing.T) {
+	parsed, err := abi.JSON(strings.NewReader(testEventABI))
+	require.NoError(t, err)
+
+	addr := common.HexToAddress("0x1000000000000000000000000000000000000001")
+	operator := common.HexToAddress("0x2000000000000000000000000000000000000002")
+
+	decoder := NewEventDecoder()
+	decoder.AddContract("TaskAVSRegistrar", addr, &parsed)
+
+	ev, err := decoder.Decode(buildTestLog(t, parsed, addr, operator, 7, 1000))
+	require.NoError(t, err)
+	require.Equal(t, "TaskAVSRegistrar", ev.Contract)
+	require.Equal(t, "OperatorRegistered", ev.Event)
+	require.Equal(t, uint64(42), ev.BlockNumber)
+	require.Equal(t, operator.Hex(), ev.Args["operator"])
+	require.Equal(t, uint32(7), ev.Args["operatorSetId"])
+	require.Equal(t, "1000", ev.Args["weight"])
+	require.ElementsMatch(t, []string{"operator", "operatorSetId"}, ev.Indexed)
+	require.Contains(t, ev.String(), "TaskAVSRegistrar.OperatorRegistered(")
+}
+
+func TestEventDecoder_UnknownLogs(t *testing.T) {
+	parsed, err := abi.JSON(strings.NewReader(testEventABI))
+	require.NoError(t, err)
+
+	addr := common.HexToAddress("0x1000000000000000000000000000000000000001")
+	decoder := NewEventDecoder()
+	decoder.AddContract("TaskAVSRegistrar", addr, &parsed)
+
+	// Unregistered address
+	log := buildTestLog(t, parsed, common.HexToAddress("0xdead"), addr, 1, 1)
+	_, err = decoder.Decode(log)
+	require.ErrorIs(t, err, ErrUnknownEvent)
+
+	// Unknown topic on a registered address
+	log = buildTestLog(t, parsed, addr, addr, 1, 1)
+	log.Topics[0] = common.HexToHash("0xbeef")
+	_, err = decoder.Decode(log)
+	require.ErrorIs(t, err, ErrUnknownEvent)
+}
+
+func TestEventFilter_Matches(t *testing.T) {
+	ev := &DecodedEvent{
+		Contract: "AllocationManager",
+		Event:    "OperatorSetCreated",
+		Args: map[string]interface{}{
+			"avs":    "0xAbC0000000000000000000000000000000000001",
+			"amount": "5",
+		},
+		Indexed: []string{"avs"},
+	}
+
+	require.True(t, EventFilter{}.Matches(ev))
+	require.True(t, EventFilter{Contracts: []string{"allocationmanager"}}.Matches(ev))
+	require.False(t, EventFilter{Contracts: []string{"KeyRegistrar"}}.Matches(ev))
+	require.True(t, EventFilter{Events: []string{"OperatorSetCreated"}}.Matches(ev))
+	require.False(t, EventFilter{Events: []string{"OperatorAddedToOperatorSet"}}.Matches(ev))
+	require.True(t, EventFilter{Indexed: map[string]string{"avs": "0xabc0000000000000000000000000000000000001"}}.Matches(ev))
+	require.False(t, EventFilter{Indexed: map[string]string{"avs": "0x01"}}.Matches(ev))
+
+	// Non-indexed args cannot be used as filters
+	require.False(t, EventFilter{Indexed: map[string]string{"amount": "5"}}.Matches(ev))
+}
+
+func TestParseIndexedFilters(t *testing.T) {
+	filters, err := ParseIndexedFilters([]string{"operator=0x01", "operatorSetId=0"})
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"operator": "0x01", "operatorSetId": "0"}, filters)
+
+	_, err = ParseIndexedFilters([]string{"operator"})
+	require.Error(t, err)
+}
@@ -5,23 +5,20 @@ const FOUNDRY_IMAGE = "ghcr.io/foundry-rs/foundry:stable"
 const CHAIN_ARGS = "--gas-limit 140000000 --base-fee 0 --gas-price 1000000"
 const FUND_VALUE = "1000000000000000000"
 const DEVNET_CONTEXT = "devnet"
+
+// Network the devnet forks, EigenLayer addresses missing from the context come from its address book
+const DEVNET_NETWORK = "holesky"
+
 const L1 = "l1"
+const L2 = "l2"
 const ANVIL_1_KEY = "0xac0974bec39a17e36ba4a6b4d238ff944bacb478cbed5efcae784d7bf4f2ff80"
 
 // Ref https://github.com/Layr-Labs/eigenlayer-contracts/blob/c08c9e849c27910f36f3ab746f3663a18838067f/src/contracts/core/AllocationManagerStorage.sol#L63
 const ALLOCATION_DELAY_INFO_SLOT = 155
 const CURVE_TYPE_KEY_REGISTRAR_BN254 = 2
 
-// These are fallback EigenLayer deployment addresses when not specified in context
-const ALLOCATION_MANAGER_ADDRESS = "0xFdD5749e11977D60850E06bF5B13221Ad95eb6B4"
-const DELEGATION_MANAGER_ADDRESS = "0x75dfE5B44C2E530568001400D3f704bC8AE350CC"
-const STRATEGY_MANAGER_ADDRESS = "0xdfB5f6CE42aAA7830E94ECFCcAd411beF4d4D5b6"
-const KEY_REGISTRAR_ADDRESS = "0x1C84Bb62fE7791e173014A879C706445fa893BbE"
-const CROSS_CHAIN_REGISTRY_ADDRESS = "0x0022d2014901F2AFBF5610dDFcd26afe2a65Ca6F"
-const BN254_TABLE_CALCULATOR_ADDRESS = "0x033af59c1b030Cc6eEE07B150FD97668497dc74b"
 const MULTICHAIN_PROXY_ADMIN = "0xC5dc0d145a21FDAD791Df8eDC7EbCB5330A3FdB5"
 const EIGEN_CONTRACT_ADDRESS = "0x3B78576F7D6837500bA3De27A60c7f594934027E"
-const RELEASE_MANAGER_ADDRESS = "0x323A9FcB2De80d04B5C4B0F72ee7799100D32F0F"
 
 const ST_ETH_TOKEN_ADDRESS = "0x3F1c547b21f65e10480dE3ad8E19fAAC46C95034"
 const B_EIGEN_TOKEN_ADDRESS = "0x275cCf9Be51f4a6C94aBa6114cdf2a4c45B9cb27"
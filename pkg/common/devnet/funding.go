@@ -11,11 +11,11 @@ import (
 	devkitcommon "github.com/Layr-Labs/devkit-cli/pkg/common"
 	"github.com/Layr-Labs/devkit-cli/pkg/common/contracts"
 	"github.com/Layr-Labs/devkit-cli/pkg/common/iface"
+	"github.com/Layr-Labs/devkit-cli/pkg/common/signer"
 
 	"context"
 
 	"github.com/ethereum/go-ethereum/accounts/abi"
-	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
@@ -46,7 +46,7 @@ var DefaultTokenHolders = map[common.Address]TokenFunding{
 }
 
 // FundStakerWithTokens funds staker with strategy tokens using impersonation
-func FundStakerWithTokens(ctx context.Context, ethClient *ethclient.Client, rpcClient *rpc.Client, stakerAddress common.Address, tokenFunding TokenFunding, tokenAddress common.Address, rpcURL string) error {
+func FundStakerWithTokens(ctx context.Context, ethClient *ethclient.Client, rpcClient *rpc.Client, txManager *devkitcommon.TxManager, stakerAddress common.Address, tokenFunding TokenFunding, tokenAddress common.Address, rpcURL string) error {
 	if tokenFunding.TokenName == "bEIGEN" {
 		// For bEIGEN, we need to call unwrap() on the EIGEN contract first
 		// to convert EIGEN tokens to bEIGEN tokens
@@ -62,8 +62,8 @@ func FundStakerWithTokens(ctx context.Context, ethClient *ethclient.Client, rpcC
 			return fmt.Errorf("failed to impersonate token holder for unwrap: %w", err)
 		}
 
-		// Get gas price
-		gasPrice, err := ethClient.SuggestGasPrice(ctx)
+		// Get fee fields
+		unwrapFields, err := txManager.FeeFields(ctx)
 		if err != nil {
 			return fmt.Errorf("failed to get gas price for unwrap: %w", err)
 		}
@@ -82,7 +82,7 @@ func FundStakerWithTokens(ctx context.Context, ethClient *ethclient.Client, rpcC
 		// if holder balance < 0.1 ether, fund it
 		fundValue, _ := strconv.ParseInt(FUND_VALUE, 10, 64)
 		if balance.Cmp(big.NewInt(fundValue)) < 0 {
-			err = fundIfNeeded(ethClient, tokenFunding.HolderAddress, ANVIL_1_KEY)
+			err = fundIfNeeded(ethClient, txManager, tokenFunding.HolderAddress, anvilFunder())
 			if err != nil {
 				return fmt.Errorf("failed to fund holder address: %w", err)
 			}
@@ -90,20 +90,18 @@ func FundStakerWithTokens(ctx context.Context, ethClient *ethclient.Client, rpcC
 
 		// Send unwrap transaction from impersonated account using RPC for impersonated accounts
 		var unwrapTxHash common.Hash
-		err = rpcClient.Call(&unwrapTxHash, "eth_sendTransaction", map[string]interface{}{
-			"from":     tokenFunding.HolderAddress.Hex(),
-			"to":       EIGEN_CONTRACT_ADDRESS,
-			"gas":      "0x30d40", // 200000 in hex
-			"gasPrice": fmt.Sprintf("0x%x", gasPrice),
-			"value":    "0x0",
-			"data":     fmt.Sprintf("0x%x", unwrapData),
-		})
+		unwrapFields["from"] = tokenFunding.HolderAddress.Hex()
+		unwrapFields["to"] = EIGEN_CONTRACT_ADDRESS
+		unwrapFields["gas"] = "0x30d40" // 200000 in hex
+		unwrapFields["value"] = "0x0"
+		unwrapFields["data"] = fmt.Sprintf("0x%x", unwrapData)
+		err = rpcClient.Call(&unwrapTxHash, "eth_sendTransaction", unwrapFields)
 		if err != nil {
 			return fmt.Errorf("failed to send unwrap transaction: %w", err)
 		}
 
 		// Wait for unwrap transaction receipt
-		unwrapReceipt, err := waitForTransaction(ctx, ethClient, unwrapTxHash)
+		unwrapReceipt, err := txManager.WaitForReceipt(ctx, unwrapTxHash)
 		if err != nil {
 			return fmt.Errorf("unwrap transaction failed: %w", err)
 		}
@@ -130,8 +128,8 @@ func FundStakerWithTokens(ctx context.Context, ethClient *ethclient.Client, rpcC
 		}
 	}()
 
-	// Get gas price
-	gasPrice, err := ethClient.SuggestGasPrice(ctx)
+	// Get fee fields
+	transferFields, err := txManager.FeeFields(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to get gas price: %w", err)
 	}
@@ -144,20 +142,18 @@ func FundStakerWithTokens(ctx context.Context, ethClient *ethclient.Client, rpcC
 
 	// Send token transfer transaction from impersonated account using RPC
 	var txHash common.Hash
-	err = rpcClient.Call(&txHash, "eth_sendTransaction", map[string]interface{}{
-		"from":     tokenFunding.HolderAddress.Hex(),
-		"to":       tokenAddress.Hex(),
-		"gas":      "0x186a0", // 100000 in hex
-		"gasPrice": fmt.Sprintf("0x%x", gasPrice),
-		"value":    "0x0",
-		"data":     fmt.Sprintf("0x%x", transferData),
-	})
+	transferFields["from"] = tokenFunding.HolderAddress.Hex()
+	transferFields["to"] = tokenAddress.Hex()
+	transferFields["gas"] = "0x186a0" // 100000 in hex
+	transferFields["value"] = "0x0"
+	transferFields["data"] = fmt.Sprintf("0x%x", transferData)
+	err = rpcClient.Call(&txHash, "eth_sendTransaction", transferFields)
 	if err != nil {
 		return fmt.Errorf("failed to send token transfer transaction: %w", err)
 	}
 
 	// Wait for transaction receipt
-	receipt, err := waitForTransaction(ctx, ethClient, txHash)
+	receipt, err := txManager.WaitForReceipt(ctx, txHash)
 	if err != nil {
 		return fmt.Errorf("token transfer transaction failed: %w", err)
 	}
@@ -176,7 +172,7 @@ func FundStakerWithTokens(ctx context.Context, ethClient *ethclient.Client, rpcC
 }
 
 // FundStakersWithStrategyTokens funds all stakers with the specified strategy tokens
-func FundStakersWithStrategyTokens(cfg *devkitcommon.ConfigWithContextConfig, rpcURL string, tokenAddresses []string) error {
+func FundStakersWithStrategyTokens(cfg *devkitcommon.ConfigWithContextConfig, rpcURL string, tokenAddresses []string, logger iface.Logger) error {
 	if os.Getenv("SKIP_TOKEN_FUNDING") == "true" {
 		log.Println("🔧 Skipping token funding (test mode)")
 		return nil
@@ -196,6 +192,10 @@ func FundStakersWithStrategyTokens(cfg *devkitcommon.ConfigWithContextConfig, rp
 	defer ethClient.Close()
 
 	ctx := context.Background()
+	txManager, err := newFundingTxManager(ctx, ethClient, cfg, logger)
+	if err != nil {
+		return err
+	}
 
 	// Fund each staker with each requested token
 	for _, staker := range cfg.Context[DEVNET_CONTEXT].Stakers {
@@ -210,7 +210,7 @@ func FundStakersWithStrategyTokens(cfg *devkitcommon.ConfigWithContextConfig, rp
 				continue
 			}
 
-			err := FundStakerWithTokens(ctx, ethClient, rpcClient, stakerAddr, tokenFunding, tokenAddress, rpcURL)
+			err := FundStakerWithTokens(ctx, ethClient, rpcClient, txManager, stakerAddr, tokenFunding, tokenAddress, rpcURL)
 			if err != nil {
 				log.Printf("❌ Failed to fund %s with %s (%s): %v", stakerAddr.Hex(), tokenFunding.TokenName, tokenAddressStr, err)
 				continue
@@ -221,32 +221,33 @@ func FundStakersWithStrategyTokens(cfg *devkitcommon.ConfigWithContextConfig, rp
 	return nil
 }
 
-// waitForTransaction waits for a transaction to be mined
-func waitForTransaction(ctx context.Context, client *ethclient.Client, txHash common.Hash) (*types.Receipt, error) {
-	for {
-		receipt, err := client.TransactionReceipt(ctx, txHash)
-		if err == nil {
-			return receipt, nil
-		}
-
-		// If error is "not found", continue waiting
-		if err.Error() == "not found" {
-			select {
-			case <-ctx.Done():
-				return nil, ctx.Err()
-			default:
-				// Small delay before retrying
-				continue
-			}
-		}
+// newFundingTxManager creates a transaction manager for funding transactions on the connected chain, using the
+// transactions config of the devnet's L1 chain
+func newFundingTxManager(ctx context.Context, ethClient *ethclient.Client, cfg *devkitcommon.ConfigWithContextConfig, logger iface.Logger) (*devkitcommon.TxManager, error) {
+	chainID, err := ethClient.ChainID(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get chain ID: %w", err)
+	}
+	txCfg, err := cfg.Context[DEVNET_CONTEXT].Chains[L1].Transactions.TxManagerConfig()
+	if err != nil {
+		return nil, fmt.Errorf("invalid transactions config for %s: %w", L1, err)
+	}
+	return devkitcommon.NewTxManager(ethClient, chainID, txCfg, logger), nil
+}
 
-		return nil, err
+// anvilFunder returns the signer for the prefunded anvil account used to fund devnet wallets
+func anvilFunder() signer.TxSigner {
+	funder, err := signer.NewPrivateKeySigner(ANVIL_1_KEY)
+	if err != nil {
+		// ANVIL_1_KEY is a constant, failing to parse it is a programming error
+		panic(err)
 	}
+	return funder
 }
 
 // FundWallets sends ETH to a list of addresses
 // Only funds wallets with balance < 0.3 ether.
-func FundWalletsDevnet(cfg *devkitcommon.ConfigWithContextConfig, rpcURL string) error {
+func FundWalletsDevnet(cfg *devkitcommon.ConfigWithContextConfig, rpcURL string, logger iface.Logger) error {
 	if os.Getenv("SKIP_DEVNET_FUNDING") == "true" {
 		log.Println("🔧 Skipping devnet wallet funding (test mode)")
 		return nil
@@ -258,15 +259,27 @@ func FundWalletsDevnet(cfg *devkitcommon.ConfigWithContextConfig, rpcURL string)
 	}
 	defer ethClient.Close()
 
+	txManager, err := newFundingTxManager(context.Background(), ethClient, cfg, logger)
+	if err != nil {
+		return err
+	}
+
 	// All operator keys from [operator]
 	// We only intend to fund for devnet, so hardcoding to `CONTEXT` is fine
 	for _, key := range cfg.Context[DEVNET_CONTEXT].Operators {
+		// Operators using a keystore or remote signer have no raw key, fund their configured address
+		if key.ECDSAKey == "" && key.Address != "" {
+			if err := fundIfNeeded(ethClient, txManager, common.HexToAddress(key.Address), anvilFunder()); err != nil {
+				return err
+			}
+			continue
+		}
 		cleanedKey := strings.TrimPrefix(key.ECDSAKey, "0x")
 		privateKey, err := crypto.HexToECDSA(cleanedKey)
 		if err != nil {
 			log.Fatalf("invalid private key %q: %v", key.ECDSAKey, err)
 		}
-		err = fundIfNeeded(ethClient, crypto.PubkeyToAddress(privateKey.PublicKey), ANVIL_1_KEY)
+		err = fundIfNeeded(ethClient, txManager, crypto.PubkeyToAddress(privateKey.PublicKey), anvilFunder())
 
 		if err != nil {
 			return err
@@ -276,7 +289,7 @@ func FundWalletsDevnet(cfg *devkitcommon.ConfigWithContextConfig, rpcURL string)
 	return nil
 }
 
-func fundIfNeeded(ethClient *ethclient.Client, to common.Address, fromKey string) error {
+func fundIfNeeded(ethClient *ethclient.Client, txManager *devkitcommon.TxManager, to common.Address, funder signer.TxSigner) error {
 	balance, err := ethClient.BalanceAt(context.Background(), to, nil)
 	if err != nil {
 		log.Printf(" Please check if your holesky fork rpc url is up")
@@ -291,18 +304,12 @@ func fundIfNeeded(ethClient *ethclient.Client, to common.Address, fromKey string
 	}
 
 	value, _ := new(big.Int).SetString(FUND_VALUE, 10) // 1 ETH in wei
-	gasPrice, err := ethClient.SuggestGasPrice(context.Background())
+	_, feeCap, _, err := txManager.SuggestFees(context.Background())
 	if err != nil {
 		return fmt.Errorf("failed to get gas price: %w", err)
 	}
 
-	// Get the nonce for the sender
-	fromKey = strings.TrimPrefix(fromKey, "0x")
-	privateKey, err := crypto.HexToECDSA(fromKey)
-	if err != nil {
-		return fmt.Errorf("failed to parse private key: %w", err)
-	}
-	fromAddress := crypto.PubkeyToAddress(privateKey.PublicKey)
+	fromAddress := funder.Address()
 
 	// Check sender's balance
 	senderBalance, err := ethClient.BalanceAt(context.Background(), fromAddress, nil)
@@ -312,53 +319,26 @@ func fundIfNeeded(ethClient *ethclient.Client, to common.Address, fromKey string
 
 	// Calculate total cost (value + gas)
 	gasLimit := uint64(21000)
-	totalCost := new(big.Int).Mul(gasPrice, big.NewInt(int64(gasLimit)))
+	totalCost := new(big.Int).Mul(feeCap, big.NewInt(int64(gasLimit)))
 	totalCost.Add(totalCost, value)
 
 	if senderBalance.Cmp(totalCost) < 0 {
 		return fmt.Errorf("funder has insufficient balance: has %s wei, needs %s wei", senderBalance.String(), totalCost.String())
 	}
 
-	nonce, err := ethClient.PendingNonceAt(context.Background(), fromAddress)
-	if err != nil {
-		return fmt.Errorf("failed to get nonce: %w", err)
-	}
-
-	tx := types.NewTransaction(
-		nonce,
-		to,
-		value,
-		gasLimit,
-		gasPrice,
-		nil, // data
-	)
-
-	// Get chain ID
-	chainID, err := ethClient.ChainID(context.Background())
-	if err != nil {
-		return fmt.Errorf("failed to get chain ID: %w", err)
-	}
-
-	// Sign the transaction with the latest signer
-	signedTx, err := types.SignTx(tx, types.LatestSignerForChainID(chainID), privateKey)
-	if err != nil {
-		return fmt.Errorf("failed to sign transaction: %w", err)
-	}
+	log.Printf("Sending funding transaction, waiting for confirmation...")
 
-	err = ethClient.SendTransaction(context.Background(), signedTx)
+	// Sign with a managed nonce and dynamic fees, then wait for it to be mined
+	signedTx, receipt, err := txManager.SendAndWait(context.Background(), funder, types.NewTx(&types.DynamicFeeTx{
+		To:    &to,
+		Value: value,
+		Gas:   gasLimit,
+	}))
 	if err != nil {
 		log.Printf("Failed to send eth funding transaction: %v", err)
 		return fmt.Errorf("failed to send transaction: %w", err)
 	}
 
-	log.Printf("Transaction sent, waiting for confirmation...")
-
-	// Wait for transaction to be mined using bind.WaitMined
-	receipt, err := bind.WaitMined(context.Background(), ethClient, signedTx)
-	if err != nil {
-		return fmt.Errorf("failed to wait for transaction: %w", err)
-	}
-
 	if receipt.Status == 0 {
 		return fmt.Errorf("transaction failed")
 	}
@@ -369,6 +349,8 @@ func fundIfNeeded(ethClient *ethclient.Client, to common.Address, fromKey string
 
 // GetUnderlyingTokenAddressesFromStrategies extracts all unique underlying token addresses from strategy contracts
 func GetUnderlyingTokenAddressesFromStrategies(cfg *devkitcommon.ConfigWithContextConfig, rpcURL string, logger iface.Logger) ([]string, error) {
+	ctx := context.Background()
+
 	// Connect to ETH client
 	ethClient, err := ethclient.Dial(rpcURL)
 	if err != nil {
@@ -385,7 +367,9 @@ func GetUnderlyingTokenAddressesFromStrategies(cfg *devkitcommon.ConfigWithConte
 
 	// Create a ContractCaller with proper registry
 	contractCaller, err := devkitcommon.NewContractCaller(
+		ctx,
 		context.DeployerPrivateKey,
+		nil,
 		big.NewInt(1), // Chain ID doesn't matter for read operations
 		ethClient,
 		common.HexToAddress(eigenLayer.L1.AllocationManager),
@@ -394,11 +378,13 @@ func GetUnderlyingTokenAddressesFromStrategies(cfg *devkitcommon.ConfigWithConte
 		common.HexToAddress(eigenLayer.L1.KeyRegistrar),
 		common.HexToAddress(""),
 		common.HexToAddress(""),
+		context.Chains[L1].Transactions,
 		logger,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create contract caller: %w", err)
 	}
+	defer contractCaller.Close()
 
 	uniqueTokenAddresses := make(map[string]bool)
 	var tokenAddresses []string
@@ -143,47 +143,18 @@ func GetDevnetForkUrlDefault(cfg *common.ConfigWithContextConfig, chainName stri
 }
 
 // GetEigenLayerAddresses returns EigenLayer L1 addresses from the context config
-// Falls back to constants if not found in context
+// Falls back to the address book of the forked network if not found in context
 func GetEigenLayerAddresses(cfg *common.ConfigWithContextConfig) (allocationManager, delegationManager string, strategyManager string, keyRegistrar string, crossChainRegistry string, bn254TableCalculator string, releaseManager string) {
-	if cfg == nil || cfg.Context == nil {
-		return ALLOCATION_MANAGER_ADDRESS, DELEGATION_MANAGER_ADDRESS, STRATEGY_MANAGER_ADDRESS, KEY_REGISTRAR_ADDRESS, CROSS_CHAIN_REGISTRY_ADDRESS, BN254_TABLE_CALCULATOR_ADDRESS, RELEASE_MANAGER_ADDRESS
+	addresses := common.EigenLayerConfig{}
+	if cfg != nil && cfg.Context != nil {
+		if devnetCtx, found := cfg.Context[DEVNET_CONTEXT]; found && devnetCtx.EigenLayer != nil {
+			addresses = *devnetCtx.EigenLayer
+		}
 	}
-
-	devnetCtx, found := cfg.Context[DEVNET_CONTEXT]
-	if !found || devnetCtx.EigenLayer == nil {
-		return ALLOCATION_MANAGER_ADDRESS, DELEGATION_MANAGER_ADDRESS, STRATEGY_MANAGER_ADDRESS, KEY_REGISTRAR_ADDRESS, CROSS_CHAIN_REGISTRY_ADDRESS, BN254_TABLE_CALCULATOR_ADDRESS, RELEASE_MANAGER_ADDRESS
-	}
-
-	allocationManager = devnetCtx.EigenLayer.L1.AllocationManager
-	if allocationManager == "" {
-		allocationManager = ALLOCATION_MANAGER_ADDRESS
-	}
-
-	delegationManager = devnetCtx.EigenLayer.L1.DelegationManager
-	if delegationManager == "" {
-		delegationManager = DELEGATION_MANAGER_ADDRESS
-	}
-	strategyManager = devnetCtx.EigenLayer.L1.StrategyManager
-	if strategyManager == "" {
-		strategyManager = STRATEGY_MANAGER_ADDRESS
-	}
-	keyRegistrar = devnetCtx.EigenLayer.L1.KeyRegistrar
-	if keyRegistrar == "" {
-		keyRegistrar = KEY_REGISTRAR_ADDRESS
-	}
-
-	crossChainRegistry = devnetCtx.EigenLayer.L1.CrossChainRegistry
-	if crossChainRegistry == "" {
-		crossChainRegistry = CROSS_CHAIN_REGISTRY_ADDRESS
-	}
-	bn254TableCalculator = devnetCtx.EigenLayer.L1.BN254TableCalculator
-	if bn254TableCalculator == "" {
-		bn254TableCalculator = BN254_TABLE_CALCULATOR_ADDRESS
-	}
-	releaseManager = devnetCtx.EigenLayer.L1.ReleaseManager
-	if releaseManager == "" {
-		releaseManager = RELEASE_MANAGER_ADDRESS
+	if book, err := common.LoadNetworkAddressBook(DEVNET_NETWORK); err == nil {
+		common.MergeEigenLayerAddresses(&addresses, &book.EigenLayer)
 	}
 
-	return allocationManager, delegationManager, strategyManager, keyRegistrar, crossChainRegistry, bn254TableCalculator, releaseManager
+	l1 := addresses.L1
+	return l1.AllocationManager, l1.DelegationManager, l1.StrategyManager, l1.KeyRegistrar, l1.CrossChainRegistry, l1.BN254TableCalculator, l1.ReleaseManager
 }
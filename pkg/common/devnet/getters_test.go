@@ -0,0 +1,36 @@
+package devnet
+
+import (
+	"testing"
+
+	"github.com/Layr-Labs/devkit-cli/config/contexts"
+	"github.com/Layr-Labs/devkit-cli/pkg/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+// TestGetEigenLayerAddressesFallback checks the devnet context and the address book it falls back to agree
+func TestGetEigenLayerAddressesFallback(t *testing.T) {
+	var ctx struct {
+		Context common.ChainContextConfig `yaml:"context"`
+	}
+	require.NoError(t, yaml.Unmarshal(contexts.ContextYamls[contexts.LatestVersion], &ctx))
+	book, err := common.LoadNetworkAddressBook(DEVNET_NETWORK)
+	require.NoError(t, err)
+	assert.Equal(t, book.EigenLayer, *ctx.Context.EigenLayer)
+
+	// Without a context every address comes from the address book
+	allocationManager, delegationManager, strategyManager, keyRegistrar, crossChainRegistry, bn254TableCalculator, releaseManager := GetEigenLayerAddresses(nil)
+	l1 := book.EigenLayer.L1
+	assert.Equal(t, []string{l1.AllocationManager, l1.DelegationManager, l1.StrategyManager, l1.KeyRegistrar, l1.CrossChainRegistry, l1.BN254TableCalculator, l1.ReleaseManager},
+		[]string{allocationManager, delegationManager, strategyManager, keyRegistrar, crossChainRegistry, bn254TableCalculator, releaseManager})
+
+	// Context addresses take precedence
+	cfg := &common.ConfigWithContextConfig{Context: map[string]common.ChainContextConfig{
+		DEVNET_CONTEXT: {EigenLayer: &common.EigenLayerConfig{L1: common.EigenLayerL1Config{AllocationManager: "0x1"}}},
+	}}
+	allocationManager, delegationManager, _, _, _, _, _ = GetEigenLayerAddresses(cfg)
+	assert.Equal(t, "0x1", allocationManager)
+	assert.Equal(t, l1.DelegationManager, delegationManager)
+}
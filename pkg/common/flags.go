@@ -1,6 +1,10 @@
 package common
 
-import "github.com/urfave/cli/v2"
+import (
+	"fmt"
+
+	"github.com/urfave/cli/v2"
+)
 
 // GlobalFlags defines flags that apply to the entire application (global flags).
 var GlobalFlags = []cli.Flag{
@@ -18,3 +22,21 @@ var GlobalFlags = []cli.Flag{
 		Usage: "Disable telemetry collection on first run without prompting",
 	},
 }
+
+// SelectedContext returns the context named by --context, given to the command or to one of its parents
+// such as `devkit avs context`, else the project's context from config/config.yaml
+func SelectedContext(cCtx *cli.Context) (string, error) {
+	for _, c := range cCtx.Lineage() {
+		if name := c.String("context"); name != "" {
+			return name, nil
+		}
+	}
+	cfg, err := LoadBaseConfigYaml()
+	if err != nil {
+		return "", fmt.Errorf("no context selected, pass --context: %w", err)
+	}
+	if cfg.Config.Project.Context == "" {
+		return "", fmt.Errorf("no context selected, pass --context or set project.context in config/config.yaml")
+	}
+	return cfg.Config.Project.Context, nil
+}
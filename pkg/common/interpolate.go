@@ -0,0 +1,83 @@
+package common
+
+import (
+	"os"
+	"regexp"
+
+	"gopkg.in/yaml.v3"
+)
+
+// envReference matches ${VAR} and ${VAR:-default}, and the escaped $${VAR}
+var envReference = regexp.MustCompile(`\$(\$)?\{([A-Za-z_][A-Za-z0-9_]*)(:-([^}]*))?\}`)
+
+// ExpandEnv replaces ${VAR} with the value of VAR, and ${VAR:-default} with default when VAR is unset or empty.
+// $${VAR} is left as the literal ${VAR}.
+func ExpandEnv(s string) string {
+	return envReference.ReplaceAllStringFunc(s, func(ref string) string {
+		match := envReference.FindStringSubmatch(ref)
+		if match[1] != "" {
+			return ref[1:]
+		}
+		if value := os.Getenv(match[2]); value != "" || match[3] == "" {
+			return value
+		}
+		return match[4]
+	})
+}
+
+// UnresolvedEnv returns the variables s references that are unset or empty and have no default, which ExpandEnv
+// replaces with an empty string
+func UnresolvedEnv(s string) []string {
+	var names []string
+	for _, match := range envReference.FindAllStringSubmatch(s, -1) {
+		if match[1] == "" && match[3] == "" && os.Getenv(match[2]) == "" {
+			names = append(names, match[2])
+		}
+	}
+	return names
+}
+
+// HasEnvReference reports whether s holds a ${VAR} reference
+func HasEnvReference(s string) bool {
+	return envReference.MatchString(s)
+}
+
+// ExpandEnvNode returns a copy of node with environment references expanded in every scalar. The node itself keeps
+// its template text, so it can still be written back. Unquoted scalars are re-resolved after expansion, letting
+// `chain_id: ${CHAIN_ID}` decode as an integer.
+func ExpandEnvNode(node *yaml.Node) *yaml.Node {
+	if node == nil {
+		return nil
+	}
+	expanded := CloneNode(node)
+	expandEnvInPlace(expanded)
+	return expanded
+}
+
+func expandEnvInPlace(node *yaml.Node) {
+	if node.Kind == yaml.ScalarNode {
+		if !envReference.MatchString(node.Value) {
+			return
+		}
+		node.Value = ExpandEnv(node.Value)
+		if node.Style&(yaml.DoubleQuotedStyle|yaml.SingleQuotedStyle|yaml.LiteralStyle|yaml.FoldedStyle) == 0 {
+			node.Tag = ""
+		}
+		return
+	}
+	for _, child := range node.Content {
+		expandEnvInPlace(child)
+	}
+}
+
+// unmarshalExpanded decodes yaml into out after expanding environment references
+func unmarshalExpanded(data []byte, out interface{}) error {
+	var node yaml.Node
+	if err := yaml.Unmarshal(data, &node); err != nil {
+		return err
+	}
+	if len(node.Content) == 0 {
+		return nil
+	}
+	return ExpandEnvNode(&node).Decode(out)
+}
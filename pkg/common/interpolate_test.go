@@ -0,0 +1,106 @@
+package common
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestExpandEnv(t *testing.T) {
+	t.Setenv("DEVKIT_TEST_RPC", "http://rpc.example:8545")
+	t.Setenv("DEVKIT_TEST_EMPTY", "")
+
+	tests := []struct {
+		in, want string
+	}{
+		{"${DEVKIT_TEST_RPC}", "http://rpc.example:8545"},
+		{"url=${DEVKIT_TEST_RPC}/v1", "url=http://rpc.example:8545/v1"},
+		{"${DEVKIT_TEST_UNSET}", ""},
+		{"${DEVKIT_TEST_UNSET:-http://localhost:8545}", "http://localhost:8545"},
+		{"${DEVKIT_TEST_EMPTY:-fallback}", "fallback"},
+		{"${DEVKIT_TEST_RPC:-fallback}", "http://rpc.example:8545"},
+		{"$${DEVKIT_TEST_RPC}", "${DEVKIT_TEST_RPC}"},
+		{"pa$$word $HOME", "pa$$word $HOME"},
+	}
+	for _, tt := range tests {
+		if got := ExpandEnv(tt.in); got != tt.want {
+			t.Errorf("ExpandEnv(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestUnresolvedEnv(t *testing.T) {
+	t.Setenv("DEVKIT_TEST_RPC", "http://rpc.example:8545")
+	t.Setenv("DEVKIT_TEST_EMPTY", "")
+
+	got := UnresolvedEnv("${DEVKIT_TEST_RPC} ${DEVKIT_TEST_UNSET} ${DEVKIT_TEST_EMPTY} ${DEVKIT_TEST_UNSET:-x} $${DEVKIT_TEST_UNSET}")
+	if strings.Join(got, ",") != "DEVKIT_TEST_UNSET,DEVKIT_TEST_EMPTY" {
+		t.Errorf("UnresolvedEnv = %v, want [DEVKIT_TEST_UNSET DEVKIT_TEST_EMPTY]", got)
+	}
+}
+
+func TestExpandEnvNode(t *testing.T) {
+	t.Setenv("DEVKIT_TEST_CHAIN_ID", "17000")
+
+	var node yaml.Node
+	if err := yaml.Unmarshal([]byte("chain_id: ${DEVKIT_TEST_CHAIN_ID}\nname: \"${DEVKIT_TEST_CHAIN_ID}\"\n"), &node); err != nil {
+		t.Fatal(err)
+	}
+
+	var out struct {
+		ChainID int    `yaml:"chain_id"`
+		Name    string `yaml:"name"`
+	}
+	if err := ExpandEnvNode(&node).Decode(&out); err != nil {
+		t.Fatalf("decode failed: %v", err)
+	}
+	if out.ChainID != 17000 || out.Name != "17000" {
+		t.Errorf("unexpected expansion: %+v", out)
+	}
+	if v := GetChildByKey(node.Content[0], "chain_id").Value; v != "${DEVKIT_TEST_CHAIN_ID}" {
+		t.Errorf("original node should keep its template text, got %q", v)
+	}
+}
+
+func TestLoadConfigExpandsEnvAndWritesTemplate(t *testing.T) {
+	t.Setenv("DEVKIT_TEST_RPC", "http://rpc.example:8545")
+	context := "version: 0.0.8\ncontext:\n  name: devnet\n  chains:\n    l1:\n      chain_id: ${DEVKIT_TEST_CHAIN_ID:-31337}\n      rpc_url: \"${DEVKIT_TEST_RPC}\"\n"
+	dir := writeContextFiles(t, map[string]string{"devnet.yaml": context})
+	if err := os.WriteFile(filepath.Join("config", BaseConfig), []byte("version: 0.0.2\nconfig:\n  project:\n    name: ${DEVKIT_TEST_NAME:-my-avs}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := LoadConfigWithContextConfig("devnet")
+	if err != nil {
+		t.Fatalf("LoadConfigWithContextConfig failed: %v", err)
+	}
+	if cfg.Config.Project.Name != "my-avs" {
+		t.Errorf("expected the default project name, got %q", cfg.Config.Project.Name)
+	}
+	l1 := cfg.Context["devnet"].Chains["l1"]
+	if l1.ChainID != 31337 || l1.RPCURL != "http://rpc.example:8545" {
+		t.Errorf("unexpected l1 chain: %+v", l1)
+	}
+
+	// Writing the context back keeps the references
+	yamlPath, rootNode, contextNode, err := LoadContext("devnet")
+	if err != nil {
+		t.Fatal(err)
+	}
+	GetChildByKey(contextNode, "name").Value = "devnet-2"
+	if err := WriteContext(yamlPath, rootNode); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "devnet.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"${DEVKIT_TEST_CHAIN_ID:-31337}", "\"${DEVKIT_TEST_RPC}\""} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("expected %q to be preserved, got:\n%s", want, data)
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package common
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Layr-Labs/devkit-cli/config/networks"
+	"gopkg.in/yaml.v3"
+)
+
+// NetworkAddressBook holds the EigenLayer core addresses of a network
+type NetworkAddressBook struct {
+	Version    string           `yaml:"version"`
+	Network    string           `yaml:"network"`
+	EigenLayer EigenLayerConfig `yaml:"eigenlayer"`
+}
+
+// LoadNetworkAddressBook returns the embedded address book of network
+func LoadNetworkAddressBook(network string) (*NetworkAddressBook, error) {
+	data, ok := networks.AddressBooks[network]
+	if !ok {
+		return nil, fmt.Errorf("unknown network %q, available networks: %s", network, strings.Join(networks.Names[:], ", "))
+	}
+
+	var book NetworkAddressBook
+	if err := yaml.Unmarshal(data, &book); err != nil {
+		return nil, fmt.Errorf("failed to parse address book for %s: %w", network, err)
+	}
+	return &book, nil
+}
+
+// eigenLayerField is an address of an EigenLayerConfig with its key under context.eigenlayer
+type eigenLayerField struct {
+	chain, key string
+	value      *string
+}
+
+func eigenLayerFields(addresses *EigenLayerConfig) []eigenLayerField {
+	return []eigenLayerField{
+		{"l1", "allocation_manager", &addresses.L1.AllocationManager},
+		{"l1", "delegation_manager", &addresses.L1.DelegationManager},
+		{"l1", "strategy_manager", &addresses.L1.StrategyManager},
+		{"l1", "bn254_table_calculator", &addresses.L1.BN254TableCalculator},
+		{"l1", "cross_chain_registry", &addresses.L1.CrossChainRegistry},
+		{"l1", "key_registrar", &addresses.L1.KeyRegistrar},
+		{"l1", "release_manager", &addresses.L1.ReleaseManager},
+		{"l2", "bn254_certificate_verifier", &addresses.L2.BN254CertificateVerifier},
+		{"l2", "operator_table_updater", &addresses.L2.OperatorTableUpdater},
+	}
+}
+
+// MergeEigenLayerAddresses fills the empty addresses of dst from src
+func MergeEigenLayerAddresses(dst, src *EigenLayerConfig) {
+	srcFields := eigenLayerFields(src)
+	for i, field := range eigenLayerFields(dst) {
+		if *field.value == "" {
+			*field.value = *srcFields[i].value
+		}
+	}
+}
+
+// UpdateContextWithEigenLayerAddresses writes addresses into context.eigenlayer, leaving entries without an address
+// untouched. It returns the entries that had no address, as eigenlayer.<chain>.<key>.
+func UpdateContextWithEigenLayerAddresses(ctx *yaml.Node, addresses *EigenLayerConfig) []string {
+	eigenlayer := getOrCreateMapping(ctx, "eigenlayer")
+
+	var missing []string
+	for _, field := range eigenLayerFields(addresses) {
+		if *field.value == "" {
+			missing = append(missing, fmt.Sprintf("eigenlayer.%s.%s", field.chain, field.key))
+			continue
+		}
+		chain := getOrCreateMapping(eigenlayer, field.chain)
+		// Keep the comments of existing entries
+		if existing := GetChildByKey(chain, field.key); existing != nil && existing.Kind == yaml.ScalarNode {
+			existing.Value, existing.Tag = *field.value, "!!str"
+			continue
+		}
+		SetMappingValue(chain,
+			&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: field.key},
+			&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: *field.value, Style: yaml.DoubleQuotedStyle},
+		)
+	}
+	return missing
+}
+
+// getOrCreateMapping returns the mapping under key, adding an empty one when it is missing
+func getOrCreateMapping(parent *yaml.Node, key string) *yaml.Node {
+	if child := GetChildByKey(parent, key); child != nil && child.Kind == yaml.MappingNode {
+		return child
+	}
+	child := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	SetMappingValue(parent, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}, child)
+	return child
+}
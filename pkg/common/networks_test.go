@@ -0,0 +1,86 @@
+package common
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Layr-Labs/devkit-cli/config/networks"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"gopkg.in/yaml.v3"
+)
+
+func TestNetworkAddressBooks(t *testing.T) {
+	for _, network := range networks.Names {
+		book, err := LoadNetworkAddressBook(network)
+		if err != nil {
+			t.Fatalf("%s: %v", network, err)
+		}
+		if book.Version != networks.LatestVersion || book.Network != network {
+			t.Errorf("%s: got version %q network %q", network, book.Version, book.Network)
+		}
+		for _, field := range eigenLayerFields(&book.EigenLayer) {
+			if address := *field.value; address != "" && ethcommon.HexToAddress(address).Hex() != address {
+				t.Errorf("%s: %s.%s %q is not a checksummed address", network, field.chain, field.key, address)
+			}
+		}
+	}
+
+	if _, err := LoadNetworkAddressBook("goerli"); err == nil {
+		t.Error("expected an error for an unknown network")
+	}
+}
+
+func TestReadZeusAddresses(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "zeus.json")
+	zeus := `{
+  "ZEUS_ENV": "testnet-hoodi",
+  "ZEUS_DEPLOYED_AllocationManager_Proxy": "0x0000000000000000000000000000000000000001",
+  "ZEUS_DEPLOYED_AllocationManager_Impl": "0x0000000000000000000000000000000000000002",
+  "ZEUS_DEPLOYED_KeyRegistrar": "0x0000000000000000000000000000000000000003"
+}`
+	if err := os.WriteFile(path, []byte(zeus), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	addresses, err := ReadZeusAddresses(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if addresses.L1.AllocationManager != "0x0000000000000000000000000000000000000001" {
+		t.Errorf("AllocationManager = %q, want the proxy", addresses.L1.AllocationManager)
+	}
+	if addresses.L1.KeyRegistrar != "0x0000000000000000000000000000000000000003" {
+		t.Errorf("KeyRegistrar = %q", addresses.L1.KeyRegistrar)
+	}
+
+	// Addresses missing from the export are left alone in the context
+	var doc yaml.Node
+	if err := yaml.Unmarshal([]byte("eigenlayer:\n  l1:\n    delegation_manager: \"0xabc\" # kept\n"), &doc); err != nil {
+		t.Fatal(err)
+	}
+	missing := UpdateContextWithEigenLayerAddresses(doc.Content[0], addresses)
+	if len(missing) != 7 {
+		t.Errorf("missing = %v, want 7 entries", missing)
+	}
+	out, err := yaml.Marshal(&doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `eigenlayer:
+    l1:
+        delegation_manager: "0xabc" # kept
+        allocation_manager: "0x0000000000000000000000000000000000000001"
+        key_registrar: "0x0000000000000000000000000000000000000003"
+`
+	if string(out) != want {
+		t.Errorf("context =\n%s\nwant\n%s", out, want)
+	}
+
+	if err := os.WriteFile(path, []byte(`{"ZEUS_ENV": "mainnet"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ReadZeusAddresses(path); err == nil {
+		t.Error("expected an error for an export without addresses")
+	}
+}
@@ -0,0 +1,264 @@
+package common
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+
+	"github.com/Layr-Labs/devkit-cli/pkg/common/contracts"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+var (
+	// Error(string) selector used by require/revert with a message
+	revertErrorSelector = []byte{0x08, 0xc3, 0x79, 0xa0}
+	// Panic(uint256) selector used by assert, overflow and similar failures
+	revertPanicSelector = []byte{0x4e, 0x48, 0x7b, 0x71}
+)
+
+// RevertError describes why a transaction reverted, decoded against the known contract ABIs
+type RevertError struct {
+	TxHash    string
+	ErrorName string
+	Args      map[string]interface{}
+	Data      []byte
+	Trace     *CallFrame
+}
+
+func (e *RevertError) Error() string {
+	var sb strings.Builder
+	if e.TxHash != "" {
+		sb.WriteString(fmt.Sprintf("transaction (hash: %s) reverted", e.TxHash))
+	} else {
+		sb.WriteString("execution reverted")
+	}
+	switch {
+	case e.ErrorName != "":
+		sb.WriteString(": ")
+		sb.WriteString(formatDecodedError(e.ErrorName, e.Args))
+	case len(e.Data) > 0:
+		sb.WriteString(fmt.Sprintf(": unknown error data %s", hexutil.Encode(e.Data)))
+	}
+	return sb.String()
+}
+
+// CallFrame is a node of the call tree returned by debug_traceTransaction with the callTracer
+type CallFrame struct {
+	Type         string      `json:"type"`
+	From         string      `json:"from"`
+	To           string      `json:"to"`
+	Input        string      `json:"input"`
+	Output       string      `json:"output,omitempty"`
+	Error        string      `json:"error,omitempty"`
+	RevertReason string      `json:"revertReason,omitempty"`
+	Calls        []CallFrame `json:"calls,omitempty"`
+}
+
+// Format renders the call tree with one indented line per call, decoding revert outputs where possible
+func (f *CallFrame) Format(abis []*abi.ABI) string {
+	var sb strings.Builder
+	f.format(&sb, abis, 0)
+	return strings.TrimRight(sb.String(), "\n")
+}
+
+func (f *CallFrame) format(sb *strings.Builder, abis []*abi.ABI, depth int) {
+	selector := f.Input
+	if len(selector) > 10 {
+		selector = selector[:10]
+	}
+	sb.WriteString(fmt.Sprintf("%s%s %s -> %s [%s]", strings.Repeat("  ", depth), f.Type, f.From, f.To, selector))
+	if f.Error != "" {
+		sb.WriteString(" ✗ ")
+		sb.WriteString(f.Error)
+		if f.RevertReason != "" {
+			sb.WriteString(": ")
+			sb.WriteString(f.RevertReason)
+		} else if out, err := hexutil.Decode(f.Output); err == nil && len(out) > 0 {
+			if name, args, ok := DecodeRevertData(out, abis...); ok {
+				sb.WriteString(": ")
+				sb.WriteString(formatDecodedError(name, args))
+			}
+		}
+	}
+	sb.WriteString("\n")
+	for i := range f.Calls {
+		f.Calls[i].format(sb, abis, depth+1)
+	}
+}
+
+// DecodeRevertData decodes revert data as Error(string), Panic(uint256) or a custom error from the given ABIs
+func DecodeRevertData(data []byte, abis ...*abi.ABI) (string, map[string]interface{}, bool) {
+	if len(data) < 4 {
+		return "", nil, false
+	}
+	selector := data[:4]
+
+	if bytes.Equal(selector, revertErrorSelector) {
+		reason, err := abi.UnpackRevert(data)
+		if err != nil {
+			return "", nil, false
+		}
+		return "Error", map[string]interface{}{"reason": reason}, true
+	}
+	if bytes.Equal(selector, revertPanicSelector) && len(data) >= 36 {
+		code := new(big.Int).SetBytes(data[4:36])
+		return "Panic", map[string]interface{}{"code": fmt.Sprintf("0x%x", code)}, true
+	}
+
+	for _, contractABI := range abis {
+		if contractABI == nil {
+			continue
+		}
+		for _, abiErr := range contractABI.Errors {
+			if !bytes.Equal(abiErr.ID[:4], selector) {
+				continue
+			}
+			args := make(map[string]interface{})
+			if len(abiErr.Inputs) > 0 {
+				values, err := abiErr.Inputs.Unpack(data[4:])
+				if err != nil {
+					return abiErr.Name, nil, true
+				}
+				for i, input := range abiErr.Inputs {
+					name := input.Name
+					if name == "" {
+						name = fmt.Sprintf("arg%d", i)
+					}
+					args[name] = contracts.NormalizeABIValue(values[i])
+				}
+			}
+			return abiErr.Name, args, true
+		}
+	}
+	return "", nil, false
+}
+
+// RevertDataFromError extracts revert data carried by a JSON-RPC error, if any
+func RevertDataFromError(err error) ([]byte, bool) {
+	var dataErr rpc.DataError
+	if !errors.As(err, &dataErr) {
+		return nil, false
+	}
+	switch data := dataErr.ErrorData().(type) {
+	case string:
+		b, decodeErr := hexutil.Decode(data)
+		if decodeErr != nil {
+			return nil, false
+		}
+		return b, true
+	case []byte:
+		return data, true
+	}
+	return nil, false
+}
+
+// knownErrorABIs returns the binding ABIs whose custom errors are used to decode reverts
+func knownErrorABIs() []*abi.ABI {
+	var abis []*abi.ABI
+	for _, contractType := range append(contracts.BindingContractTypes(), contracts.ERC20Contract) {
+		parsed, err := contracts.GetBindingABI(contractType)
+		if err != nil {
+			continue
+		}
+		abis = append(abis, parsed)
+	}
+	return abis
+}
+
+// decodeRevertFromError wraps a failed call/estimate error with its decoded revert reason when available
+func decodeRevertFromError(err error) error {
+	data, ok := RevertDataFromError(err)
+	if !ok || len(data) == 0 {
+		return err
+	}
+	revertErr := &RevertError{Data: data}
+	if name, args, ok := DecodeRevertData(data, knownErrorABIs()...); ok {
+		revertErr.ErrorName = name
+		revertErr.Args = args
+	}
+	return fmt.Errorf("%w (%v)", revertErr, err)
+}
+
+// diagnoseRevert replays a reverted transaction on the state before its block to recover the revert data, and
+// fetches a call trace when trace_reverts is set and the node supports it
+func (cc *ContractCaller) diagnoseRevert(ctx context.Context, tx *types.Transaction, receipt *types.Receipt) *RevertError {
+	revertErr := &RevertError{TxHash: tx.Hash().Hex()}
+	abis := knownErrorABIs()
+	setData := func(data []byte) {
+		revertErr.Data = data
+		if name, args, ok := DecodeRevertData(data, abis...); ok {
+			revertErr.ErrorName = name
+			revertErr.Args = args
+		}
+	}
+
+	from, err := types.Sender(types.LatestSignerForChainID(tx.ChainId()), tx)
+	if err != nil {
+		cc.logger.Debug("Unable to recover sender of %s: %v", tx.Hash().Hex(), err)
+	} else {
+		msg := ethereum.CallMsg{
+			From:  from,
+			To:    tx.To(),
+			Gas:   tx.Gas(),
+			Value: tx.Value(),
+			Data:  tx.Data(),
+		}
+		_, callErr := cc.ethclient.CallContract(ctx, msg, replayBlock(receipt))
+		if data, ok := RevertDataFromError(callErr); ok {
+			setData(data)
+		} else if callErr != nil {
+			cc.logger.Debug("Replay of %s did not return revert data: %v", tx.Hash().Hex(), callErr)
+		}
+	}
+
+	if cc.txManager == nil || !cc.txManager.Config().TraceReverts {
+		return revertErr
+	}
+	var trace CallFrame
+	err = cc.ethclient.Client().CallContext(ctx, &trace, "debug_traceTransaction", tx.Hash(), map[string]interface{}{
+		"tracer": "callTracer",
+	})
+	if err != nil {
+		cc.logger.Debug("debug_traceTransaction unavailable for %s: %v", tx.Hash().Hex(), err)
+		return revertErr
+	}
+	revertErr.Trace = &trace
+	cc.logger.Debug("Call trace for %s:\n%s", tx.Hash().Hex(), trace.Format(abis))
+
+	// The replay can miss the revert when earlier txs in the block changed the state, the trace has the real output
+	if len(revertErr.Data) == 0 && trace.Output != "" {
+		if data, err := hexutil.Decode(trace.Output); err == nil && len(data) > 0 {
+			setData(data)
+		}
+	}
+	return revertErr
+}
+
+// replayBlock returns the block whose state a tx executed on, the one before its inclusion block
+func replayBlock(receipt *types.Receipt) *big.Int {
+	if receipt.BlockNumber == nil || receipt.BlockNumber.Sign() == 0 {
+		return receipt.BlockNumber
+	}
+	return new(big.Int).Sub(receipt.BlockNumber, big.NewInt(1))
+}
+
+func formatDecodedError(name string, args map[string]interface{}) string {
+	keys := make([]string, 0, len(args))
+	for k := range args {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	parts := make([]string, 0, len(keys))
+	for _, k := range keys {
+		parts = append(parts, fmt.Sprintf("%s=%v", k, args[k]))
+	}
+	return fmt.Sprintf("%s(%s)", name, strings.Join(parts, ", "))
+}
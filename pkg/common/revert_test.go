@@ -0,0 +1,131 @@
+package common
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+)
+
+const testErrorsABI = `[
+	{"type": "error", "name": "InvalidOperatorSet", "inputs": []},
+	{"type": "error", "name": "NotMemberOfSet", "inputs": [
+		{"name": "operator", "type": "address"},
+		{"name": "operatorSetId", "type": "uint32"}
+	]}
+]`
+
+type testDataError struct {
+	data interface{}
+}
+
+func (e testDataError) Error() string          { return "execution reverted" }
+func (e testDataError) ErrorData() interface{} { return e.data }
+
+func TestDecodeRevertData_CustomErrors(t *testing.T) {
+	parsed, err := abi.JSON(strings.NewReader(testErrorsABI))
+	require.NoError(t, err)
+
+	// Error without arguments
+	data := parsed.Errors["InvalidOperatorSet"].ID.Bytes()[:4]
+	name, args, ok := DecodeRevertData(data, &parsed)
+	require.True(t, ok)
+	require.Equal(t, "InvalidOperatorSet", name)
+	require.Empty(t, args)
+
+	// Error with arguments
+	notMember := parsed.Errors["NotMemberOfSet"]
+	operator := common.HexToAddress("0x1000000000000000000000000000000000000001")
+	packed, err := notMember.Inputs.Pack(operator, uint32(3))
+	require.NoError(t, err)
+	data = append(append([]byte{}, notMember.ID.Bytes()[:4]...), packed...)
+
+	name, args, ok = DecodeRevertData(data, &parsed)
+	require.True(t, ok)
+	require.Equal(t, "NotMemberOfSet", name)
+	require.Equal(t, operator.Hex(), args["operator"])
+	require.Equal(t, uint32(3), args["operatorSetId"])
+
+	// Unknown selector
+	_, _, ok = DecodeRevertData([]byte{0xde, 0xad, 0xbe, 0xef}, &parsed)
+	require.False(t, ok)
+}
+
+func TestDecodeRevertData_BuiltinErrors(t *testing.T) {
+	stringTy, err := abi.NewType("string", "", nil)
+	require.NoError(t, err)
+	packed, err := abi.Arguments{{Type: stringTy}}.Pack("insufficient balance")
+	require.NoError(t, err)
+
+	name, args, ok := DecodeRevertData(append(append([]byte{}, revertErrorSelector...), packed...))
+	require.True(t, ok)
+	require.Equal(t, "Error", name)
+	require.Equal(t, "insufficient balance", args["reason"])
+
+	panicData := append(append([]byte{}, revertPanicSelector...), common.BigToHash(big.NewInt(0x11)).Bytes()...)
+	name, args, ok = DecodeRevertData(panicData)
+	require.True(t, ok)
+	require.Equal(t, "Panic", name)
+	require.Equal(t, "0x11", args["code"])
+}
+
+func TestRevertDataFromError(t *testing.T) {
+	data, ok := RevertDataFromError(testDataError{data: "0xdeadbeef"})
+	require.True(t, ok)
+	require.Equal(t, []byte{0xde, 0xad, 0xbe, 0xef}, data)
+
+	_, ok = RevertDataFromError(testDataError{data: nil})
+	require.False(t, ok)
+}
+
+func TestRevertErrorMessage(t *testing.T) {
+	err := &RevertError{
+		TxHash:    "0xabc",
+		ErrorName: "NotMemberOfSet",
+		Args:      map[string]interface{}{"operatorSetId": uint32(3), "operator": "0x01"},
+	}
+	require.Equal(t, "transaction (hash: 0xabc) reverted: NotMemberOfSet(operator=0x01, operatorSetId=3)", err.Error())
+
+	err = &RevertError{TxHash: "0xabc", Data: []byte{0xde, 0xad, 0xbe, 0xef}}
+	require.Equal(t, "transaction (hash: 0xabc) reverted: unknown error data 0xdeadbeef", err.Error())
+}
+
+func TestCallFrameFormat(t *testing.T) {
+	parsed, err := abi.JSON(strings.NewReader(testErrorsABI))
+	require.NoError(t, err)
+
+	frame := CallFrame{
+		Type:  "CALL",
+		From:  "0x01",
+		To:    "0x02",
+		Input: "0x12345678abcdef",
+		Error: "execution reverted",
+		Calls: []CallFrame{
+			{
+				Type:   "DELEGATECALL",
+				From:   "0x02",
+				To:     "0x03",
+				Input:  "0x12345678",
+				Output: hexutil.Encode(parsed.Errors["InvalidOperatorSet"].ID.Bytes()[:4]),
+				Error:  "execution reverted",
+			},
+		},
+	}
+
+	out := frame.Format([]*abi.ABI{&parsed})
+	lines := strings.Split(out, "\n")
+	require.Len(t, lines, 2)
+	require.Equal(t, "CALL 0x01 -> 0x02 [0x12345678] ✗ execution reverted", lines[0])
+	require.Equal(t, "  DELEGATECALL 0x02 -> 0x03 [0x12345678] ✗ execution reverted: InvalidOperatorSet()", lines[1])
+}
+
+func TestReplayBlock(t *testing.T) {
+	// Replays run on the state before the inclusion block
+	require.Equal(t, int64(99), replayBlock(&types.Receipt{BlockNumber: big.NewInt(100)}).Int64())
+	require.Equal(t, int64(0), replayBlock(&types.Receipt{BlockNumber: big.NewInt(0)}).Int64())
+}
@@ -0,0 +1,353 @@
+package common
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// JSONSchemaDraft is the JSON Schema dialect of generated schemas
+const JSONSchemaDraft = "https://json-schema.org/draft/2020-12/schema"
+
+// JSONSchema is the subset of JSON Schema generated for config and context files
+type JSONSchema struct {
+	Schema      string                 `json:"$schema,omitempty"`
+	Title       string                 `json:"title,omitempty"`
+	Type        string                 `json:"type,omitempty"`
+	Properties  map[string]*JSONSchema `json:"properties,omitempty"`
+	Required    []string               `json:"required,omitempty"`
+	Items       *JSONSchema            `json:"items,omitempty"`
+	Description string                 `json:"description,omitempty"`
+	// false for objects with a fixed set of fields, or the schema of every value of a map
+	AdditionalProperties interface{} `json:"additionalProperties,omitempty"`
+}
+
+// SchemaError is a violation of a schema, located at the yaml node that causes it
+type SchemaError struct {
+	Path    string
+	Line    int
+	Column  int
+	Message string
+	// Node that causes the error
+	Node *yaml.Node
+	// File the error is in when the validated document is merged from several files
+	File string
+}
+
+func (e SchemaError) Error() string {
+	if e.Path == "" {
+		return fmt.Sprintf("%d:%d: %s", e.Line, e.Column, e.Message)
+	}
+	return fmt.Sprintf("%d:%d: %s: %s", e.Line, e.Column, e.Path, e.Message)
+}
+
+// SchemaFromType generates the schema of a struct from its yaml tags. Fields without omitempty are required
+// and unknown fields are rejected.
+func SchemaFromType(t reflect.Type) *JSONSchema {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return &JSONSchema{Type: "string"}
+	case reflect.Bool:
+		return &JSONSchema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &JSONSchema{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return &JSONSchema{Type: "number"}
+	case reflect.Slice, reflect.Array:
+		return &JSONSchema{Type: "array", Items: SchemaFromType(t.Elem())}
+	case reflect.Map:
+		return &JSONSchema{Type: "object", AdditionalProperties: SchemaFromType(t.Elem())}
+	case reflect.Struct:
+		schema := &JSONSchema{Type: "object", Properties: make(map[string]*JSONSchema), AdditionalProperties: false}
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if f.PkgPath != "" {
+				continue
+			}
+			name, opts, _ := strings.Cut(f.Tag.Get("yaml"), ",")
+			if name == "-" {
+				continue
+			}
+			if name == "" {
+				name = strings.ToLower(f.Name)
+			}
+			schema.Properties[name] = SchemaFromType(f.Type)
+			if !strings.Contains(opts, "omitempty") {
+				schema.Required = append(schema.Required, name)
+			}
+		}
+		return schema
+	default:
+		// interface{} and anything else accepts any value
+		return &JSONSchema{}
+	}
+}
+
+// SchemaFromTemplate infers a schema from a yaml template, for versions whose layout no longer matches the Go structs.
+// Mappings accept the keys the template uses, sequences accept items shaped like any of the template's items.
+func SchemaFromTemplate(node *yaml.Node) *JSONSchema {
+	node = resolveNode(node)
+	switch node.Kind {
+	case yaml.MappingNode:
+		schema := &JSONSchema{Type: "object", Properties: make(map[string]*JSONSchema), AdditionalProperties: false}
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			schema.Properties[node.Content[i].Value] = SchemaFromTemplate(node.Content[i+1])
+		}
+		return schema
+	case yaml.SequenceNode:
+		schema := &JSONSchema{Type: "array"}
+		for _, item := range node.Content {
+			schema.Items = mergeSchemas(schema.Items, SchemaFromTemplate(item))
+		}
+		if schema.Items == nil {
+			schema.Items = &JSONSchema{}
+		}
+		return schema
+	case yaml.ScalarNode:
+		switch node.Tag {
+		case "!!int":
+			return &JSONSchema{Type: "integer"}
+		case "!!float":
+			return &JSONSchema{Type: "number"}
+		case "!!bool":
+			return &JSONSchema{Type: "boolean"}
+		case "!!null":
+			return &JSONSchema{}
+		}
+		return &JSONSchema{Type: "string"}
+	}
+	return &JSONSchema{}
+}
+
+// mergeSchemas widens a to also accept what b accepts, used to combine the items of a template sequence
+func mergeSchemas(a, b *JSONSchema) *JSONSchema {
+	if a == nil {
+		return b
+	}
+	if a.Type != b.Type {
+		if (a.Type == "integer" && b.Type == "number") || (a.Type == "number" && b.Type == "integer") {
+			return &JSONSchema{Type: "number"}
+		}
+		return &JSONSchema{}
+	}
+	for name, prop := range b.Properties {
+		a.Properties[name] = mergeSchemas(a.Properties[name], prop)
+	}
+	if a.Items != nil && b.Items != nil {
+		a.Items = mergeSchemas(a.Items, b.Items)
+	}
+	return a
+}
+
+// ApplyTemplate fits a struct schema to a version's template: fields the template leaves out are not required,
+// and keys the template uses without a matching field are accepted with their inferred schema
+func ApplyTemplate(schema *JSONSchema, node *yaml.Node) {
+	node = resolveNode(node)
+	switch {
+	case schema.Type == "object" && schema.Properties != nil && node.Kind == yaml.MappingNode:
+		present := make(map[string]bool)
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i].Value, node.Content[i+1]
+			present[key] = true
+			if prop, ok := schema.Properties[key]; ok {
+				ApplyTemplate(prop, value)
+			} else {
+				schema.Properties[key] = SchemaFromTemplate(value)
+			}
+		}
+		required := schema.Required[:0]
+		for _, name := range schema.Required {
+			if present[name] {
+				required = append(required, name)
+			}
+		}
+		schema.Required = required
+	case schema.Type == "object" && node.Kind == yaml.MappingNode:
+		if values, ok := schema.AdditionalProperties.(*JSONSchema); ok {
+			for i := 1; i < len(node.Content); i += 2 {
+				ApplyTemplate(values, node.Content[i])
+			}
+		}
+	case schema.Type == "array" && schema.Items != nil && node.Kind == yaml.SequenceNode:
+		for _, item := range node.Content {
+			ApplyTemplate(schema.Items, item)
+		}
+	}
+}
+
+// PartialSchema drops every required field from schema, for files that only hold the settings they override
+func PartialSchema(schema *JSONSchema) {
+	if schema == nil {
+		return
+	}
+	schema.Required = nil
+	for _, prop := range schema.Properties {
+		PartialSchema(prop)
+	}
+	PartialSchema(schema.Items)
+	if values, ok := schema.AdditionalProperties.(*JSONSchema); ok {
+		PartialSchema(values)
+	}
+}
+
+// ValidateNode checks node against schema and returns every violation, ordered by position
+func ValidateNode(schema *JSONSchema, node *yaml.Node) []SchemaError {
+	if node.Kind == yaml.DocumentNode && len(node.Content) > 0 {
+		node = node.Content[0]
+	}
+	var errs []SchemaError
+	validateNode(schema, node, "", &errs)
+	sort.SliceStable(errs, func(i, j int) bool {
+		if errs[i].Line != errs[j].Line {
+			return errs[i].Line < errs[j].Line
+		}
+		return errs[i].Column < errs[j].Column
+	})
+	return errs
+}
+
+func validateNode(schema *JSONSchema, node *yaml.Node, path string, errs *[]SchemaError) {
+	node = resolveNode(node)
+	fail := func(n *yaml.Node, format string, args ...interface{}) {
+		*errs = append(*errs, SchemaError{Path: path, Line: n.Line, Column: n.Column, Message: fmt.Sprintf(format, args...), Node: n})
+	}
+
+	// A null leaves the field at its zero value, which any type accepts
+	if schema.Type == "" || (node.Kind == yaml.ScalarNode && node.Tag == "!!null") {
+		return
+	}
+
+	switch schema.Type {
+	case "object":
+		if node.Kind != yaml.MappingNode {
+			fail(node, "expected a mapping, got %s", describeNode(node))
+			return
+		}
+		present := make(map[string]bool)
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			present[key.Value] = true
+			childPath := joinSchemaPath(path, key.Value)
+			if prop, ok := schema.Properties[key.Value]; ok {
+				validateNode(prop, value, childPath, errs)
+				continue
+			}
+			switch additional := schema.AdditionalProperties.(type) {
+			case *JSONSchema:
+				validateNode(additional, value, childPath, errs)
+			case bool:
+				if !additional {
+					msg := fmt.Sprintf("unknown field %q", key.Value)
+					if suggestion := closestName(key.Value, schema.Properties); suggestion != "" {
+						msg += fmt.Sprintf(", did you mean %q?", suggestion)
+					}
+					*errs = append(*errs, SchemaError{Path: path, Line: key.Line, Column: key.Column, Message: msg, Node: key})
+				}
+			}
+		}
+		for _, name := range schema.Required {
+			if !present[name] {
+				fail(node, "missing required field %q", name)
+			}
+		}
+	case "array":
+		if node.Kind != yaml.SequenceNode {
+			fail(node, "expected a sequence, got %s", describeNode(node))
+			return
+		}
+		for i, item := range node.Content {
+			validateNode(schema.Items, item, fmt.Sprintf("%s[%d]", path, i), errs)
+		}
+	case "string":
+		if node.Kind != yaml.ScalarNode {
+			fail(node, "expected a string, got %s", describeNode(node))
+		}
+	case "integer":
+		if node.Kind != yaml.ScalarNode || node.Tag != "!!int" {
+			fail(node, "expected an integer, got %s", describeNode(node))
+		}
+	case "number":
+		if node.Kind != yaml.ScalarNode || (node.Tag != "!!int" && node.Tag != "!!float") {
+			fail(node, "expected a number, got %s", describeNode(node))
+		}
+	case "boolean":
+		if node.Kind != yaml.ScalarNode || node.Tag != "!!bool" {
+			fail(node, "expected true or false, got %s", describeNode(node))
+		}
+	}
+}
+
+// resolveNode follows aliases to the node they point at
+func resolveNode(node *yaml.Node) *yaml.Node {
+	for node.Kind == yaml.AliasNode && node.Alias != nil {
+		node = node.Alias
+	}
+	return node
+}
+
+// describeNode names what a node holds for error messages
+func describeNode(node *yaml.Node) string {
+	switch node.Kind {
+	case yaml.MappingNode:
+		return "a mapping"
+	case yaml.SequenceNode:
+		return "a sequence"
+	}
+	switch node.Tag {
+	case "!!int":
+		return "integer " + node.Value
+	case "!!float":
+		return "number " + node.Value
+	case "!!bool":
+		return node.Value
+	}
+	return "string " + strconv.Quote(node.Value)
+}
+
+func joinSchemaPath(base, key string) string {
+	if base == "" {
+		return key
+	}
+	return base + "." + key
+}
+
+// closestName suggests the known field a misspelled key most likely meant
+func closestName(name string, properties map[string]*JSONSchema) string {
+	best, bestDistance := "", len(name)/2+1
+	for candidate := range properties {
+		if d := editDistance(name, candidate); d < bestDistance || (d == bestDistance && candidate < best) {
+			best, bestDistance = candidate, d
+		}
+	}
+	return best
+}
+
+// editDistance is the Levenshtein distance between a and b
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
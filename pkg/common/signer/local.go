@@ -0,0 +1,112 @@
+package signer
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+
+	"github.com/Layr-Labs/crypto-libs/pkg/bn254"
+	"github.com/Layr-Labs/crypto-libs/pkg/keystore"
+	ethkeystore "github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// PrivateKeySigner signs transactions with an in-memory ECDSA key
+type PrivateKeySigner struct {
+	key     *ecdsa.PrivateKey
+	address common.Address
+}
+
+// NewPrivateKeySigner creates a signer from a hex encoded private key, with or without 0x prefix
+func NewPrivateKeySigner(privateKeyHex string) (*PrivateKeySigner, error) {
+	key, err := crypto.HexToECDSA(strings.TrimPrefix(privateKeyHex, "0x"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid private key: %w", err)
+	}
+	return NewPrivateKeySignerFromKey(key), nil
+}
+
+// NewPrivateKeySignerFromKey creates a signer from a parsed ECDSA key
+func NewPrivateKeySignerFromKey(key *ecdsa.PrivateKey) *PrivateKeySigner {
+	return &PrivateKeySigner{key: key, address: crypto.PubkeyToAddress(key.PublicKey)}
+}
+
+// NewKeystoreSigner decrypts an Ethereum (web3 secret storage) keystore file
+func NewKeystoreSigner(path, password string) (*PrivateKeySigner, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read keystore %s: %w", path, err)
+	}
+	key, err := ethkeystore.DecryptKey(data, password)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt keystore %s: %w", path, err)
+	}
+	return NewPrivateKeySignerFromKey(key.PrivateKey), nil
+}
+
+// Address returns the signer's account
+func (s *PrivateKeySigner) Address() common.Address {
+	return s.address
+}
+
+// SignTx signs tx with the latest signer for chainID
+func (s *PrivateKeySigner) SignTx(_ context.Context, chainID *big.Int, tx *types.Transaction) (*types.Transaction, error) {
+	return types.SignTx(tx, types.LatestSignerForChainID(chainID), s.key)
+}
+
+// Close is a no-op, the key lives in memory
+func (s *PrivateKeySigner) Close() {}
+
+// NewBLSPrivateKeySigner creates a BLS signer from a hex encoded BN254 private key
+func NewBLSPrivateKeySigner(privateKeyHex string) (*LocalBLSSigner, error) {
+	genericPk, err := bn254.NewScheme().NewPrivateKeyFromHexString(strings.TrimPrefix(privateKeyHex, "0x"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create BLS private key: %w", err)
+	}
+	pk, err := bn254.NewPrivateKeyFromBytes(genericPk.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert BLS private key: %w", err)
+	}
+	return NewLocalBLSSigner(pk)
+}
+
+// NewBLSKeystoreSigner decrypts an EIP-2335 BN254 keystore file
+func NewBLSKeystoreSigner(path, password string) (*LocalBLSSigner, error) {
+	ks, err := keystore.LoadKeystoreFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load BLS keystore %s: %w", path, err)
+	}
+	pk, err := ks.GetBN254PrivateKey(password)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt BLS keystore %s: %w", path, err)
+	}
+	return NewLocalBLSSigner(pk)
+}
+
+// LocalBLSSigner signs with an in-memory BN254 key
+type LocalBLSSigner struct {
+	key *bn254.PrivateKey
+}
+
+// NewLocalBLSSigner wraps a parsed BN254 key
+func NewLocalBLSSigner(key *bn254.PrivateKey) (*LocalBLSSigner, error) {
+	if key == nil {
+		return nil, fmt.Errorf("private key cannot be nil")
+	}
+	return &LocalBLSSigner{key: key}, nil
+}
+
+// SignBytes signs a message hash in the format expected by the EigenLayer contracts
+func (s *LocalBLSSigner) SignBytes(_ context.Context, data [32]byte) (*bn254.Signature, error) {
+	return s.key.SignSolidityCompatible(data)
+}
+
+// GetPublicKey returns the public key of the signing key
+func (s *LocalBLSSigner) GetPublicKey() (*bn254.PublicKey, error) {
+	return s.key.Public(), nil
+}
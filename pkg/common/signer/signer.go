@@ -0,0 +1,76 @@
+// Package signer abstracts where transaction and BLS signing keys live: in the context as raw keys,
+// in encrypted keystores on disk, or behind a Web3Signer-compatible remote signing service.
+package signer
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/Layr-Labs/crypto-libs/pkg/bn254"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// TxSigner signs Ethereum transactions for a single account
+type TxSigner interface {
+	// Address returns the account the signer signs for
+	Address() common.Address
+	// SignTx returns a copy of tx signed for chainID
+	SignTx(ctx context.Context, chainID *big.Int, tx *types.Transaction) (*types.Transaction, error)
+	// Close releases the connection to a remote signer, local signers have nothing to release
+	Close()
+}
+
+// BLSSigner signs 32-byte message hashes with a BN254 key
+type BLSSigner interface {
+	SignBytes(ctx context.Context, data [32]byte) (*bn254.Signature, error)
+	GetPublicKey() (*bn254.PublicKey, error)
+}
+
+// TransactOpts returns bind options that sign with s, leaving fees, nonce and gas to the binding
+func TransactOpts(ctx context.Context, s TxSigner, chainID *big.Int) *bind.TransactOpts {
+	return &bind.TransactOpts{
+		From:    s.Address(),
+		Context: ctx,
+		Signer: func(addr common.Address, tx *types.Transaction) (*types.Transaction, error) {
+			if addr != s.Address() {
+				return nil, fmt.Errorf("signer for %s cannot sign for %s", s.Address().Hex(), addr.Hex())
+			}
+			return s.SignTx(ctx, chainID, tx)
+		},
+	}
+}
+
+// MultichainTxSigner adapts a TxSigner to the transaction signer interface used by the multichain transport
+type MultichainTxSigner struct {
+	Signer TxSigner
+}
+
+// GetTransactOpts returns bind options that sign with the wrapped signer
+func (m *MultichainTxSigner) GetTransactOpts(ctx context.Context, chainID *big.Int) (*bind.TransactOpts, error) {
+	return TransactOpts(ctx, m.Signer, chainID), nil
+}
+
+// GetAddress returns the address of the wrapped signer
+func (m *MultichainTxSigner) GetAddress() (common.Address, error) {
+	return m.Signer.Address(), nil
+}
+
+// MultichainBLSSigner adapts a BLSSigner to the BLS signer interface used by the multichain transport, which signs
+// without a context
+type MultichainBLSSigner struct {
+	Ctx    context.Context
+	Signer BLSSigner
+}
+
+// SignBytes signs data with the wrapped signer under the adapter's context
+func (m *MultichainBLSSigner) SignBytes(data [32]byte) (*bn254.Signature, error) {
+	return m.Signer.SignBytes(m.Ctx, data)
+}
+
+// GetPublicKey returns the public key of the wrapped signer
+func (m *MultichainBLSSigner) GetPublicKey() (*bn254.PublicKey, error) {
+	return m.Signer.GetPublicKey()
+}
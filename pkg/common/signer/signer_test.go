@@ -0,0 +1,252 @@
+package signer
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Layr-Labs/crypto-libs/pkg/bn254"
+	ethkeystore "github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+const testKey = "ac0974bec39a17e36ba4a6b4d238ff944bacb478cbed5efcae784d7bf4f2ff80"
+
+func testTx(nonce uint64) *types.Transaction {
+	to := common.HexToAddress("0x1000000000000000000000000000000000000001")
+	return types.NewTx(&types.DynamicFeeTx{
+		ChainID:   big.NewInt(17000),
+		Nonce:     nonce,
+		GasTipCap: big.NewInt(1),
+		GasFeeCap: big.NewInt(2),
+		Gas:       21000,
+		To:        &to,
+		Data:      []byte{0x01, 0x02},
+	})
+}
+
+// newWeb3SignerStub serves eth_accounts and eth_signTransaction, signing with key
+func newWeb3SignerStub(t *testing.T, accounts []common.Address, key string) *httptest.Server {
+	t.Helper()
+	local, err := NewPrivateKeySigner(key)
+	require.NoError(t, err)
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID     json.RawMessage   `json:"id"`
+			Method string            `json:"method"`
+			Params []json.RawMessage `json:"params"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+
+		var result interface{}
+		switch req.Method {
+		case "eth_accounts":
+			result = accounts
+		case "eth_signTransaction":
+			var args struct {
+				To                   *common.Address `json:"to"`
+				Gas                  hexutil.Uint64  `json:"gas"`
+				Nonce                hexutil.Uint64  `json:"nonce"`
+				Data                 hexutil.Bytes   `json:"data"`
+				ChainID              *hexutil.Big    `json:"chainId"`
+				MaxFeePerGas         *hexutil.Big    `json:"maxFeePerGas"`
+				MaxPriorityFeePerGas *hexutil.Big    `json:"maxPriorityFeePerGas"`
+			}
+			require.NoError(t, json.Unmarshal(req.Params[0], &args))
+			tx := types.NewTx(&types.DynamicFeeTx{
+				ChainID:   args.ChainID.ToInt(),
+				Nonce:     uint64(args.Nonce),
+				GasTipCap: args.MaxPriorityFeePerGas.ToInt(),
+				GasFeeCap: args.MaxFeePerGas.ToInt(),
+				Gas:       uint64(args.Gas),
+				To:        args.To,
+				Data:      args.Data,
+			})
+			signed, err := local.SignTx(r.Context(), args.ChainID.ToInt(), tx)
+			require.NoError(t, err)
+			raw, err := signed.MarshalBinary()
+			require.NoError(t, err)
+			result = hexutil.Bytes(raw)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		require.NoError(t, json.NewEncoder(w).Encode(map[string]interface{}{
+			"jsonrpc": "2.0",
+			"id":      req.ID,
+			"result":  result,
+		}))
+	}))
+}
+
+func TestPrivateKeySigner(t *testing.T) {
+	s, err := NewPrivateKeySigner("0x" + testKey)
+	require.NoError(t, err)
+	require.Equal(t, "0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266", s.Address().Hex())
+
+	signed, err := s.SignTx(context.Background(), big.NewInt(17000), testTx(3))
+	require.NoError(t, err)
+	sender, err := types.Sender(types.LatestSignerForChainID(big.NewInt(17000)), signed)
+	require.NoError(t, err)
+	require.Equal(t, s.Address(), sender)
+
+	_, err = NewPrivateKeySigner("not-a-key")
+	require.Error(t, err)
+}
+
+func TestKeystoreSigner(t *testing.T) {
+	key, err := crypto.HexToECDSA(testKey)
+	require.NoError(t, err)
+	encrypted, err := ethkeystore.EncryptKey(&ethkeystore.Key{
+		Id:         uuid.New(),
+		Address:    crypto.PubkeyToAddress(key.PublicKey),
+		PrivateKey: key,
+	}, "secret", ethkeystore.LightScryptN, ethkeystore.LightScryptP)
+	require.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "key.json")
+	require.NoError(t, os.WriteFile(path, encrypted, 0600))
+
+	s, err := NewKeystoreSigner(path, "secret")
+	require.NoError(t, err)
+	require.Equal(t, crypto.PubkeyToAddress(key.PublicKey), s.Address())
+
+	_, err = NewKeystoreSigner(path, "wrong")
+	require.Error(t, err)
+}
+
+func TestWeb3Signer(t *testing.T) {
+	local, err := NewPrivateKeySigner(testKey)
+	require.NoError(t, err)
+	other := common.HexToAddress("0x2000000000000000000000000000000000000002")
+
+	t.Run("single key is selected", func(t *testing.T) {
+		server := newWeb3SignerStub(t, []common.Address{local.Address()}, testKey)
+		defer server.Close()
+
+		s, err := NewWeb3Signer(context.Background(), server.URL, common.Address{})
+		require.NoError(t, err)
+		defer s.Close()
+		require.Equal(t, local.Address(), s.Address())
+
+		signed, err := s.SignTx(context.Background(), big.NewInt(17000), testTx(7))
+		require.NoError(t, err)
+		require.Equal(t, uint64(7), signed.Nonce())
+	})
+
+	t.Run("address required with several keys", func(t *testing.T) {
+		server := newWeb3SignerStub(t, []common.Address{local.Address(), other}, testKey)
+		defer server.Close()
+
+		_, err := NewWeb3Signer(context.Background(), server.URL, common.Address{})
+		require.ErrorContains(t, err, "holds 2 keys")
+
+		s, err := NewWeb3Signer(context.Background(), server.URL, local.Address())
+		require.NoError(t, err)
+		s.Close()
+	})
+
+	t.Run("unknown address", func(t *testing.T) {
+		server := newWeb3SignerStub(t, []common.Address{local.Address()}, testKey)
+		defer server.Close()
+
+		_, err := NewWeb3Signer(context.Background(), server.URL, other)
+		require.ErrorContains(t, err, "does not hold a key")
+	})
+
+	t.Run("signature from wrong key is rejected", func(t *testing.T) {
+		wrongKey := strings.Repeat("1", 64)
+		server := newWeb3SignerStub(t, []common.Address{local.Address()}, wrongKey)
+		defer server.Close()
+
+		s, err := NewWeb3Signer(context.Background(), server.URL, common.Address{})
+		require.NoError(t, err)
+		defer s.Close()
+
+		_, err = s.SignTx(context.Background(), big.NewInt(17000), testTx(1))
+		require.ErrorContains(t, err, "expected "+local.Address().Hex())
+	})
+}
+
+func TestRemoteBLSSigner(t *testing.T) {
+	privateKey, publicKey, err := bn254.GenerateKeyPair()
+	require.NoError(t, err)
+	publicKeyHex := hexutil.Encode(publicKey.Bytes())
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/api/v1/bn254/sign/"+publicKeyHex, r.URL.Path)
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		var req struct {
+			SigningRoot string `json:"signingRoot"`
+		}
+		require.NoError(t, json.Unmarshal(body, &req))
+
+		var root [32]byte
+		copy(root[:], hexutil.MustDecode(req.SigningRoot))
+		sig, err := privateKey.SignSolidityCompatible(root)
+		require.NoError(t, err)
+		_, _ = w.Write([]byte(hexutil.Encode(sig.Bytes())))
+	}))
+	defer server.Close()
+
+	s, err := NewRemoteBLSSigner(server.URL+"/", publicKeyHex)
+	require.NoError(t, err)
+
+	msg := crypto.Keccak256Hash([]byte("stake root"))
+	sig, err := s.SignBytes(context.Background(), msg)
+	require.NoError(t, err)
+
+	pub, err := s.GetPublicKey()
+	require.NoError(t, err)
+	ok, err := sig.VerifySolidityCompatible(pub, msg)
+	require.NoError(t, err)
+	require.True(t, ok)
+}
+
+func TestMultichainBLSSigner(t *testing.T) {
+	privateKey, _, err := bn254.GenerateKeyPair()
+	require.NoError(t, err)
+	local, err := NewLocalBLSSigner(privateKey)
+	require.NoError(t, err)
+	m := &MultichainBLSSigner{Ctx: context.Background(), Signer: local}
+
+	msg := crypto.Keccak256Hash([]byte("stake root"))
+	sig, err := m.SignBytes(msg)
+	require.NoError(t, err)
+
+	pub, err := m.GetPublicKey()
+	require.NoError(t, err)
+	ok, err := sig.VerifySolidityCompatible(pub, msg)
+	require.NoError(t, err)
+	require.True(t, ok)
+}
+
+func TestMultichainTxSigner(t *testing.T) {
+	local, err := NewPrivateKeySigner(testKey)
+	require.NoError(t, err)
+	m := &MultichainTxSigner{Signer: local}
+
+	addr, err := m.GetAddress()
+	require.NoError(t, err)
+	require.Equal(t, local.Address(), addr)
+
+	opts, err := m.GetTransactOpts(context.Background(), big.NewInt(17000))
+	require.NoError(t, err)
+	_, err = opts.Signer(local.Address(), testTx(0))
+	require.NoError(t, err)
+	_, err = opts.Signer(common.HexToAddress("0x2000000000000000000000000000000000000002"), testTx(0))
+	require.Error(t, err)
+}
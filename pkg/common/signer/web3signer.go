@@ -0,0 +1,188 @@
+package signer
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Layr-Labs/crypto-libs/pkg/bn254"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// Timeout for a single request to the remote signer
+const remoteSignerTimeout = 30 * time.Second
+
+// Web3Signer signs transactions through the eth_signTransaction JSON-RPC method of a Web3Signer-compatible service
+type Web3Signer struct {
+	client  *rpc.Client
+	address common.Address
+}
+
+// NewWeb3Signer connects to the remote signer at url. When address is zero the signer must hold exactly one key.
+func NewWeb3Signer(ctx context.Context, url string, address common.Address) (*Web3Signer, error) {
+	client, err := rpc.DialOptions(ctx, url, rpc.WithHTTPClient(&http.Client{Timeout: remoteSignerTimeout}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to remote signer at %s: %w", url, err)
+	}
+
+	var accounts []common.Address
+	if err := client.CallContext(ctx, &accounts, "eth_accounts"); err != nil {
+		client.Close()
+		return nil, fmt.Errorf("failed to list remote signer accounts: %w", err)
+	}
+
+	if address == (common.Address{}) {
+		if len(accounts) != 1 {
+			client.Close()
+			return nil, fmt.Errorf("remote signer at %s holds %d keys, set the signer address to choose one", url, len(accounts))
+		}
+		address = accounts[0]
+	} else if !containsAddress(accounts, address) {
+		client.Close()
+		return nil, fmt.Errorf("remote signer at %s does not hold a key for %s", url, address.Hex())
+	}
+
+	return &Web3Signer{client: client, address: address}, nil
+}
+
+// Address returns the remote account
+func (s *Web3Signer) Address() common.Address {
+	return s.address
+}
+
+// SignTx asks the remote signer to sign tx and checks the result matches the request
+func (s *Web3Signer) SignTx(ctx context.Context, chainID *big.Int, tx *types.Transaction) (*types.Transaction, error) {
+	args := map[string]interface{}{
+		"from":    s.address,
+		"gas":     hexutil.Uint64(tx.Gas()),
+		"value":   (*hexutil.Big)(tx.Value()),
+		"data":    hexutil.Bytes(tx.Data()),
+		"nonce":   hexutil.Uint64(tx.Nonce()),
+		"chainId": (*hexutil.Big)(chainID),
+	}
+	if tx.To() != nil {
+		args["to"] = tx.To()
+	}
+	if tx.Type() == types.LegacyTxType {
+		args["gasPrice"] = (*hexutil.Big)(tx.GasPrice())
+	} else {
+		args["maxFeePerGas"] = (*hexutil.Big)(tx.GasFeeCap())
+		args["maxPriorityFeePerGas"] = (*hexutil.Big)(tx.GasTipCap())
+	}
+
+	var raw hexutil.Bytes
+	if err := s.client.CallContext(ctx, &raw, "eth_signTransaction", args); err != nil {
+		return nil, fmt.Errorf("remote signer failed to sign transaction: %w", err)
+	}
+
+	signed := new(types.Transaction)
+	if err := signed.UnmarshalBinary(raw); err != nil {
+		return nil, fmt.Errorf("remote signer returned an invalid transaction: %w", err)
+	}
+	sender, err := types.Sender(types.LatestSignerForChainID(chainID), signed)
+	if err != nil {
+		return nil, fmt.Errorf("remote signer returned an invalid signature: %w", err)
+	}
+	if sender != s.address {
+		return nil, fmt.Errorf("remote signer signed with %s, expected %s", sender.Hex(), s.address.Hex())
+	}
+	if signed.Nonce() != tx.Nonce() || signed.Gas() != tx.Gas() || !bytes.Equal(signed.Data(), tx.Data()) {
+		return nil, fmt.Errorf("remote signer returned a transaction that differs from the request")
+	}
+	return signed, nil
+}
+
+// Close releases the connection to the remote signer
+func (s *Web3Signer) Close() {
+	s.client.Close()
+}
+
+// RemoteBLSSigner signs BN254 message hashes through a Web3Signer-style REST endpoint:
+// POST {url}/api/v1/bn254/sign/{publicKey} with {"signingRoot": "0x..."}
+type RemoteBLSSigner struct {
+	url        string
+	identifier string
+	publicKey  *bn254.PublicKey
+	httpClient *http.Client
+}
+
+// NewRemoteBLSSigner creates a remote BLS signer for the key identified by its hex encoded G2 public key
+func NewRemoteBLSSigner(url, publicKeyHex string) (*RemoteBLSSigner, error) {
+	identifier := strings.TrimPrefix(publicKeyHex, "0x")
+	publicKey, err := bn254.NewPublicKeyFromHexString(identifier)
+	if err != nil {
+		return nil, fmt.Errorf("invalid BLS public key: %w", err)
+	}
+	return &RemoteBLSSigner{
+		url:        strings.TrimRight(url, "/"),
+		identifier: identifier,
+		publicKey:  publicKey,
+		httpClient: &http.Client{Timeout: remoteSignerTimeout},
+	}, nil
+}
+
+// SignBytes asks the remote signer to sign the message hash
+func (s *RemoteBLSSigner) SignBytes(ctx context.Context, data [32]byte) (*bn254.Signature, error) {
+	body, err := json.Marshal(map[string]string{"signingRoot": hexutil.Encode(data[:])})
+	if err != nil {
+		return nil, err
+	}
+	endpoint := fmt.Sprintf("%s/api/v1/bn254/sign/0x%s", s.url, s.identifier)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("remote BLS signer request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read remote BLS signer response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("remote BLS signer returned %s: %s", resp.Status, strings.TrimSpace(string(respBody)))
+	}
+
+	// Web3Signer answers with a bare hex string unless JSON is negotiated, accept both
+	sigHex := strings.TrimSpace(string(respBody))
+	var decoded struct {
+		Signature string `json:"signature"`
+	}
+	if json.Unmarshal(respBody, &decoded) == nil && decoded.Signature != "" {
+		sigHex = decoded.Signature
+	}
+	sigBytes, err := hexutil.Decode(sigHex)
+	if err != nil {
+		return nil, fmt.Errorf("remote BLS signer returned an invalid signature: %w", err)
+	}
+	return bn254.NewSignatureFromBytes(sigBytes)
+}
+
+// GetPublicKey returns the public key the signer was configured with
+func (s *RemoteBLSSigner) GetPublicKey() (*bn254.PublicKey, error) {
+	return s.publicKey, nil
+}
+
+func containsAddress(list []common.Address, addr common.Address) bool {
+	for _, a := range list {
+		if a == addr {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,94 @@
+package common
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Layr-Labs/devkit-cli/pkg/common/signer"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Signer types accepted in a role's signer config
+const (
+	SignerTypePrivateKey = "private_key"
+	SignerTypeKeystore   = "keystore"
+	SignerTypeWeb3Signer = "web3signer"
+)
+
+// NewTxSigner builds the transaction signer described by cfg, falling back to rawKey when cfg is nil
+func NewTxSigner(ctx context.Context, cfg *SignerConfig, rawKey string) (signer.TxSigner, error) {
+	if isRawKeySigner(cfg) {
+		if rawKey == "" {
+			return nil, fmt.Errorf("no private key configured")
+		}
+		return signer.NewPrivateKeySigner(rawKey)
+	}
+
+	switch cfg.Type {
+	case SignerTypeKeystore:
+		if cfg.KeystorePath == "" {
+			return nil, fmt.Errorf("keystore signer requires keystore_path")
+		}
+		return signer.NewKeystoreSigner(cfg.KeystorePath, cfg.KeystorePassword)
+	case SignerTypeWeb3Signer:
+		if cfg.URL == "" {
+			return nil, fmt.Errorf("web3signer signer requires url")
+		}
+		var address common.Address
+		if cfg.Address != "" {
+			if !common.IsHexAddress(cfg.Address) {
+				return nil, fmt.Errorf("invalid signer address %q", cfg.Address)
+			}
+			address = common.HexToAddress(cfg.Address)
+		}
+		return signer.NewWeb3Signer(ctx, cfg.URL, address)
+	}
+	return nil, fmt.Errorf("unknown signer type %q", cfg.Type)
+}
+
+// isRawKeySigner reports whether cfg leaves signing to the role's raw key
+func isRawKeySigner(cfg *SignerConfig) bool {
+	return cfg == nil || cfg.Type == "" || cfg.Type == SignerTypePrivateKey
+}
+
+// NewBLSSigner builds the BLS signer described by cfg, falling back to rawKey when cfg is nil. A web3signer signer
+// signs remotely with the key of bls_public_key, or locally with the raw key when it has none. Any signer can instead
+// carry a local BN254 keystore.
+func NewBLSSigner(cfg *SignerConfig, rawKey string) (signer.BLSSigner, error) {
+	if cfg != nil && cfg.BlsKeystorePath != "" {
+		return signer.NewBLSKeystoreSigner(cfg.BlsKeystorePath, cfg.BlsKeystorePassword)
+	}
+	if isRawKeySigner(cfg) {
+		if rawKey == "" {
+			return nil, fmt.Errorf("no BLS private key configured")
+		}
+		return signer.NewBLSPrivateKeySigner(rawKey)
+	}
+
+	switch cfg.Type {
+	case SignerTypeKeystore:
+		return nil, fmt.Errorf("keystore signer requires bls_keystore_path")
+	case SignerTypeWeb3Signer:
+		if cfg.BlsPublicKey == "" {
+			if rawKey == "" {
+				return nil, fmt.Errorf("web3signer BLS signer requires bls_public_key, bls_keystore_path or the raw BLS key")
+			}
+			return signer.NewBLSPrivateKeySigner(rawKey)
+		}
+		if cfg.URL == "" {
+			return nil, fmt.Errorf("web3signer signer requires url")
+		}
+		return signer.NewRemoteBLSSigner(cfg.URL, cfg.BlsPublicKey)
+	}
+	return nil, fmt.Errorf("unknown signer type %q", cfg.Type)
+}
+
+// TxSigner returns the signer used for the transporter's transactions
+func (t Transporter) TxSigner(ctx context.Context) (signer.TxSigner, error) {
+	return NewTxSigner(ctx, t.Signer, t.PrivateKey)
+}
+
+// BLSSigner returns the signer used to sign transported stake roots
+func (t Transporter) BLSSigner() (signer.BLSSigner, error) {
+	return NewBLSSigner(t.Signer, t.BlsPrivateKey)
+}
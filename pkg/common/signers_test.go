@@ -0,0 +1,58 @@
+package common
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Layr-Labs/devkit-cli/pkg/common/signer"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewTxSigner(t *testing.T) {
+	const key = "0xac0974bec39a17e36ba4a6b4d238ff944bacb478cbed5efcae784d7bf4f2ff80"
+
+	s, err := NewTxSigner(context.Background(), nil, key)
+	require.NoError(t, err)
+	require.Equal(t, "0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266", s.Address().Hex())
+
+	tests := []struct {
+		name string
+		cfg  *SignerConfig
+		key  string
+		err  string
+	}{
+		{"missing raw key", nil, "", "no private key configured"},
+		{"keystore without path", &SignerConfig{Type: SignerTypeKeystore}, "", "keystore_path"},
+		{"web3signer without url", &SignerConfig{Type: SignerTypeWeb3Signer}, "", "requires url"},
+		{"web3signer bad address", &SignerConfig{Type: SignerTypeWeb3Signer, URL: "http://localhost:9000", Address: "0x1"}, "", "invalid signer address"},
+		{"unknown type", &SignerConfig{Type: "hsm"}, key, "unknown signer type"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewTxSigner(context.Background(), tt.cfg, tt.key)
+			require.ErrorContains(t, err, tt.err)
+		})
+	}
+}
+
+func TestNewBLSSigner(t *testing.T) {
+	_, err := NewBLSSigner(nil, "")
+	require.ErrorContains(t, err, "no BLS private key configured")
+
+	// Web3Signer signs with the key of bls_public_key, without one the BLS key stays local
+	_, err = NewBLSSigner(&SignerConfig{Type: SignerTypeWeb3Signer, URL: "http://localhost:9000"}, "")
+	require.ErrorContains(t, err, "bls_public_key")
+	bls, err := NewBLSSigner(&SignerConfig{Type: SignerTypeWeb3Signer, URL: "http://localhost:9000"}, "0x2ba58f64c57faa1073d63add89799f2a0101855a8b289b1330cb500758d5d1ee")
+	require.NoError(t, err)
+	publicKey, err := bls.GetPublicKey()
+	require.NoError(t, err)
+	bls, err = NewBLSSigner(&SignerConfig{Type: SignerTypeWeb3Signer, URL: "http://localhost:9000", BlsPublicKey: hexutil.Encode(publicKey.Bytes())}, "")
+	require.NoError(t, err)
+	require.IsType(t, &signer.RemoteBLSSigner{}, bls)
+	_, err = NewBLSSigner(&SignerConfig{Type: SignerTypeWeb3Signer, BlsPublicKey: hexutil.Encode(publicKey.Bytes())}, "")
+	require.ErrorContains(t, err, "requires url")
+
+	_, err = NewBLSSigner(&SignerConfig{Type: SignerTypeKeystore}, "")
+	require.ErrorContains(t, err, "bls_keystore_path")
+}
@@ -0,0 +1,527 @@
+package common
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Layr-Labs/devkit-cli/pkg/common/iface"
+	"github.com/Layr-Labs/devkit-cli/pkg/common/signer"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// TxManagerConfig controls fee caps, replacement, retries and confirmation depth
+type TxManagerConfig struct {
+	// Upper bounds for the dynamic fee fields, nil leaves them uncapped
+	MaxFeePerGas         *big.Int
+	MaxPriorityFeePerGas *big.Int
+	// maxFeePerGas is set to baseFee * BaseFeeMultiplier + tip
+	BaseFeeMultiplier int64
+	// Percentage by which both fee fields are raised when replacing a stuck tx
+	PriceBumpPercent int64
+	// How long to wait for inclusion before replacing the tx with a higher fee
+	ResubmitAfter time.Duration
+	MaxResubmits  int
+	// Retries for transient RPC failures (timeouts, rate limits, 5xx)
+	MaxRetries   int
+	RetryBackoff time.Duration
+	PollInterval time.Duration
+	// Number of blocks (including the inclusion block) to wait for
+	Confirmations uint64
+	// Fetch a debug_traceTransaction call trace for reverted txs, the node must expose the debug namespace
+	TraceReverts bool
+}
+
+// DefaultTxManagerConfig returns the defaults used when a chain has no transactions config
+func DefaultTxManagerConfig() TxManagerConfig {
+	return TxManagerConfig{
+		BaseFeeMultiplier: 2,
+		PriceBumpPercent:  12,
+		ResubmitAfter:     30 * time.Second,
+		MaxResubmits:      5,
+		MaxRetries:        3,
+		RetryBackoff:      500 * time.Millisecond,
+		PollInterval:      500 * time.Millisecond,
+		Confirmations:     1,
+	}
+}
+
+// TxManagerConfig converts the context transactions config into a TxManagerConfig, filling in defaults
+func (c *TxConfig) TxManagerConfig() (TxManagerConfig, error) {
+	cfg := DefaultTxManagerConfig()
+	if c == nil {
+		return cfg, nil
+	}
+	if c.MaxFeePerGasGwei < 0 || c.MaxPriorityFeePerGasGwei < 0 {
+		return cfg, fmt.Errorf("fee caps must not be negative")
+	}
+	if c.MaxFeePerGasGwei > 0 {
+		cfg.MaxFeePerGas = gweiToWei(c.MaxFeePerGasGwei)
+	}
+	if c.MaxPriorityFeePerGasGwei > 0 {
+		cfg.MaxPriorityFeePerGas = gweiToWei(c.MaxPriorityFeePerGasGwei)
+	}
+	if cfg.MaxFeePerGas != nil && cfg.MaxPriorityFeePerGas != nil && cfg.MaxPriorityFeePerGas.Cmp(cfg.MaxFeePerGas) > 0 {
+		return cfg, fmt.Errorf("max_priority_fee_per_gas_gwei (%v) exceeds max_fee_per_gas_gwei (%v)", c.MaxPriorityFeePerGasGwei, c.MaxFeePerGasGwei)
+	}
+	if c.Confirmations > 0 {
+		cfg.Confirmations = c.Confirmations
+	}
+	if c.ResubmitAfter != "" {
+		d, err := time.ParseDuration(c.ResubmitAfter)
+		if err != nil {
+			return cfg, fmt.Errorf("invalid resubmit_after %q: %w", c.ResubmitAfter, err)
+		}
+		cfg.ResubmitAfter = d
+	}
+	if c.MaxResubmits > 0 {
+		cfg.MaxResubmits = c.MaxResubmits
+	}
+	if c.MaxRetries > 0 {
+		cfg.MaxRetries = c.MaxRetries
+	}
+	cfg.TraceReverts = c.TraceReverts
+	return cfg, nil
+}
+
+func gweiToWei(gwei float64) *big.Int {
+	wei, _ := new(big.Float).Mul(big.NewFloat(gwei), big.NewFloat(params.GWei)).Int(nil)
+	return wei
+}
+
+// nonceTracker hands out nonces per chain and signer so concurrent senders do not collide
+type nonceTracker struct {
+	mu     sync.Mutex
+	nonces map[string]uint64
+}
+
+// Shared by every TxManager in the process so parallel steps using the same key stay ordered
+var defaultNonceTracker = &nonceTracker{nonces: make(map[string]uint64)}
+
+func nonceKey(chainID *big.Int, addr common.Address) string {
+	return chainID.String() + ":" + addr.Hex()
+}
+
+// next reserves the next nonce, never going below the node's pending nonce
+func (t *nonceTracker) next(chainID *big.Int, addr common.Address, pending uint64) uint64 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	key := nonceKey(chainID, addr)
+	nonce, ok := t.nonces[key]
+	if !ok || pending > nonce {
+		nonce = pending
+	}
+	t.nonces[key] = nonce + 1
+	return nonce
+}
+
+// reset forgets the tracked nonce so the next reservation resyncs from the node
+func (t *nonceTracker) reset(chainID *big.Int, addr common.Address) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.nonces, nonceKey(chainID, addr))
+}
+
+// TxManager signs, sends and confirms transactions with EIP-1559 fees, managed nonces and retries
+type TxManager struct {
+	client  *ethclient.Client
+	chainID *big.Int
+	cfg     TxManagerConfig
+	logger  iface.Logger
+	nonces  *nonceTracker
+}
+
+// NewTxManager creates a transaction manager for a single chain
+func NewTxManager(client *ethclient.Client, chainID *big.Int, cfg TxManagerConfig, logger iface.Logger) *TxManager {
+	return &TxManager{
+		client:  client,
+		chainID: chainID,
+		cfg:     cfg,
+		logger:  logger,
+		nonces:  defaultNonceTracker,
+	}
+}
+
+// Config returns the manager's configuration
+func (m *TxManager) Config() TxManagerConfig {
+	return m.cfg
+}
+
+// SuggestFees returns capped EIP-1559 fee fields, or a capped legacy gas price as feeCap when the chain has no base fee
+func (m *TxManager) SuggestFees(ctx context.Context) (tipCap *big.Int, feeCap *big.Int, dynamic bool, err error) {
+	var head *types.Header
+	err = m.withRetry(ctx, "fetch latest header", func() error {
+		var callErr error
+		head, callErr = m.client.HeaderByNumber(ctx, nil)
+		return callErr
+	})
+	if err != nil {
+		return nil, nil, false, err
+	}
+
+	if head.BaseFee == nil {
+		var gasPrice *big.Int
+		err = m.withRetry(ctx, "suggest gas price", func() error {
+			var callErr error
+			gasPrice, callErr = m.client.SuggestGasPrice(ctx)
+			return callErr
+		})
+		if err != nil {
+			return nil, nil, false, err
+		}
+		gasPrice = capAt(gasPrice, m.cfg.MaxFeePerGas)
+		return gasPrice, gasPrice, false, nil
+	}
+
+	err = m.withRetry(ctx, "suggest gas tip cap", func() error {
+		var callErr error
+		tipCap, callErr = m.client.SuggestGasTipCap(ctx)
+		return callErr
+	})
+	if err != nil {
+		return nil, nil, false, err
+	}
+
+	multiplier := m.cfg.BaseFeeMultiplier
+	if multiplier < 1 {
+		multiplier = 1
+	}
+	tipCap = capAt(tipCap, m.cfg.MaxPriorityFeePerGas)
+	feeCap = new(big.Int).Mul(head.BaseFee, big.NewInt(multiplier))
+	feeCap.Add(feeCap, tipCap)
+	feeCap = capAt(feeCap, m.cfg.MaxFeePerGas)
+	if tipCap.Cmp(feeCap) > 0 {
+		tipCap = new(big.Int).Set(feeCap)
+	}
+	return tipCap, feeCap, true, nil
+}
+
+// SendAndWait re-signs the call described by template (to, value, data and gas) with a managed nonce and
+// current fees, broadcasts it, replaces it with higher fees while it is stuck and waits for confirmations.
+// It returns the transaction that was finally included together with its receipt.
+func (m *TxManager) SendAndWait(ctx context.Context, txSigner signer.TxSigner, template *types.Transaction) (*types.Transaction, *types.Receipt, error) {
+	if txSigner == nil {
+		return nil, nil, fmt.Errorf("no signer configured")
+	}
+	from := txSigner.Address()
+
+	tipCap, feeCap, dynamic, err := m.SuggestFees(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get fees: %w", err)
+	}
+
+	var pending uint64
+	err = m.withRetry(ctx, "fetch pending nonce", func() error {
+		var callErr error
+		pending, callErr = m.client.PendingNonceAt(ctx, from)
+		return callErr
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get nonce: %w", err)
+	}
+	nonce := m.nonces.next(m.chainID, from, pending)
+
+	build := func() (*types.Transaction, error) {
+		var inner types.TxData
+		if dynamic {
+			inner = &types.DynamicFeeTx{
+				ChainID:   m.chainID,
+				Nonce:     nonce,
+				GasTipCap: tipCap,
+				GasFeeCap: feeCap,
+				Gas:       template.Gas(),
+				To:        template.To(),
+				Value:     template.Value(),
+				Data:      template.Data(),
+			}
+		} else {
+			inner = &types.LegacyTx{
+				Nonce:    nonce,
+				GasPrice: feeCap,
+				Gas:      template.Gas(),
+				To:       template.To(),
+				Value:    template.Value(),
+				Data:     template.Data(),
+			}
+		}
+		return txSigner.SignTx(ctx, m.chainID, types.NewTx(inner))
+	}
+
+	tx, err := build()
+	if err != nil {
+		m.nonces.reset(m.chainID, from)
+		return nil, nil, fmt.Errorf("failed to sign transaction: %w", err)
+	}
+	if err := m.broadcast(ctx, tx); err != nil {
+		// Nothing was accepted for this nonce, let the next send resync with the node
+		m.nonces.reset(m.chainID, from)
+		return nil, nil, err
+	}
+
+	sent := []*types.Transaction{tx}
+	resubmits := 0
+	exhausted := false
+	for {
+		mined, receipt, err := m.waitForAny(ctx, sent, m.cfg.ResubmitAfter)
+		if err != nil {
+			return nil, nil, err
+		}
+		if receipt != nil {
+			if err := m.waitForConfirmations(ctx, receipt); err != nil {
+				return mined, receipt, err
+			}
+			return mined, receipt, nil
+		}
+
+		if exhausted {
+			// The last transaction got a full window too, give up rather than poll forever. The nonce is left to
+			// the node so a later send does not queue behind a transaction that may never be mined.
+			m.nonces.reset(m.chainID, from)
+			return nil, nil, fmt.Errorf("transaction %s not mined after %d replacements, it may still be mined later; raise the fee caps or resubmits under transactions in the context", tx.Hash().Hex(), resubmits)
+		}
+		if resubmits >= m.cfg.MaxResubmits {
+			m.logger.Warn("Transaction %s not mined after %d replacements, waiting %s more", tx.Hash().Hex(), resubmits, m.cfg.ResubmitAfter)
+			exhausted = true
+			continue
+		}
+		bumpedTip, bumpedFee, ok := m.bumpFees(tipCap, feeCap)
+		if !ok {
+			m.logger.Warn("Transaction %s is pending but fees are already at the configured caps, waiting %s more", tx.Hash().Hex(), m.cfg.ResubmitAfter)
+			exhausted = true
+			continue
+		}
+		tipCap, feeCap = bumpedTip, bumpedFee
+		replacement, err := build()
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to sign replacement transaction: %w", err)
+		}
+		resubmits++
+		m.logger.Warn("Transaction %s not mined after %s, replacing with %s (max fee %s wei, attempt %d/%d)",
+			tx.Hash().Hex(), m.cfg.ResubmitAfter, replacement.Hash().Hex(), feeCap.String(), resubmits, m.cfg.MaxResubmits)
+		if err := m.broadcast(ctx, replacement); err != nil {
+			// The original may have just been mined, keep waiting on what was sent
+			m.logger.Warn("Failed to send replacement transaction: %v", err)
+			continue
+		}
+		tx = replacement
+		sent = append(sent, replacement)
+	}
+}
+
+// WaitForReceipt polls for the receipt of a transaction sent outside the manager, e.g. from an impersonated account
+func (m *TxManager) WaitForReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error) {
+	ticker := time.NewTicker(m.pollInterval())
+	defer ticker.Stop()
+	for {
+		receipt, err := m.client.TransactionReceipt(ctx, txHash)
+		if err == nil {
+			if err := m.waitForConfirmations(ctx, receipt); err != nil {
+				return receipt, err
+			}
+			return receipt, nil
+		}
+		if !errors.Is(err, ethereum.NotFound) && !isTransientRPCError(err) {
+			return nil, err
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// broadcast sends a signed transaction, retrying transient failures
+func (m *TxManager) broadcast(ctx context.Context, tx *types.Transaction) error {
+	return m.withRetry(ctx, "send transaction", func() error {
+		err := m.client.SendTransaction(ctx, tx)
+		if err != nil && strings.Contains(strings.ToLower(err.Error()), "already known") {
+			return nil
+		}
+		return err
+	})
+}
+
+// waitForAny waits up to timeout for any of the sent transactions (all sharing one nonce) to be mined
+func (m *TxManager) waitForAny(ctx context.Context, sent []*types.Transaction, timeout time.Duration) (*types.Transaction, *types.Receipt, error) {
+	ticker := time.NewTicker(m.pollInterval())
+	defer ticker.Stop()
+
+	var deadline <-chan time.Time
+	if timeout > 0 {
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+		deadline = timer.C
+	}
+
+	for {
+		for _, tx := range sent {
+			receipt, err := m.client.TransactionReceipt(ctx, tx.Hash())
+			if err == nil {
+				return tx, receipt, nil
+			}
+			if !errors.Is(err, ethereum.NotFound) && !isTransientRPCError(err) {
+				return nil, nil, fmt.Errorf("failed to get receipt for %s: %w", tx.Hash().Hex(), err)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, nil, ctx.Err()
+		case <-deadline:
+			return nil, nil, nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// waitForConfirmations blocks until the receipt's block is buried under the configured depth
+func (m *TxManager) waitForConfirmations(ctx context.Context, receipt *types.Receipt) error {
+	if m.cfg.Confirmations <= 1 || receipt.BlockNumber == nil {
+		return nil
+	}
+	target := receipt.BlockNumber.Uint64() + m.cfg.Confirmations - 1
+
+	ticker := time.NewTicker(m.pollInterval())
+	defer ticker.Stop()
+	for {
+		head, err := m.client.BlockNumber(ctx)
+		if err != nil && !isTransientRPCError(err) {
+			return fmt.Errorf("failed to get block number: %w", err)
+		}
+		if err == nil && head >= target {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// bumpFees raises both fee fields by the configured percentage, reporting false once the caps prevent a valid replacement
+func (m *TxManager) bumpFees(tipCap, feeCap *big.Int) (*big.Int, *big.Int, bool) {
+	bump := m.cfg.PriceBumpPercent
+	if bump < 10 {
+		// Nodes reject replacements that raise fees by less than 10%
+		bump = 10
+	}
+	raise := func(v *big.Int) *big.Int {
+		out := new(big.Int).Mul(v, big.NewInt(100+bump))
+		out.Div(out, big.NewInt(100))
+		if out.Cmp(v) <= 0 {
+			out.Add(v, big.NewInt(1))
+		}
+		return out
+	}
+
+	newTip := raise(tipCap)
+	newFee := raise(feeCap)
+	if m.cfg.MaxPriorityFeePerGas != nil && newTip.Cmp(m.cfg.MaxPriorityFeePerGas) > 0 {
+		return nil, nil, false
+	}
+	if m.cfg.MaxFeePerGas != nil && newFee.Cmp(m.cfg.MaxFeePerGas) > 0 {
+		return nil, nil, false
+	}
+	return newTip, newFee, true
+}
+
+// withRetry runs fn, retrying with exponential backoff while it fails with a transient RPC error
+func (m *TxManager) withRetry(ctx context.Context, what string, fn func() error) error {
+	backoff := m.cfg.RetryBackoff
+	if backoff <= 0 {
+		backoff = 500 * time.Millisecond
+	}
+	var err error
+	for attempt := 0; ; attempt++ {
+		err = fn()
+		if err == nil || !isTransientRPCError(err) || attempt >= m.cfg.MaxRetries {
+			return err
+		}
+		m.logger.Debug("Transient error during %s (attempt %d/%d): %v", what, attempt+1, m.cfg.MaxRetries, err)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+func (m *TxManager) pollInterval() time.Duration {
+	if m.cfg.PollInterval <= 0 {
+		return 500 * time.Millisecond
+	}
+	return m.cfg.PollInterval
+}
+
+// isTransientRPCError reports whether an RPC failure is worth retrying
+func isTransientRPCError(err error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, context.Canceled) {
+		return false
+	}
+
+	var httpErr rpc.HTTPError
+	if errors.As(err, &httpErr) {
+		return httpErr.StatusCode == 429 || httpErr.StatusCode >= 500
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+
+	msg := strings.ToLower(err.Error())
+	for _, s := range []string{
+		"connection refused",
+		"connection reset",
+		"broken pipe",
+		"eof",
+		"i/o timeout",
+		"too many requests",
+		"rate limit",
+		"header not found",
+		"service unavailable",
+		"bad gateway",
+	} {
+		if strings.Contains(msg, s) {
+			return true
+		}
+	}
+	return false
+}
+
+func capAt(v, limit *big.Int) *big.Int {
+	if limit != nil && v.Cmp(limit) > 0 {
+		return new(big.Int).Set(limit)
+	}
+	return v
+}
+
+// FeeFields returns the fee fields for an eth_sendTransaction request, used for impersonated senders on anvil
+func (m *TxManager) FeeFields(ctx context.Context) (map[string]interface{}, error) {
+	tipCap, feeCap, dynamic, err := m.SuggestFees(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !dynamic {
+		return map[string]interface{}{"gasPrice": fmt.Sprintf("0x%x", feeCap)}, nil
+	}
+	return map[string]interface{}{
+		"maxFeePerGas":         fmt.Sprintf("0x%x", feeCap),
+		"maxPriorityFeePerGas": fmt.Sprintf("0x%x", tipCap),
+	}, nil
+}
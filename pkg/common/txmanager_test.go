@@ -0,0 +1,172 @@
+package common
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/Layr-Labs/devkit-cli/pkg/common/logger"
+	"github.com/Layr-Labs/devkit-cli/pkg/common/signer"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTxConfigToTxManagerConfig(t *testing.T) {
+	// Nil config yields the defaults
+	var nilCfg *TxConfig
+	cfg, err := nilCfg.TxManagerConfig()
+	require.NoError(t, err)
+	require.Equal(t, DefaultTxManagerConfig(), cfg)
+
+	cfg, err = (&TxConfig{
+		MaxFeePerGasGwei:         50,
+		MaxPriorityFeePerGasGwei: 1.5,
+		Confirmations:            3,
+		ResubmitAfter:            "10s",
+		MaxResubmits:             2,
+		MaxRetries:               7,
+		TraceReverts:             true,
+	}).TxManagerConfig()
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(50_000_000_000), cfg.MaxFeePerGas)
+	require.Equal(t, big.NewInt(1_500_000_000), cfg.MaxPriorityFeePerGas)
+	require.Equal(t, uint64(3), cfg.Confirmations)
+	require.Equal(t, 10*time.Second, cfg.ResubmitAfter)
+	require.Equal(t, 2, cfg.MaxResubmits)
+	require.Equal(t, 7, cfg.MaxRetries)
+	require.True(t, cfg.TraceReverts)
+
+	_, err = (&TxConfig{ResubmitAfter: "soon"}).TxManagerConfig()
+	require.ErrorContains(t, err, "invalid resubmit_after")
+
+	_, err = (&TxConfig{MaxFeePerGasGwei: 1, MaxPriorityFeePerGasGwei: 2}).TxManagerConfig()
+	require.ErrorContains(t, err, "exceeds max_fee_per_gas_gwei")
+
+	_, err = (&TxConfig{MaxFeePerGasGwei: -1}).TxManagerConfig()
+	require.Error(t, err)
+}
+
+func TestNonceTracker(t *testing.T) {
+	tracker := &nonceTracker{nonces: make(map[string]uint64)}
+	chainID := big.NewInt(31337)
+	addr := common.HexToAddress("0x1000000000000000000000000000000000000001")
+
+	// Starts from the pending nonce and increments locally
+	require.Equal(t, uint64(5), tracker.next(chainID, addr, 5))
+	require.Equal(t, uint64(6), tracker.next(chainID, addr, 5))
+
+	// Jumps forward when the node is ahead
+	require.Equal(t, uint64(10), tracker.next(chainID, addr, 10))
+
+	// Other chains are tracked separately
+	require.Equal(t, uint64(0), tracker.next(big.NewInt(1), addr, 0))
+
+	tracker.reset(chainID, addr)
+	require.Equal(t, uint64(3), tracker.next(chainID, addr, 3))
+}
+
+func TestBumpFees(t *testing.T) {
+	m := &TxManager{cfg: DefaultTxManagerConfig()}
+
+	tip, fee, ok := m.bumpFees(big.NewInt(100), big.NewInt(1000))
+	require.True(t, ok)
+	require.Equal(t, big.NewInt(112), tip)
+	require.Equal(t, big.NewInt(1120), fee)
+
+	// Bumps below the 10% replacement minimum are raised
+	m.cfg.PriceBumpPercent = 1
+	tip, _, ok = m.bumpFees(big.NewInt(100), big.NewInt(1000))
+	require.True(t, ok)
+	require.Equal(t, big.NewInt(110), tip)
+
+	// Caps stop further replacements
+	m.cfg.MaxFeePerGas = big.NewInt(1050)
+	_, _, ok = m.bumpFees(big.NewInt(100), big.NewInt(1000))
+	require.False(t, ok)
+}
+
+func TestIsTransientRPCError(t *testing.T) {
+	require.False(t, isTransientRPCError(nil))
+	require.False(t, isTransientRPCError(context.Canceled))
+	require.False(t, isTransientRPCError(errors.New("execution reverted")))
+	require.False(t, isTransientRPCError(rpc.HTTPError{StatusCode: 400}))
+
+	require.True(t, isTransientRPCError(rpc.HTTPError{StatusCode: 429}))
+	require.True(t, isTransientRPCError(fmt.Errorf("wrapped: %w", rpc.HTTPError{StatusCode: 503})))
+	require.True(t, isTransientRPCError(errors.New("dial tcp: connection refused")))
+}
+
+func TestCapAt(t *testing.T) {
+	require.Equal(t, big.NewInt(5), capAt(big.NewInt(5), nil))
+	require.Equal(t, big.NewInt(5), capAt(big.NewInt(5), big.NewInt(10)))
+	require.Equal(t, big.NewInt(10), capAt(big.NewInt(15), big.NewInt(10)))
+}
+
+// stuckNode is an eth namespace that accepts every transaction and never mines any
+type stuckNode struct {
+	sent []common.Hash
+}
+
+func (n *stuckNode) GetBlockByNumber(_ string, _ bool) (*types.Header, error) {
+	return &types.Header{Number: big.NewInt(1), Difficulty: big.NewInt(0), BaseFee: big.NewInt(1000)}, nil
+}
+
+func (n *stuckNode) MaxPriorityFeePerGas() (*hexutil.Big, error) {
+	return (*hexutil.Big)(big.NewInt(100)), nil
+}
+
+func (n *stuckNode) GetTransactionCount(_ common.Address, _ string) (hexutil.Uint64, error) {
+	return 0, nil
+}
+
+func (n *stuckNode) SendRawTransaction(data hexutil.Bytes) (common.Hash, error) {
+	tx := new(types.Transaction)
+	if err := tx.UnmarshalBinary(data); err != nil {
+		return common.Hash{}, err
+	}
+	n.sent = append(n.sent, tx.Hash())
+	return tx.Hash(), nil
+}
+
+func (n *stuckNode) GetTransactionReceipt(_ common.Hash) (*types.Receipt, error) {
+	return nil, nil
+}
+
+func TestSendAndWaitGivesUpAfterResubmits(t *testing.T) {
+	node := &stuckNode{}
+	server := rpc.NewServer()
+	require.NoError(t, server.RegisterName("eth", node))
+	client := ethclient.NewClient(rpc.DialInProc(server))
+	defer client.Close()
+
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	cfg := DefaultTxManagerConfig()
+	cfg.ResubmitAfter = 20 * time.Millisecond
+	cfg.PollInterval = 5 * time.Millisecond
+	cfg.MaxResubmits = 2
+	m := NewTxManager(client, big.NewInt(31337), cfg, logger.NewNoopLogger())
+
+	to := common.HexToAddress("0x1000000000000000000000000000000000000001")
+	template := types.NewTx(&types.DynamicFeeTx{To: &to, Gas: 21000})
+	_, _, err = m.SendAndWait(context.Background(), signer.NewPrivateKeySignerFromKey(key), template)
+	require.ErrorContains(t, err, "not mined after 2 replacements")
+	require.Len(t, node.sent, 3)
+	require.ErrorContains(t, err, node.sent[2].Hex())
+
+	// Fee caps stop replacements early, the original gets one more window
+	node.sent = nil
+	cfg.MaxFeePerGas = big.NewInt(2100)
+	m = NewTxManager(client, big.NewInt(31337), cfg, logger.NewNoopLogger())
+	_, _, err = m.SendAndWait(context.Background(), signer.NewPrivateKeySignerFromKey(key), template)
+	require.ErrorContains(t, err, "not mined after 0 replacements")
+	require.Len(t, node.sent, 1)
+}
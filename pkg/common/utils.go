@@ -27,8 +27,10 @@ type loggerContextKey struct{}
 // progressTrackerContextKey is used to store the progress tracker in the context
 type progressTrackerContextKey struct{}
 
-// RexExp to match semver strings
-var semverRegex = regexp.MustCompile(`^v?\d+\.\d+\.\d+$`)
+// RexExp to match SemVer 2.0 strings, with an optional v prefix, prerelease and build metadata
+var semverRegex = regexp.MustCompile(`^v?(0|[1-9]\d*)\.(0|[1-9]\d*)\.(0|[1-9]\d*)` +
+	`(?:-((?:0|[1-9]\d*|\d*[a-zA-Z-][0-9a-zA-Z-]*)(?:\.(?:0|[1-9]\d*|\d*[a-zA-Z-][0-9a-zA-Z-]*))*))?` +
+	`(?:\+([0-9a-zA-Z-]+(?:\.[0-9a-zA-Z-]+)*))?$`)
 
 // IsVerboseEnabled checks if either the CLI --verbose flag is set,
 // or config.yaml has [log] level = "debug"
@@ -229,9 +231,11 @@ func IsSemver(s string) bool {
 	return semverRegex.MatchString(s)
 }
 
-// ParseVersion converts version string like "0.0.5" to comparable integers
+// ParseVersion converts version string like "0.0.5" to comparable integers. The prerelease and build
+// metadata of a full semver string are ignored.
 func ParseVersion(v string) (major, minor, patch int, err error) {
-	parts := strings.Split(strings.TrimPrefix(v, "v"), ".")
+	core, _, _ := splitSemver(v)
+	parts := strings.Split(core, ".")
 	if len(parts) != 3 {
 		return 0, 0, 0, fmt.Errorf("invalid version format: %s", v)
 	}
@@ -254,7 +258,20 @@ func ParseVersion(v string) (major, minor, patch int, err error) {
 	return major, minor, patch, nil
 }
 
-// CompareVersions returns true if v1 > v2
+// splitSemver splits a semver string into its core version, prerelease and build metadata
+func splitSemver(v string) (core, prerelease, build string) {
+	core = strings.TrimPrefix(v, "v")
+	if i := strings.IndexByte(core, '+'); i >= 0 {
+		core, build = core[:i], core[i+1:]
+	}
+	if i := strings.IndexByte(core, '-'); i >= 0 {
+		core, prerelease = core[:i], core[i+1:]
+	}
+	return core, prerelease, build
+}
+
+// CompareVersions returns true if v1 > v2, following semver precedence: a prerelease is lower than its
+// release and build metadata is ignored
 func CompareVersions(v1, v2 string) (bool, error) {
 	major1, minor1, patch1, err := ParseVersion(v1)
 	if err != nil {
@@ -266,19 +283,83 @@ func CompareVersions(v1, v2 string) (bool, error) {
 		return false, fmt.Errorf("parse version %s: %w", v2, err)
 	}
 
-	if major1 > major2 {
-		return true, nil
+	if major1 != major2 {
+		return major1 > major2, nil
 	}
-	if major1 < major2 {
-		return false, nil
+	if minor1 != minor2 {
+		return minor1 > minor2, nil
 	}
+	if patch1 != patch2 {
+		return patch1 > patch2, nil
+	}
+	_, prerelease1, _ := splitSemver(v1)
+	_, prerelease2, _ := splitSemver(v2)
+	return comparePrereleases(prerelease1, prerelease2) > 0, nil
+}
 
-	if minor1 > minor2 {
-		return true, nil
+// comparePrereleases orders two prereleases of the same version, an empty prerelease being the release itself.
+// Numeric identifiers compare as numbers and are lower than alphanumeric ones, which compare as strings.
+func comparePrereleases(a, b string) int {
+	switch {
+	case a == b:
+		return 0
+	case a == "":
+		return 1
+	case b == "":
+		return -1
 	}
-	if minor1 < minor2 {
-		return false, nil
+	partsA, partsB := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(partsA) && i < len(partsB); i++ {
+		numA, errA := strconv.ParseUint(partsA[i], 10, 64)
+		numB, errB := strconv.ParseUint(partsB[i], 10, 64)
+		switch {
+		case errA == nil && errB == nil:
+			if numA != numB {
+				if numA > numB {
+					return 1
+				}
+				return -1
+			}
+		case errA == nil:
+			return -1
+		case errB == nil:
+			return 1
+		default:
+			if c := strings.Compare(partsA[i], partsB[i]); c != 0 {
+				return c
+			}
+		}
 	}
+	return len(partsA) - len(partsB)
+}
 
-	return patch1 > patch2, nil
+// BumpVersion increments the major, minor or patch part of a semver string, resetting the parts after it.
+// A prerelease is bumped to its release when that is the part being bumped, e.g. 2.0.0-rc.1 bumps to 2.0.0
+// for major and 1.4.1-rc.1 to 1.4.1 for patch. Build metadata is dropped.
+func BumpVersion(v, part string) (string, error) {
+	major, minor, patch, err := ParseVersion(v)
+	if err != nil {
+		return "", err
+	}
+	_, pre, _ := splitSemver(v)
+	prerelease := pre != ""
+	switch part {
+	case "major":
+		if !prerelease || minor != 0 || patch != 0 {
+			major++
+		}
+		minor, patch = 0, 0
+	case "minor":
+		if !prerelease || patch != 0 {
+			minor++
+		}
+		patch = 0
+	case "patch":
+		if !prerelease {
+			patch++
+		}
+	default:
+		return "", fmt.Errorf("invalid version part %q, expected major, minor or patch", part)
+	}
+	return fmt.Sprintf("%d.%d.%d", major, minor, patch), nil
 }
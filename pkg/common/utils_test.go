@@ -138,3 +138,71 @@ func TestParseETHAmount(t *testing.T) {
 		})
 	}
 }
+
+func TestBumpVersion(t *testing.T) {
+	tests := []struct {
+		version  string
+		part     string
+		expected string
+		wantErr  bool
+	}{
+		{version: "1.4.2", part: "patch", expected: "1.4.3"},
+		{version: "1.4.2", part: "minor", expected: "1.5.0"},
+		{version: "v1.4.2", part: "major", expected: "2.0.0"},
+		{version: "1.4.2", part: "build", wantErr: true},
+		{version: "1.4", part: "patch", wantErr: true},
+		{version: "1.4.1-rc.1", part: "patch", expected: "1.4.1"},
+		{version: "1.5.0-rc.1", part: "minor", expected: "1.5.0"},
+		{version: "1.5.1-rc.1", part: "minor", expected: "1.6.0"},
+		{version: "2.0.0-alpha+build.7", part: "major", expected: "2.0.0"},
+		{version: "1.4.2+build.7", part: "patch", expected: "1.4.3"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.version+"/"+tt.part, func(t *testing.T) {
+			result, err := BumpVersion(tt.version, tt.part)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("BumpVersion(%q, %q) expected error, got %q", tt.version, tt.part, result)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("BumpVersion(%q, %q) unexpected error: %v", tt.version, tt.part, err)
+			}
+			if result != tt.expected {
+				t.Errorf("BumpVersion(%q, %q) = %q, expected %q", tt.version, tt.part, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestIsSemver(t *testing.T) {
+	for _, v := range []string{"1.4.2", "v1.4.2", "1.4.2-rc.1", "1.4.2-alpha.beta-1", "1.4.2+build.7", "1.4.2-rc.1+sha.5114f85"} {
+		if !IsSemver(v) {
+			t.Errorf("IsSemver(%q) = false, expected true", v)
+		}
+	}
+	for _, v := range []string{"1.4", "01.4.2", "1.4.2-", "1.4.2-rc..1", "1.4.2-01", "1.4.2+", "next"} {
+		if IsSemver(v) {
+			t.Errorf("IsSemver(%q) = true, expected false", v)
+		}
+	}
+}
+
+func TestCompareVersions(t *testing.T) {
+	// Ordered by increasing precedence, as in the SemVer 2.0 spec
+	ordered := []string{"1.0.0-alpha", "1.0.0-alpha.1", "1.0.0-alpha.beta", "1.0.0-beta", "1.0.0-beta.2", "1.0.0-beta.11", "1.0.0-rc.1", "1.0.0", "1.0.1", "1.1.0", "2.0.0"}
+	for i := 1; i < len(ordered); i++ {
+		if greater, err := CompareVersions(ordered[i], ordered[i-1]); err != nil || !greater {
+			t.Errorf("CompareVersions(%q, %q) = %v, %v, expected true", ordered[i], ordered[i-1], greater, err)
+		}
+		if greater, err := CompareVersions(ordered[i-1], ordered[i]); err != nil || greater {
+			t.Errorf("CompareVersions(%q, %q) = %v, %v, expected false", ordered[i-1], ordered[i], greater, err)
+		}
+	}
+	// Build metadata has no precedence
+	if greater, err := CompareVersions("1.0.0+build.2", "1.0.0+build.1"); err != nil || greater {
+		t.Errorf("expected build metadata to be ignored, got %v, %v", greater, err)
+	}
+}
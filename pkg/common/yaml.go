@@ -20,6 +20,7 @@ type HandlerFunc func(node *yaml.Node, last bool, val string) (*yaml.Node, error
 var (
 	idxRe  = regexp.MustCompile(`^(\w+)\[(\d+)\]$`)
 	filtRe = regexp.MustCompile(`^(\w+)\[([^=]+)=([^\]]+)\]$`)
+	numRe  = regexp.MustCompile(`^\d+$`)
 )
 
 // LoadYAML reads a YAML file from the given path and unmarshals it into a *yaml.Node
@@ -86,14 +87,23 @@ func Normalize(i interface{}) interface{} {
 
 // WriteYAML encodes a *yaml.Node to YAML and writes it to the specified file path
 func WriteYAML(path string, node *yaml.Node) error {
+	data, err := EncodeYAML(node)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// EncodeYAML returns node as WriteYAML writes it
+func EncodeYAML(node *yaml.Node) ([]byte, error) {
 	buf := &bytes.Buffer{}
 	enc := yaml.NewEncoder(buf)
 	enc.SetIndent(2)
 	if err := enc.Encode(node); err != nil {
-		return fmt.Errorf("encode yaml: %w", err)
+		return nil, fmt.Errorf("encode yaml: %w", err)
 	}
 	enc.Close()
-	return os.WriteFile(path, buf.Bytes(), 0644)
+	return buf.Bytes(), nil
 }
 
 // WriteMap takes a map[string]interface{} and writes it back to a file
@@ -362,6 +372,87 @@ func WriteToPath(root *yaml.Node, path []string, val string) (*yaml.Node, error)
 	return root, nil
 }
 
+// SplitPath splits a dot-delimited path into segments, keeping dots inside brackets, e.g. in [name=foo.bar]
+func SplitPath(path string) []string {
+	var segments []string
+	start, depth := 0, 0
+	for i, r := range path {
+		switch r {
+		case '[':
+			depth++
+		case ']':
+			if depth > 0 {
+				depth--
+			}
+		case '.':
+			if depth == 0 {
+				segments = append(segments, path[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(segments, path[start:])
+}
+
+// ReadFromPath returns the node at a dot-delimited path, accepting the same segments as WriteToPath:
+// keys, numeric indices (operators.0), bracket indices (operators[0]) and filters (operators[address=0x...]).
+func ReadFromPath(root *yaml.Node, path []string) (*yaml.Node, error) {
+	workingNode := root
+	for i, seg := range path {
+		at := strings.Join(path[:i+1], ".")
+
+		switch {
+		case idxRe.MatchString(seg):
+			m := idxRe.FindStringSubmatch(seg)
+			idx, _ := strconv.Atoi(m[2])
+			seq := GetChildByKey(workingNode, m[1])
+			if seq == nil || seq.Kind != yaml.SequenceNode {
+				return nil, fmt.Errorf("%s: not a sequence", at)
+			}
+			if idx >= len(seq.Content) {
+				return nil, fmt.Errorf("%s: index out of range: %d", at, idx)
+			}
+			workingNode = seq.Content[idx]
+
+		case workingNode.Kind == yaml.SequenceNode && numRe.MatchString(seg):
+			idx, _ := strconv.Atoi(seg)
+			if idx >= len(workingNode.Content) {
+				return nil, fmt.Errorf("%s: index out of range: %d", at, idx)
+			}
+			workingNode = workingNode.Content[idx]
+
+		case filtRe.MatchString(seg):
+			m := filtRe.FindStringSubmatch(seg)
+			seq := GetChildByKey(workingNode, m[1])
+			if seq == nil || seq.Kind != yaml.SequenceNode {
+				return nil, fmt.Errorf("%s: not a sequence", at)
+			}
+			var match *yaml.Node
+			for _, item := range seq.Content {
+				if child := GetChildByKey(item, m[2]); child != nil && child.Value == m[3] {
+					match = item
+					break
+				}
+			}
+			if match == nil {
+				return nil, fmt.Errorf("%s: no match for %s=%s", at, m[2], m[3])
+			}
+			workingNode = match
+
+		default:
+			if workingNode.Kind != yaml.MappingNode {
+				return nil, fmt.Errorf("%s: not a mapping", at)
+			}
+			child := GetChildByKey(workingNode, seg)
+			if child == nil {
+				return nil, fmt.Errorf("%s: not found", at)
+			}
+			workingNode = child
+		}
+	}
+	return workingNode, nil
+}
+
 // sanitizeValue trims quotes from user input
 func sanitizeValue(val string) string {
 	return strings.Trim(val, `"'`)
@@ -401,7 +492,7 @@ func bracketHandler(idx int) HandlerFunc {
 
 // tryNumericIndex detects .0 on a sequence
 func tryNumericIndex(root *yaml.Node, seg string) (HandlerFunc, *yaml.Node) {
-	if root.Kind == yaml.SequenceNode && regexp.MustCompile(`^\d+$`).MatchString(seg) {
+	if root.Kind == yaml.SequenceNode && numRe.MatchString(seg) {
 		idx, _ := strconv.Atoi(seg)
 		return numericHandler(idx), root
 	}
@@ -3,6 +3,7 @@ package common
 import (
 	"os"
 	"reflect"
+	"strings"
 	"testing"
 
 	"gopkg.in/yaml.v3"
@@ -326,3 +327,54 @@ func TestWriteToPath_MappingCreation(t *testing.T) {
 		t.Errorf("expected foo.bar=baz, got %v", m)
 	}
 }
+
+func TestReadFromPath(t *testing.T) {
+	var doc yaml.Node
+	src := `avs:
+  address: "0xabc"
+deployed_contracts:
+  - name: AVSRegistrar
+    address: "0x1"
+  - name: TaskMailbox
+    address: "0x2"
+  - name: mailbox.v2
+    address: "0x3"
+operators:
+  - address: "0xop"
+`
+	if err := yaml.Unmarshal([]byte(src), &doc); err != nil {
+		t.Fatal(err)
+	}
+	root := doc.Content[0]
+
+	tests := []struct {
+		path, want, wantErr string
+	}{
+		{path: "avs.address", want: "0xabc"},
+		{path: "deployed_contracts[name=TaskMailbox].address", want: "0x2"},
+		{path: "deployed_contracts[name=mailbox.v2].address", want: "0x3"},
+		{path: "deployed_contracts[0].name", want: "AVSRegistrar"},
+		{path: "deployed_contracts.1.name", want: "TaskMailbox"},
+		{path: "operators.0.address", want: "0xop"},
+		{path: "avs.missing", wantErr: "avs.missing: not found"},
+		{path: "deployed_contracts[name=Nope].address", wantErr: "no match for name=Nope"},
+		{path: "deployed_contracts[5]", wantErr: "index out of range: 5"},
+		{path: "avs.address.more", wantErr: "avs.address.more: not a mapping"},
+	}
+	for _, tt := range tests {
+		node, err := ReadFromPath(root, SplitPath(tt.path))
+		if tt.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ReadFromPath(%q) error = %v, want %q", tt.path, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ReadFromPath(%q) failed: %v", tt.path, err)
+			continue
+		}
+		if node.Value != tt.want {
+			t.Errorf("ReadFromPath(%q) = %q, want %q", tt.path, node.Value, tt.want)
+		}
+	}
+}
@@ -4,11 +4,12 @@ import (
 	"bytes"
 	"errors"
 	"fmt"
+	"os"
 	"strconv"
 	"strings"
 
 	"github.com/Layr-Labs/devkit-cli/pkg/common"
-	"github.com/Layr-Labs/devkit-cli/pkg/common/iface"
+	"github.com/pmezard/go-difflib/difflib"
 	"gopkg.in/yaml.v3"
 )
 
@@ -113,40 +114,152 @@ func (e *PatchEngine) Apply() error {
 	return nil
 }
 
-// Run all migrations after current version upto latestVersion according to migrationChain
-func MigrateYaml(logger iface.Logger, path string, latestVersion string, migrationChain []MigrationStep) error {
+// PendingMigration holds a file before and after the migrations it is due
+type PendingMigration struct {
+	Path   string
+	From   string
+	To     string
+	Before []byte
+	After  []byte
+}
+
+// PlanYaml runs all migrations after the current version of path upto latestVersion in memory, without writing.
+// It returns ErrAlreadyUpToDate when there is nothing to migrate.
+func PlanYaml(path string, latestVersion string, migrationChain []MigrationStep) (*PendingMigration, error) {
+	return planYaml(path, "", latestVersion, migrationChain, false)
+}
+
+// PlanOverlayYaml plans the migration of a file layered over another one, such as a context that extends another or
+// a local overlay. Such files only hold the settings they override, so the migrations may rewrite the keys they
+// already contain but the defaults they insert are dropped. from is the version to migrate from, read from the file
+// when empty, as overlays without a version of their own follow the file they are layered over.
+func PlanOverlayYaml(path, from, latestVersion string, migrationChain []MigrationStep) (*PendingMigration, error) {
+	return planYaml(path, from, latestVersion, migrationChain, true)
+}
+
+func planYaml(path, from, latestVersion string, migrationChain []MigrationStep, overlay bool) (*PendingMigration, error) {
+	before, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("load error %s: %v", path, err)
+	}
 
 	// Load as YAML AST
 	userNode, err := common.LoadYAML(path)
 	if err != nil {
-		return fmt.Errorf("load error %s: %v", path, err)
+		return nil, fmt.Errorf("load error %s: %v", path, err)
+	}
+
+	// An empty overlay has nothing to migrate
+	if overlay && len(userNode.Content) == 0 {
+		return nil, ErrAlreadyUpToDate
 	}
 
 	// Extract version scalar
-	verNode := ResolveNode(userNode, []string{"version"})
-	if verNode == nil {
-		return fmt.Errorf("no version field %s", path)
+	if from == "" {
+		verNode := ResolveNode(userNode, []string{"version"})
+		if verNode == nil {
+			return nil, fmt.Errorf("no version field %s", path)
+		}
+		from = verNode.Value
 	}
-	from := verNode.Value
 	to := latestVersion
-
-	// Continue and don't say anything if the user version is latest
 	if from == to {
-		return ErrAlreadyUpToDate
+		return nil, ErrAlreadyUpToDate
 	}
-	logger.Info("Migrating %s v%s -> v%s", path, from, to)
 
 	// Perform node-based migration
+	original := CloneNode(userNode)
 	migrated, err := MigrateNode(userNode, from, to, migrationChain)
 	if err != nil {
-		return fmt.Errorf("migration failed %s: %v", path, err)
+		return nil, fmt.Errorf("migration failed %s: %v", path, err)
+	}
+	if overlay {
+		defaults := &yaml.Node{}
+		if err := yaml.Unmarshal(migrationChain[len(migrationChain)-1].NewYAML, defaults); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal default for %s: %w", to, err)
+		}
+		pruneInsertedDefaults(migrated, original, defaults)
+	}
+	after, err := common.EncodeYAML(migrated)
+	if err != nil {
+		return nil, fmt.Errorf("migration failed %s: %v", path, err)
 	}
+	if overlay && bytes.Equal(after, before) {
+		return nil, ErrAlreadyUpToDate
+	}
+
+	return &PendingMigration{Path: path, From: from, To: to, Before: before, After: after}, nil
+}
 
-	// Write AST back to disk
-	if err := common.WriteYAML(path, migrated); err != nil {
-		return fmt.Errorf("failed to write %s: %v", path, err)
+// pruneInsertedDefaults removes the keys a migration added to migrated that are missing from original and hold
+// the default value of defaults. Keys the file had, and keys a migration renamed along with their values, stay.
+func pruneInsertedDefaults(migrated, original, defaults *yaml.Node) {
+	if migrated.Kind == yaml.DocumentNode && len(migrated.Content) > 0 {
+		migrated = migrated.Content[0]
+	}
+	if original != nil && original.Kind == yaml.DocumentNode && len(original.Content) > 0 {
+		original = original.Content[0]
+	}
+	if defaults != nil && defaults.Kind == yaml.DocumentNode && len(defaults.Content) > 0 {
+		defaults = defaults.Content[0]
 	}
+	if migrated.Kind != yaml.MappingNode {
+		return
+	}
+
+	content := migrated.Content[:0]
+	for i := 0; i+1 < len(migrated.Content); i += 2 {
+		key, value := migrated.Content[i], migrated.Content[i+1]
+		var had, def *yaml.Node
+		if original != nil && original.Kind == yaml.MappingNode {
+			had = ResolveNode(original, []string{key.Value})
+		}
+		if defaults != nil && defaults.Kind == yaml.MappingNode {
+			def = ResolveNode(defaults, []string{key.Value})
+		}
+		if value.Kind == yaml.MappingNode && (had == nil || had.Kind == yaml.MappingNode) {
+			pruneInsertedDefaults(value, had, def)
+			// A mapping the migration created only for defaults is dropped with them
+			if had == nil && len(value.Content) == 0 {
+				continue
+			}
+		} else if had == nil && def != nil && sameContent(value, def) {
+			continue
+		}
+		content = append(content, key, value)
+	}
+	migrated.Content = content
+}
+
+// sameContent compares the values of two nodes, ignoring style, comments and positions
+func sameContent(a, b *yaml.Node) bool {
+	if a.Kind != b.Kind || a.Value != b.Value || len(a.Content) != len(b.Content) {
+		return false
+	}
+	for i := range a.Content {
+		if !sameContent(a.Content[i], b.Content[i]) {
+			return false
+		}
+	}
+	return true
+}
 
+// Diff returns the pending changes as a unified diff
+func (p *PendingMigration) Diff() (string, error) {
+	return difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(string(p.Before)),
+		B:        difflib.SplitLines(string(p.After)),
+		FromFile: fmt.Sprintf("%s (v%s)", p.Path, p.From),
+		ToFile:   fmt.Sprintf("%s (v%s)", p.Path, p.To),
+		Context:  3,
+	})
+}
+
+// Write writes the migrated file over the original
+func (p *PendingMigration) Write() error {
+	if err := os.WriteFile(p.Path, p.After, 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %v", p.Path, err)
+	}
 	return nil
 }
 
@@ -2,6 +2,7 @@ package migration_test
 
 import (
 	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/Layr-Labs/devkit-cli/config/configs"
@@ -719,6 +720,97 @@ func TestAVSContextMigration_0_0_6_to_0_0_7(t *testing.T) {
 	})
 }
 
+// TestAVSContextMigration_0_0_7_to_0_0_8 tests the migration from version 0.0.7 to 0.0.8
+// which turns the single artifact component into a list of components
+func TestAVSContextMigration_0_0_7_to_0_0_8(t *testing.T) {
+	// Start from v0.0.7 with a built and released component
+	userYAML := strings.Replace(string(contexts.ContextYamls["0.0.7"]), `  artifact:
+    artifactId: ""
+    component: ""
+    digest: ""
+    registry: ""
+    version: ""`, `  artifact:
+    artifactId: "sha256:1111"
+    component: "hello-world-avs"
+    digest: "sha256:2222"
+    registry: "ghcr.io/acme/avs"
+    version: "3"
+    signing_key: "cosign.pub"
+    labels:
+      team: infra`, 1)
+
+	userNode := testNode(t, userYAML)
+
+	var migrationStep migration.MigrationStep
+	for _, step := range contexts.MigrationChain {
+		if step.From == "0.0.7" && step.To == "0.0.8" {
+			migrationStep = step
+			break
+		}
+	}
+	if migrationStep.Apply == nil {
+		t.Fatal("Could not find 0.0.7 -> 0.0.8 migration step")
+	}
+
+	migratedNode, err := migration.MigrateNode(userNode, "0.0.7", "0.0.8", []migration.MigrationStep{migrationStep})
+	if err != nil {
+		t.Fatalf("Migration failed: %v", err)
+	}
+
+	t.Run("version updated", func(t *testing.T) {
+		version := migration.ResolveNode(migratedNode, []string{"version"})
+		if version == nil || version.Value != "0.0.8" {
+			t.Errorf("Expected version to be updated to 0.0.8, got %v", version.Value)
+		}
+	})
+
+	t.Run("registry and version preserved", func(t *testing.T) {
+		registry := migration.ResolveNode(migratedNode, []string{"context", "artifact", "registry"})
+		if registry == nil || registry.Value != "ghcr.io/acme/avs" {
+			t.Errorf("Expected registry to be preserved, got %v", registry)
+		}
+		version := migration.ResolveNode(migratedNode, []string{"context", "artifact", "version"})
+		if version == nil || version.Value != "3" {
+			t.Errorf("Expected artifact version to be preserved, got %v", version)
+		}
+	})
+
+	t.Run("component moved to components list", func(t *testing.T) {
+		for key, expected := range map[string]string{"name": "hello-world-avs", "image": "hello-world-avs", "artifact_id": "sha256:1111", "digest": "sha256:2222"} {
+			node := migration.ResolveNode(migratedNode, []string{"context", "artifact", "components", "0", key})
+			if node == nil || node.Value != expected {
+				t.Errorf("Expected components[0].%s to be %q, got %v", key, expected, node)
+			}
+		}
+		if migration.ResolveNode(migratedNode, []string{"context", "artifact", "component"}) != nil {
+			t.Error("Expected the single component field to be removed")
+		}
+	})
+
+	t.Run("unknown artifact keys preserved", func(t *testing.T) {
+		signingKey := migration.ResolveNode(migratedNode, []string{"context", "artifact", "signing_key"})
+		if signingKey == nil || signingKey.Value != "cosign.pub" {
+			t.Errorf("Expected signing_key to be preserved, got %v", signingKey)
+		}
+		team := migration.ResolveNode(migratedNode, []string{"context", "artifact", "labels", "team"})
+		if team == nil || team.Value != "infra" {
+			t.Errorf("Expected labels.team to be preserved, got %v", team)
+		}
+	})
+
+	t.Run("unbuilt artifact has no components", func(t *testing.T) {
+		userNode := testNode(t, string(contexts.ContextYamls["0.0.7"]))
+		migratedNode, err := migration.MigrateNode(userNode, "0.0.7", "0.0.8", []migration.MigrationStep{migrationStep})
+		if err != nil {
+			t.Fatalf("Migration failed: %v", err)
+		}
+		components := migration.ResolveNode(migratedNode, []string{"context", "artifact", "components"})
+		if components == nil || components.Kind != yaml.SequenceNode || len(components.Content) != 0 {
+			t.Errorf("Expected an empty components list, got %v", components)
+		}
+	})
+}
+
 // TestAVSContextMigration_FullChain tests migrating through the entire chain from 0.0.1 to 0.0.6
 func TestAVSContextMigration_FullChain(t *testing.T) {
 	// Use the embedded v0.0.1 content as our starting point